@@ -15,7 +15,9 @@ import (
 	"github.com/Rasikrr/core/http"
 	"github.com/Rasikrr/core/interfaces"
 	"github.com/Rasikrr/core/log"
+	"github.com/Rasikrr/core/s3"
 	"github.com/Rasikrr/core/sentry"
+	"github.com/Rasikrr/core/telegram"
 	"github.com/Rasikrr/core/version"
 	"github.com/robfig/cron/v3"
 	"go.uber.org/multierr"
@@ -29,8 +31,15 @@ type App struct {
 	postgres          *postgres.Postgres
 	postgresTXManager *postgres.TXManager
 
-	httpServer *http.Server
-	grpcServer *coreGrpc.Server
+	s3 *s3.Client
+
+	telegramBot    telegram.Bot
+	telegramSender *telegram.Sender
+	telegramAlerts *telegram.AlertHandler
+
+	httpServer  *http.Server
+	grpcServer  *coreGrpc.Server
+	grpcClients map[string]*coreGrpc.Client
 
 	metricsServer *http.Server
 
@@ -83,15 +92,24 @@ func newAppWithConfig(ctx context.Context, cfg *config.Config) *App {
 	if err := app.initRedis(ctx); err != nil {
 		log.Fatalf(ctx, "failed to init redis: %v", err)
 	}
+	if err := app.initS3(ctx); err != nil {
+		log.Fatalf(ctx, "failed to init s3: %v", err)
+	}
 	if err := app.initGRPC(ctx); err != nil {
 		log.Fatalf(ctx, "failed to init grpc: %v", err)
 	}
+	if err := app.initGRPCClients(ctx); err != nil {
+		log.Fatalf(ctx, "failed to init grpc clients: %v", err)
+	}
 	if err := app.initHTTP(ctx); err != nil {
 		log.Fatalf(ctx, "failed to init http: %v", err)
 	}
 	if err := app.initNats(ctx); err != nil {
 		log.Fatalf(ctx, "failed to init nats: %v", err)
 	}
+	if err := app.initTelegram(ctx); err != nil {
+		log.Fatalf(ctx, "failed to init telegram: %v", err)
+	}
 	return app
 }
 
@@ -110,6 +128,7 @@ func (a *App) Start(ctx context.Context) error {
 	}
 
 	a.initSubscribers(ctx)
+	a.watchLogLevelSignals(ctx)
 	stopChan := make(chan struct{})
 	go a.gracefulShutdown(ctx, stopChan)
 	if err := a.start(ctx); err != nil {
@@ -168,13 +187,17 @@ func (a *App) start(ctx context.Context) error {
 	return multiErr
 }
 
+// Close закрывает все компоненты, даже если часть из них вернула ошибку, и возвращает ошибки вместе
 func (a *App) Close(ctx context.Context) error {
+	var multiErr error
 	for _, c := range a.closers.closers {
-		if err := c.Close(ctx); err != nil {
-			return err
-		}
+		multiErr = multierr.Append(multiErr, c.Close(ctx))
 	}
-	return nil
+	// Алерты и выходы логов закрываются последними, чтобы сохранить и ошибки остановки остальных компонентов
+	if a.telegramAlerts != nil {
+		multiErr = multierr.Append(multiErr, a.telegramAlerts.Close(ctx))
+	}
+	return multierr.Append(multiErr, log.Close(ctx))
 }
 
 func (a *App) gracefulShutdown(ctx context.Context, stopChan chan struct{}) {
@@ -201,6 +224,15 @@ func (a *App) GrpcServer() *coreGrpc.Server {
 	return a.grpcServer
 }
 
+// GRPCClient возвращает клиент, объявленный в секции grpc_clients конфигурации
+func (a *App) GRPCClient(name string) *coreGrpc.Client {
+	client, ok := a.grpcClients[name]
+	if !ok {
+		log.Fatalf(context.Background(), "grpc client %q is not declared in grpc_clients, please check your config", name)
+	}
+	return client
+}
+
 func (a *App) Postgres() *postgres.Postgres {
 	if a.postgres == nil {
 		log.Fatalf(context.Background(), "postgres is not initialized or not required. please check your config")
@@ -229,6 +261,40 @@ func (a *App) Redis() *redis.Client {
 	return a.redis
 }
 
+// S3 возвращает клиент основного бакета. Именованные бакеты из s3.buckets доступны через S3Bucket.
+func (a *App) S3() *s3.Client {
+	if a.s3 == nil {
+		log.Fatalf(context.Background(), "s3 is not initialized or not required. please check your config")
+	}
+	return a.s3
+}
+
+// S3Bucket возвращает клиент бакета, объявленного в секции s3.buckets конфигурации
+func (a *App) S3Bucket(name string) *s3.Client {
+	client, err := a.S3().Bucket(name)
+	if err != nil {
+		log.Fatalf(context.Background(), "s3 bucket %q is not declared in s3.buckets, please check your config", name)
+	}
+	return client
+}
+
+// TelegramBot возвращает бота из секции telegram. Обработчики задаются через SetRouter до Start.
+func (a *App) TelegramBot() telegram.Bot {
+	if a.telegramBot == nil {
+		log.Fatalf(context.Background(), "telegram is not initialized or not required. please check your config")
+	}
+	return a.telegramBot
+}
+
+// TelegramSender возвращает Sender бота. Передайте его в telegram.NewTelegramClient через telegram.WithSender,
+// чтобы клиент с тем же токеном соблюдал общие лимиты.
+func (a *App) TelegramSender() *telegram.Sender {
+	if a.telegramSender == nil {
+		log.Fatalf(context.Background(), "telegram is not initialized or not required. please check your config")
+	}
+	return a.telegramSender
+}
+
 func (a *App) Config() *config.Config {
 	return a.config
 }
@@ -2,20 +2,24 @@ package application
 
 import (
 	"context"
+	"fmt"
 
 	coreGrpc "github.com/Rasikrr/core/grpc"
 	"github.com/Rasikrr/core/log"
 )
 
-// nolint: unparam
 func (a *App) initGRPC(ctx context.Context) error {
 	if !a.config.GRPC.Required {
 		return nil
 	}
 
-	a.grpcServer = coreGrpc.NewServer(
+	server, err := coreGrpc.NewServer(
 		a.Config().GRPC,
 	)
+	if err != nil {
+		return err
+	}
+	a.grpcServer = server
 
 	log.Info(ctx, "grpc initialized")
 
@@ -24,3 +28,38 @@ func (a *App) initGRPC(ctx context.Context) error {
 
 	return nil
 }
+
+func (a *App) initGRPCClients(ctx context.Context) error {
+	if len(a.Config().GRPCClients) == 0 {
+		return nil
+	}
+
+	a.grpcClients = make(map[string]*coreGrpc.Client, len(a.Config().GRPCClients))
+	for name, cfg := range a.Config().GRPCClients {
+		client, err := coreGrpc.NewClientFromConfig(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("grpc client %q: %w", name, err)
+		}
+		a.grpcClients[name] = client
+		a.closers.Add(client)
+	}
+
+	log.Info(ctx, "grpc clients initialized", log.Int("count", len(a.grpcClients)))
+
+	return nil
+}
+
+// WithGateway монтирует JSON транскодер gRPC сервисов на HTTP сервер под prefix, например "/v1".
+// prefix должен совпадать с началом путей из аннотаций google.api.http: URL передается в gateway без изменений.
+// Запросы уходят во встроенный gRPC сервер и проходят его интерцепторы.
+func (a *App) WithGateway(ctx context.Context, prefix string, registrars ...coreGrpc.GatewayRegistrar) error {
+	gateway, err := a.GrpcServer().NewGateway(ctx, registrars...)
+	if err != nil {
+		return fmt.Errorf("init grpc gateway: %w", err)
+	}
+	a.HTTPServer().Mount(prefix, gateway)
+
+	log.Info(ctx, "grpc gateway mounted", log.String("prefix", prefix))
+
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/Rasikrr/core/interfaces"
 	"github.com/Rasikrr/core/log"
+	"github.com/Rasikrr/core/recovery"
 	"github.com/robfig/cron/v3"
 )
 
@@ -48,7 +49,7 @@ func (jm *JobManager) Start(_ context.Context) error {
 	entryIDs := make([]cron.EntryID, 0, len(jm.jobs))
 
 	for _, job := range jm.jobs {
-		entryID, err := jm.cron.AddJob(job.Schedule(), job)
+		entryID, err := jm.cron.AddJob(job.Schedule(), recoverableJob{job: job})
 		if err != nil {
 			// Откатываем уже добавленные джобы
 			for _, id := range entryIDs {
@@ -104,3 +105,24 @@ func (jm *JobManager) Entries() []cron.Entry {
 func (jm *JobManager) JobsCount() int {
 	return len(jm.jobs)
 }
+
+// recoverableJob не дает панике в джобе уронить процесс и обрабатывает ее так же, как HTTP и gRPC
+type recoverableJob struct {
+	job interfaces.Job
+}
+
+func (j recoverableJob) Run() {
+	ctx := log.WithContextAttrs(context.Background(), log.String(log.KeyJob, j.job.Name()))
+	defer func() {
+		if r := recover(); r != nil {
+			recovery.Handle(ctx, recovery.ComponentCron, r, map[string]string{
+				"cron.job": j.job.Name(),
+			})
+		}
+	}()
+	if job, ok := j.job.(interfaces.ContextJob); ok {
+		job.RunContext(ctx)
+		return
+	}
+	j.job.Run()
+}
@@ -1,9 +1,46 @@
 package application
 
 import (
+	"fmt"
+	"log/slog"
+	"slices"
+
 	"github.com/Rasikrr/core/log"
+	"github.com/Rasikrr/core/telegram"
 )
 
 func (a *App) InitLogger() error {
-	return log.Init(a.Config().Logger)
+	var handlers []slog.Handler
+	if a.Config().Telegram.Alerts.Required {
+		if err := a.initTelegramAlerts(); err != nil {
+			return err
+		}
+		handlers = append(handlers, a.telegramAlerts)
+	}
+	return log.Init(a.loggerConfig(), handlers...)
+}
+
+// loggerConfig дополняет конфигурацию логов данными приложения: OTLP экспорт использует имя сервиса
+// и, если endpoint не задан, тот же коллектор, что и трейсы
+func (a *App) loggerConfig() log.Config {
+	cfg := a.Config().Logger
+	cfg.ServiceName = a.Config().AppName
+	cfg.Outputs = slices.Clone(cfg.Outputs)
+	for i := range cfg.Outputs {
+		if cfg.Outputs[i].Type == log.OutputOTLP && cfg.Outputs[i].OTLP.Endpoint == "" {
+			cfg.Outputs[i].OTLP.Endpoint = a.Config().Tracing.DSN
+		}
+	}
+	return cfg
+}
+
+func (a *App) initTelegramAlerts() error {
+	cfg := a.Config().Telegram
+	a.telegramSender = telegram.NewSender(cfg.Sender)
+	client, err := telegram.NewTelegramClient(cfg.Token, telegram.WithSender(a.telegramSender))
+	if err != nil {
+		return fmt.Errorf("init telegram alerts error: %w", err)
+	}
+	a.telegramAlerts = telegram.NewAlertHandler(client, cfg.Alerts, a.Config().AppName)
+	return nil
 }
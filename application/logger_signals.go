@@ -0,0 +1,39 @@
+//go:build unix
+
+package application
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Rasikrr/core/log"
+)
+
+// watchLogLevelSignals включает debug по SIGUSR1 на log.level_ttl и возвращает уровень из конфигурации по SIGUSR2
+func (a *App) watchLogLevelSignals(ctx context.Context) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGUSR1, syscall.SIGUSR2)
+
+	go func() {
+		defer signal.Stop(sigChan)
+		for {
+			select {
+			case sig := <-sigChan:
+				if sig == syscall.SIGUSR1 {
+					log.SetLevel(slog.LevelDebug, a.Config().Logger.LevelTTL)
+				} else {
+					log.ResetLevel()
+				}
+				log.Info(ctx, "log level changed by signal",
+					log.String("signal", sig.String()),
+					log.String("level", log.Level().String()),
+				)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
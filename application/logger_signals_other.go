@@ -0,0 +1,8 @@
+//go:build !unix
+
+package application
+
+import "context"
+
+// SIGUSR1 и SIGUSR2 есть только в unix, уровень меняется через log.LevelHandler
+func (a *App) watchLogLevelSignals(_ context.Context) {}
@@ -2,12 +2,15 @@ package application
 
 import (
 	"context"
+	"log/slog"
 
 	"github.com/Rasikrr/core/http"
 	"github.com/Rasikrr/core/log"
 	"github.com/Rasikrr/core/metrics"
 )
 
+const logLevelPath = "/debug/log/level"
+
 // nolint: unparam
 func (a *App) initMetrics(ctx context.Context) error {
 	metrics.Init(
@@ -15,8 +18,20 @@ func (a *App) initMetrics(ctx context.Context) error {
 		a.Config().Metrics.Namespace,
 		nil,
 	)
+	if !metrics.Enabled() && a.Config().Logger.Sampling.Mode != log.SamplingOff {
+		log.Warn(ctx, "metrics are disabled, log_dropped_total is not exported")
+	}
 	if metrics.Enabled() {
 		a.metricsServer = http.NewMetricsServer(ctx, a.Config().Metrics.Prometheus.Port)
+		// Внутренний порт метрик подходит и для служебной ручки уровня логирования
+		if endpoint := a.Config().Logger.LevelEndpoint; endpoint.Enabled {
+			a.metricsServer.Mount(logLevelPath, log.LevelHandler(endpoint.Token))
+		}
+		// log не может зависеть от metrics, поэтому счетчик отброшенных записей подключается здесь
+		logDropped := metrics.NewCounterVec("log", "dropped_total", "Log records dropped by sampling", []string{"level", "reason"}, nil)
+		log.SetDropHook(func(level slog.Level, reason string) {
+			logDropped.WithLabelValues(level.String(), reason).Inc()
+		})
 		a.starters.Add(a.metricsServer)
 		a.closers.Add(a.metricsServer)
 		log.Infof(ctx, "metrics server initialized")
@@ -0,0 +1,31 @@
+package application
+
+import (
+	"context"
+
+	"github.com/Rasikrr/core/log"
+	"github.com/Rasikrr/core/s3"
+)
+
+func (a *App) initS3(ctx context.Context) error {
+	if !a.Config().S3.Required {
+		return nil
+	}
+
+	var err error
+	a.s3, err = s3.NewClient(ctx, a.Config().S3)
+	if err != nil {
+		return err
+	}
+	if err = a.s3.HealthCheck(ctx); err != nil {
+		return err
+	}
+
+	if cfg := a.Config().S3.StaleUploads; cfg.Schedule != "" {
+		a.WithCronJobs(s3.NewStaleUploadsJob(a.s3, cfg))
+	}
+
+	log.Info(ctx, "s3 initialized", log.Int("buckets", len(a.Config().S3.Buckets)+1))
+
+	return nil
+}
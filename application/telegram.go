@@ -0,0 +1,41 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Rasikrr/core/log"
+	"github.com/Rasikrr/core/telegram"
+)
+
+func (a *App) initTelegram(ctx context.Context) error {
+	cfg := a.Config().Telegram
+	if !cfg.Required {
+		return nil
+	}
+
+	// Лимиты Telegram считаются на токен: клиенты этого токена, включая алерты, должны использовать тот же Sender
+	if a.telegramSender == nil {
+		a.telegramSender = telegram.NewSender(cfg.Sender)
+	}
+	bot, err := telegram.NewBotWithConfig(cfg, telegram.WithSender(a.telegramSender))
+	if err != nil {
+		return fmt.Errorf("init telegram bot error: %w", err)
+	}
+
+	if cfg.Webhook.Enabled() {
+		if a.httpServer == nil {
+			return errors.New("telegram webhook requires http server, please enable http in config")
+		}
+		a.httpServer.Mount(cfg.Webhook.RoutePath(), bot.WebhookHandler())
+	}
+	a.telegramBot = bot
+
+	log.Info(ctx, "telegram initialized", log.Bool("webhook", cfg.Webhook.Enabled()))
+
+	a.starters.Add(a.telegramBot)
+	a.closers.Add(a.telegramBot)
+
+	return nil
+}
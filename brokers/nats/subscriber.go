@@ -8,7 +8,7 @@ import (
 
 	"github.com/Rasikrr/core/interfaces"
 	"github.com/Rasikrr/core/log"
-	"github.com/Rasikrr/core/sentry"
+	"github.com/Rasikrr/core/recovery"
 	"github.com/nats-io/nats.go"
 )
 
@@ -94,29 +94,7 @@ func (s *subscriber) Subscribe(ctx context.Context, subject string, handler Subs
 					continue
 				}
 
-				metrics.recvTotal.WithLabelValues(subject).Inc()
-				if msg != nil && msg.Data != nil {
-					metrics.recvBytes.WithLabelValues(subject).Observe(float64(len(msg.Data)))
-				}
-
-				// Извлекаем trace context из заголовков сообщения
-				handlerCtx, span := extractTraceContext(ctx, msg, fmt.Sprintf("nats.handle %s", subject))
-				handlerCtx = setSentryHubAndScope(handlerCtx, msg, "")
-
-				// Обработка паник с отправкой в Sentry
-				defer sentry.RecoverWithContext(handlerCtx)
-
-				start := time.Now()
-				metrics.inflightReq.WithLabelValues(subject).Inc()
-
-				if err := handler.Handle(handlerCtx, msg); err != nil {
-					l.Error(handlerCtx, "handle message error", log.Err(err))
-					recordSpanError(span, err)
-				}
-
-				metrics.handlerSeconds.WithLabelValues(subject).Observe(time.Since(start).Seconds())
-				metrics.inflightReq.WithLabelValues(subject).Dec()
-				span.End()
+				s.handle(ctx, l, subject, "", handler, msg)
 			}
 		}
 	}()
@@ -131,31 +109,47 @@ func (s *subscriber) SubscribeQueue(ctx context.Context, subject string, queue s
 	)
 
 	_, err := s.nc.QueueSubscribe(subject, queue, func(msg *Msg) {
-		metrics.recvTotal.WithLabelValues(subject).Inc()
-		if msg != nil && msg.Data != nil {
-			metrics.recvBytes.WithLabelValues(subject).Observe(float64(len(msg.Data)))
-		}
-		// Извлекаем trace context из заголовков сообщения
-		handlerCtx, span := extractTraceContext(ctx, msg, fmt.Sprintf("nats.handle %s", subject))
-		handlerCtx = setSentryHubAndScope(handlerCtx, msg, queue)
-
-		// Обработка паник с отправкой в Sentry
-		defer sentry.RecoverWithContext(handlerCtx)
-		defer span.End()
+		s.handle(ctx, l, subject, queue, handler, msg)
+	})
+	log.Debugf(ctx, "subscribed to subject: %s, queue: %s\n", subject, queue)
+	return err
+}
 
-		start := time.Now()
-		metrics.inflightReq.WithLabelValues(subject).Inc()
+// handle обрабатывает одно сообщение. Паника в обработчике не роняет подписку:
+// она обрабатывается общей логикой recovery и считается ошибкой обработки.
+func (s *subscriber) handle(ctx context.Context, l log.Logger, subject, queue string, handler SubscriberHandler, msg *Msg) {
+	metrics.recvTotal.WithLabelValues(subject).Inc()
+	if msg != nil && msg.Data != nil {
+		metrics.recvBytes.WithLabelValues(subject).Observe(float64(len(msg.Data)))
+	}
 
-		if err := handler.Handle(handlerCtx, msg); err != nil {
-			l.Error(handlerCtx, "handle message error", log.Err(err))
-			recordSpanError(span, err)
-		}
+	// Извлекаем trace context из заголовков сообщения
+	handlerCtx, span := extractTraceContext(ctx, msg, fmt.Sprintf("nats.handle %s", subject))
+	handlerCtx = setSentryHubAndScope(handlerCtx, msg, queue)
+	handlerCtx = log.WithContextAttrs(handlerCtx, log.String(log.KeyNATSSubject, subject))
+	defer span.End()
 
+	start := time.Now()
+	metrics.inflightReq.WithLabelValues(subject).Inc()
+	defer func() {
 		metrics.handlerSeconds.WithLabelValues(subject).Observe(time.Since(start).Seconds())
 		metrics.inflightReq.WithLabelValues(subject).Dec()
-	})
-	log.Debugf(ctx, "subscribed to subject: %s, queue: %s\n", subject, queue)
-	return err
+	}()
+
+	defer func() {
+		if r := recover(); r != nil {
+			perr := recovery.Handle(handlerCtx, recovery.ComponentNATS, r, map[string]string{
+				"nats.subject": subject,
+				"nats.queue":   queue,
+			})
+			recordSpanError(span, perr)
+		}
+	}()
+
+	if err := handler.Handle(handlerCtx, msg); err != nil {
+		l.Error(handlerCtx, "handle message error", log.Err(err))
+		recordSpanError(span, err)
+	}
 }
 
 func (s *subscriber) Start(ctx context.Context) error {
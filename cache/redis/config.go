@@ -14,7 +14,7 @@ type Config struct {
 	Host        string        `yaml:"-" env:"REDIS_HOST"`
 	Port        string        `yaml:"-" env:"REDIS_PORT"`
 	User        string        `yaml:"-" env:"REDIS_USER"`
-	Password    string        `yaml:"-" env:"REDIS_PASSWORD"`
+	Password    string        `yaml:"-" env:"REDIS_PASSWORD" log:"redact"`
 	DB          int           `yaml:"-" env:"REDIS_DB"`
 	Required    bool          `yaml:"required"`
 	PoolSize    int           `yaml:"pool_size"`
@@ -16,7 +16,9 @@ import (
 	"github.com/Rasikrr/core/interfaces"
 	"github.com/Rasikrr/core/log"
 	"github.com/Rasikrr/core/metrics"
+	"github.com/Rasikrr/core/s3"
 	"github.com/Rasikrr/core/sentry"
+	"github.com/Rasikrr/core/telegram"
 	"github.com/Rasikrr/core/tracing"
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/joho/godotenv"
@@ -41,6 +43,12 @@ type Config struct {
 	Metrics  metrics.Config  `yaml:"metrics"`
 	Tracing  tracing.Config  `yaml:"tracing"`
 	Sentry   sentry.Config   `yaml:"sentry"`
+
+	// GRPCClients - gRPC клиенты по имени, создаются App и доступны через App.GRPCClient
+	GRPCClients grpc.ClientsConfig `yaml:"grpc_clients"`
+
+	S3       s3.Config       `yaml:"s3"`
+	Telegram telegram.Config `yaml:"telegram"`
 }
 
 func Parse() (Config, error) {
@@ -70,11 +78,15 @@ func Parse() (Config, error) {
 func (c *Config) validate() error {
 	for _, v := range []interfaces.Validatable{
 		c.Sentry,
+		c.Logger,
 		c.HTTP,
 		c.GRPC,
+		c.GRPCClients,
 		c.Postgres,
 		c.Redis,
 		c.NATS,
+		c.S3,
+		c.Telegram,
 		c.Variables,
 		c.Metrics,
 	} {
@@ -82,6 +94,10 @@ func (c *Config) validate() error {
 			return fmt.Errorf("error while validating config: %w", err)
 		}
 	}
+	// Ручка уровня логирования монтируется на сервер метрик, без него включать ее бессмысленно
+	if c.Logger.LevelEndpoint.Enabled && !c.Metrics.Enabled {
+		return errors.New("error while validating config: log.level_endpoint requires metrics to be enabled")
+	}
 	return nil
 }
 
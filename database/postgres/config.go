@@ -10,7 +10,7 @@ var (
 )
 
 type Config struct {
-	DSN                 string        `yaml:"-" env:"POSTGRES_DSN"`
+	DSN                 string        `yaml:"-" env:"POSTGRES_DSN" log:"redact"`
 	Required            bool          `yaml:"required"`
 	MaxConns            int           `yaml:"max_conns"`
 	MinConns            int           `yaml:"min_conns"`
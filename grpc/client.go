@@ -2,27 +2,78 @@ package grpc
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
+	"os"
+	"strconv"
+	"time"
 
+	"github.com/Rasikrr/core/log"
 	"github.com/Rasikrr/core/tracing"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
 )
 
 type Client struct {
-	conn *grpc.ClientConn
+	target string
+	conn   *grpc.ClientConn
 }
 
+// NewClient создает клиент с метриками, дедлайном по умолчанию, повторами Unavailable/ResourceExhausted
+// и отправкой ошибок в Sentry. Транспортные credentials передаются в opts.
 func NewClient(
 	ctx context.Context,
 	addr string,
 	opts ...grpc.DialOption,
 ) (*Client, error) {
-	if tracing.Enabled() {
-		opts = append(opts, tracingClientInterceptor())
+	cfg := ClientConfig{Target: addr}.withDefaults()
+	return NewClientWithOptions(ctx, addr, append(defaultClientOptions(cfg), opts...)...)
+}
+
+// NewClientFromConfig создает клиент по ClientConfig, включая TLS, keepalive и балансировку
+func NewClientFromConfig(
+	ctx context.Context,
+	cfg ClientConfig,
+	opts ...grpc.DialOption,
+) (*Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
 	}
-	return NewClientWithOptions(ctx, addr, opts...)
+	cfg = cfg.withDefaults()
+
+	creds, err := clientCredentials(cfg.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("create gRPC client credentials: %w", err)
+	}
+
+	dialOpts := append(defaultClientOptions(cfg), grpc.WithTransportCredentials(creds))
+	if cfg.Keepalive.Time > 0 {
+		dialOpts = append(dialOpts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                cfg.Keepalive.Time,
+			Timeout:             cfg.Keepalive.Timeout,
+			PermitWithoutStream: cfg.Keepalive.PermitWithoutStream,
+		}))
+	}
+
+	var callOpts []grpc.CallOption
+	if cfg.MaxRecvMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallRecvMsgSize(cfg.MaxRecvMsgSize))
+	}
+	if cfg.MaxSendMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallSendMsgSize(cfg.MaxSendMsgSize))
+	}
+	if len(callOpts) > 0 {
+		dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(callOpts...))
+	}
+
+	return NewClientWithOptions(ctx, cfg.Target, append(dialOpts, opts...)...)
 }
 
+// NewClientWithOptions создает клиент без интерцепторов фреймворка
 func NewClientWithOptions(
 	_ context.Context,
 	addr string,
@@ -33,10 +84,91 @@ func NewClientWithOptions(
 		return nil, fmt.Errorf("create gRPC client dial connection: %w", err)
 	}
 	return &Client{
-		conn: conn,
+		target: addr,
+		conn:   conn,
 	}, nil
 }
 
+func defaultClientOptions(cfg ClientConfig) []grpc.DialOption {
+	initGRPCMetrics()
+
+	opts := []grpc.DialOption{
+		grpc.WithDefaultServiceConfig(serviceConfig(cfg)),
+		grpc.WithChainUnaryInterceptor(
+			unaryClientDeadlineInterceptor(cfg.DefaultTimeout),
+			unaryClientSentryInterceptor,
+			metrics.UnaryClient(),
+		),
+		grpc.WithChainStreamInterceptor(
+			streamClientSentryInterceptor,
+			metrics.StreamClient(),
+		),
+	}
+	if tracing.Enabled() {
+		opts = append(opts, tracingClientInterceptor())
+	}
+	return opts
+}
+
+// serviceConfig описывает балансировку и повторы для всех методов.
+// Повторы выполняет сам gRPC, поэтому интерцепторы видят только итоговый результат.
+func serviceConfig(cfg ClientConfig) string {
+	sc := map[string]any{
+		"loadBalancingConfig": []map[string]any{{cfg.LoadBalancingPolicy: map[string]any{}}},
+	}
+	// gRPC считает политику с maxAttempts < 2 невалидной, MaxAttempts: 1 отключает повторы
+	if cfg.Retry.MaxAttempts > 1 {
+		sc["methodConfig"] = []map[string]any{{
+			"name": []map[string]any{{}},
+			"retryPolicy": map[string]any{
+				"maxAttempts":          cfg.Retry.MaxAttempts,
+				"initialBackoff":       protoDuration(cfg.Retry.InitialBackoff),
+				"maxBackoff":           protoDuration(cfg.Retry.MaxBackoff),
+				"backoffMultiplier":    cfg.Retry.BackoffMultiplier,
+				"retryableStatusCodes": []string{"UNAVAILABLE", "RESOURCE_EXHAUSTED"},
+			},
+		}}
+	}
+	bb, _ := json.Marshal(sc)
+	return string(bb)
+}
+
+// protoDuration форматирует длительность в JSON представлении google.protobuf.Duration
+func protoDuration(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
+
+func clientCredentials(cfg ClientTLSConfig) (credentials.TransportCredentials, error) {
+	if !cfg.Enabled {
+		return insecure.NewCredentials(), nil
+	}
+
+	tlsCfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         cfg.ServerName,
+		InsecureSkipVerify: cfg.InsecureSkipVerify, // nolint: gosec
+	}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.CAFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+	if cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	return credentials.NewTLS(tlsCfg), nil
+}
+
 func (c *Client) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
 	return c.conn.Invoke(ctx, method, args, reply, opts...)
 }
@@ -44,3 +176,16 @@ func (c *Client) Invoke(ctx context.Context, method string, args, reply any, opt
 func (c *Client) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
 	return c.conn.NewStream(ctx, desc, method, opts...)
 }
+
+// Conn возвращает соединение для сгенерированных клиентов: pb.NewUsersClient(client.Conn())
+func (c *Client) Conn() *grpc.ClientConn {
+	return c.conn
+}
+
+func (c *Client) Close(ctx context.Context) error {
+	if err := c.conn.Close(); err != nil {
+		return err
+	}
+	log.Info(ctx, "grpc client closed", log.String("target", c.target))
+	return nil
+}
@@ -0,0 +1,109 @@
+package grpc
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	defaultClientTimeout       = 5 * time.Second
+	defaultClientRetryAttempts = 3
+	defaultClientLBPolicy      = "round_robin"
+)
+
+// ClientConfig содержит настройки gRPC клиента
+type ClientConfig struct {
+	// Target - адрес в формате gRPC name resolution, например dns:///users:3000
+	Target string `yaml:"target"`
+	// DefaultTimeout - дедлайн unary вызова, если в контексте его нет
+	DefaultTimeout time.Duration `yaml:"default_timeout" env-default:"5s"`
+	// MaxRecvMsgSize и MaxSendMsgSize в байтах, 0 - значения gRPC по умолчанию (4MB на прием)
+	MaxRecvMsgSize int `yaml:"max_recv_msg_size"`
+	MaxSendMsgSize int `yaml:"max_send_msg_size"`
+	// LoadBalancingPolicy - pick_first или round_robin
+	LoadBalancingPolicy string                `yaml:"load_balancing_policy" env-default:"round_robin"`
+	Keepalive           ClientKeepaliveConfig `yaml:"keepalive"`
+	TLS                 ClientTLSConfig       `yaml:"tls"`
+	Retry               ClientRetryConfig     `yaml:"retry"`
+}
+
+type ClientKeepaliveConfig struct {
+	// Time - интервал ping при отсутствии активности, 0 - keepalive выключен
+	Time                time.Duration `yaml:"time"`
+	Timeout             time.Duration `yaml:"timeout" env-default:"20s"`
+	PermitWithoutStream bool          `yaml:"permit_without_stream"`
+}
+
+type ClientTLSConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// CAFile - сертификат CA сервера, без него используется системный пул
+	CAFile string `yaml:"ca_file"`
+	// CertFile и KeyFile задаются вместе для mTLS
+	CertFile           string `yaml:"cert_file"`
+	KeyFile            string `yaml:"key_file"`
+	ServerName         string `yaml:"server_name"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
+
+// ClientRetryConfig - политика повторов Unavailable и ResourceExhausted через service config
+type ClientRetryConfig struct {
+	// MaxAttempts - общее число попыток, включая первую. gRPC ограничивает его значением 5.
+	MaxAttempts       int           `yaml:"max_attempts" env-default:"3"`
+	InitialBackoff    time.Duration `yaml:"initial_backoff" env-default:"100ms"`
+	MaxBackoff        time.Duration `yaml:"max_backoff" env-default:"1s"`
+	BackoffMultiplier float64       `yaml:"backoff_multiplier" env-default:"2"`
+}
+
+// Validate проверяет корректность конфигурации
+func (c ClientConfig) Validate() error {
+	if c.Target == "" {
+		return fmt.Errorf("target is empty: %w", errConfigRequired)
+	}
+	switch c.LoadBalancingPolicy {
+	case "", "pick_first", "round_robin":
+	default:
+		return fmt.Errorf("unknown load_balancing_policy %q: %w", c.LoadBalancingPolicy, errConfigRequired)
+	}
+	if c.TLS.Enabled && (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		return fmt.Errorf("tls cert_file and key_file must be set together: %w", errConfigRequired)
+	}
+	if c.Retry.MaxAttempts < 0 || c.Retry.MaxAttempts > 5 {
+		return fmt.Errorf("retry.max_attempts must be in [0, 5]: %w", errConfigRequired)
+	}
+	return nil
+}
+
+func (c ClientConfig) withDefaults() ClientConfig {
+	if c.DefaultTimeout == 0 {
+		c.DefaultTimeout = defaultClientTimeout
+	}
+	if c.LoadBalancingPolicy == "" {
+		c.LoadBalancingPolicy = defaultClientLBPolicy
+	}
+	if c.Retry.MaxAttempts == 0 {
+		c.Retry.MaxAttempts = defaultClientRetryAttempts
+	}
+	if c.Retry.InitialBackoff == 0 {
+		c.Retry.InitialBackoff = 100 * time.Millisecond
+	}
+	if c.Retry.MaxBackoff == 0 {
+		c.Retry.MaxBackoff = time.Second
+	}
+	if c.Retry.BackoffMultiplier == 0 {
+		c.Retry.BackoffMultiplier = 2
+	}
+	return c
+}
+
+// ClientsConfig - именованные gRPC клиенты из YAML конфигурации
+type ClientsConfig map[string]ClientConfig
+
+// Validate проверяет конфигурацию каждого клиента
+func (c ClientsConfig) Validate() error {
+	for name, cfg := range c {
+		if err := cfg.Validate(); err != nil {
+			return fmt.Errorf("grpc client %q: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/Rasikrr/core/log"
+	"github.com/Rasikrr/core/sentry"
+	sentrySDK "github.com/getsentry/sentry-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// unaryClientDeadlineInterceptor выставляет дедлайн, если вызывающий его не задал,
+// чтобы зависший сервер не держал горутину бесконечно
+func unaryClientDeadlineInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if _, ok := ctx.Deadline(); !ok && timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func unaryClientSentryInterceptor(
+	ctx context.Context,
+	method string,
+	req, reply interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	reportClientError(ctx, "unary", cc.Target(), method, err)
+	return err
+}
+
+func streamClientSentryInterceptor(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	cc *grpc.ClientConn,
+	method string,
+	streamer grpc.Streamer,
+	opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	cs, err := streamer(ctx, desc, cc, method, opts...)
+	reportClientError(ctx, "stream", cc.Target(), method, err)
+	return cs, err
+}
+
+// reportClientError логирует ошибку вызова и отправляет ее в Sentry, если код из shouldReportToSentry.
+// Лог пишется на уровне warn, чтобы sentry handler логгера не создал второе событие.
+func reportClientError(ctx context.Context, callType, target, fullMethod string, err error) {
+	if err == nil {
+		return
+	}
+	st, _ := status.FromError(err)
+	code := st.Code()
+	if !shouldReportToSentry(code) {
+		return
+	}
+
+	service, method := split(fullMethod)
+	log.Warn(ctx, "grpc client call failed",
+		log.String("grpc.target", target),
+		log.String("grpc.service", service),
+		log.String("grpc.method", method),
+		log.String("grpc.code", code.String()),
+		log.Err(err),
+	)
+
+	hub := sentry.GetHubFromContext(ctx)
+	if hub == nil {
+		var hubErr error
+		if hub, hubErr = sentry.CurrentHub(); hubErr != nil {
+			return
+		}
+	}
+	hub.WithScope(func(scope *sentrySDK.Scope) {
+		scope.SetLevel(sentryLevelFromGRPCCode(code))
+		scope.SetTag("grpc.side", "client")
+		scope.SetTag("grpc.type", callType)
+		scope.SetTag("grpc.target", target)
+		scope.SetTag("grpc.service", service)
+		scope.SetTag("grpc.method", method)
+		scope.SetTag("grpc.code", code.String())
+		scope.SetContext("grpc_error", map[string]interface{}{
+			"message": st.Message(),
+			"code":    code.String(),
+			"details": st.Details(),
+		})
+		hub.CaptureException(err)
+	})
+}
@@ -0,0 +1,61 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type flakyHealthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+	calls       atomic.Int32
+	hadDeadline atomic.Bool
+}
+
+func (s *flakyHealthServer) Check(ctx context.Context, _ *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	_, ok := ctx.Deadline()
+	s.hadDeadline.Store(ok)
+	if s.calls.Add(1) < 3 {
+		return nil, status.Error(codes.Unavailable, "warming up")
+	}
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
+
+func TestClientRetriesAndDefaultDeadline(t *testing.T) {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	health := &flakyHealthServer{}
+	grpc_health_v1.RegisterHealthServer(srv, health)
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	client, err := NewClientFromConfig(context.Background(),
+		ClientConfig{
+			Target:         "passthrough:///bufnet",
+			DefaultTimeout: time.Second,
+			Retry:          ClientRetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond},
+		},
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer client.Close(context.Background())
+
+	resp, err := grpc_health_v1.NewHealthClient(client.Conn()).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	require.NoError(t, err)
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, resp.GetStatus())
+	require.EqualValues(t, 3, health.calls.Load())
+	require.True(t, health.hadDeadline.Load())
+}
@@ -11,6 +11,16 @@ type Config struct {
 	Host     string `yaml:"host" env:"GRPC_HOST" env-default:"0.0.0.0"`
 	Port     int    `yaml:"port" env:"GRPC_PORT" env-default:"3000"`
 	Required bool   `yaml:"required" env:"GRPC_REQUIRED" env-default:"false"`
+
+	Validation ValidationConfig `yaml:"validation"`
+}
+
+// ValidationConfig настраивает проверку входящих сообщений по buf.validate и Validate().
+// Проверка включена по умолчанию.
+type ValidationConfig struct {
+	Disabled bool `yaml:"disabled" env:"GRPC_VALIDATION_DISABLED"`
+	// SkipMethods - полные имена методов без проверки, например /users.v1.UserService/Import
+	SkipMethods []string `yaml:"skip_methods"`
 }
 
 // Validate проверяет корректность конфигурации
@@ -0,0 +1,86 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"strings"
+
+	coreCtx "github.com/Rasikrr/core/context"
+	coreHTTP "github.com/Rasikrr/core/http"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// TraceIDMetadataKey - ключ metadata, в котором gateway передает trace ID фреймворка
+const TraceIDMetadataKey = "trace-id"
+
+// Ключи metadata для request_id и tenant_id в логах. Gateway передает их из заголовков X-Request-Id и X-Tenant-Id.
+const (
+	RequestIDMetadataKey = "x-request-id"
+	TenantIDMetadataKey  = "x-tenant-id"
+)
+
+// GatewayRegistrar регистрирует обработчики сервиса на gateway mux.
+// Совпадает с сигнатурой сгенерированных функций: pb.RegisterUserServiceHandler
+type GatewayRegistrar func(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error
+
+// forwardedHeaders пробрасываются из HTTP запроса в gRPC metadata помимо стандартных заголовков gateway
+var forwardedHeaders = map[string]struct{}{
+	textproto.CanonicalMIMEHeaderKey(coreHTTP.TraceIDHeader): {},
+	"Authorization":          {},
+	coreHTTP.RequestIDHeader: {},
+	coreHTTP.TenantIDHeader:  {},
+	"X-Forwarded-For":        {},
+	"Accept-Language":        {},
+}
+
+// NewGateway возвращает http.Handler, транскодирующий JSON запросы в вызовы этого же сервера через
+// InProcessConn. Вызовы проходят все серверные интерцепторы: валидацию, метрики, Sentry, recovery.
+func (s *Server) NewGateway(ctx context.Context, registrars ...GatewayRegistrar) (http.Handler, error) {
+	conn, err := s.InProcessConn()
+	if err != nil {
+		return nil, fmt.Errorf("create in-process grpc connection: %w", err)
+	}
+
+	mux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(gatewayHeaderMatcher),
+		runtime.WithMetadata(gatewayMetadata),
+		runtime.WithErrorHandler(gatewayErrorHandler),
+		runtime.WithRoutingErrorHandler(gatewayRoutingErrorHandler),
+	)
+	for _, register := range registrars {
+		if err := register(ctx, mux, conn); err != nil {
+			return nil, fmt.Errorf("register gateway handler: %w", err)
+		}
+	}
+	return mux, nil
+}
+
+func gatewayHeaderMatcher(key string) (string, bool) {
+	if _, ok := forwardedHeaders[textproto.CanonicalMIMEHeaderKey(key)]; ok {
+		return strings.ToLower(key), true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// gatewayMetadata передает trace ID из контекста запроса, даже если клиент не прислал заголовок
+func gatewayMetadata(ctx context.Context, _ *http.Request) metadata.MD {
+	if traceID, ok := coreCtx.TraceID(ctx); ok {
+		return metadata.Pairs(TraceIDMetadataKey, traceID)
+	}
+	return nil
+}
+
+// gatewayErrorHandler отвечает в формате ErrorResponse фреймворка вместо google.rpc.Status
+func gatewayErrorHandler(ctx context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, _ *http.Request, err error) {
+	st := status.Convert(err)
+	coreHTTP.SendError(ctx, w, coreHTTP.NewError(st.Message(), runtime.HTTPStatusFromCode(st.Code())))
+}
+
+func gatewayRoutingErrorHandler(ctx context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, _ *http.Request, httpStatus int) {
+	coreHTTP.SendError(ctx, w, coreHTTP.NewError(http.StatusText(httpStatus), httpStatus))
+}
@@ -0,0 +1,111 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	coreHTTP "github.com/Rasikrr/core/http"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type gatewayHealthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+}
+
+func (gatewayHealthServer) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	if req.GetService() != "users" {
+		return nil, status.Error(codes.NotFound, "unknown service")
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if len(md.Get(TraceIDMetadataKey)) == 0 {
+		return nil, status.Error(codes.FailedPrecondition, "trace id is not propagated")
+	}
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
+
+// registerHealthGateway повторяет то, что генерирует protoc-gen-grpc-gateway для GET /v1/health/{service}
+func registerHealthGateway(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error {
+	client := grpc_health_v1.NewHealthClient(conn)
+	return mux.HandlePath(http.MethodGet, "/v1/health/{service}", func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		_, outbound := runtime.MarshalerForRequest(mux, r)
+		annotated, err := runtime.AnnotateContext(r.Context(), mux, r, "/grpc.health.v1.Health/Check")
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+		resp, err := client.Check(annotated, &grpc_health_v1.HealthCheckRequest{Service: params["service"]})
+		if err != nil {
+			runtime.HTTPError(annotated, mux, outbound, w, r, err)
+			return
+		}
+		runtime.ForwardResponseMessage(annotated, mux, outbound, w, r, resp)
+	})
+}
+
+func TestGatewayForwardsToInProcessServer(t *testing.T) {
+	srv, err := NewServer(Config{Host: "127.0.0.1"})
+	require.NoError(t, err)
+	grpc_health_v1.RegisterHealthServer(srv.Srv(), gatewayHealthServer{})
+
+	gateway, err := srv.NewGateway(context.Background(), registerHealthGateway)
+	require.NoError(t, err)
+
+	go func() { _ = srv.Start(context.Background()) }()
+	defer srv.Close(context.Background())
+
+	ts := httptest.NewServer(gateway)
+	defer ts.Close()
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/v1/health/users", nil)
+	require.NoError(t, err)
+	req.Header.Set(coreHTTP.TraceIDHeader, "trace-1")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Equal(t, "SERVING", body["status"])
+
+	resp, err = http.Get(ts.URL + "/v1/health/orders")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	var errResp coreHTTP.ErrorResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&errResp))
+	require.Equal(t, coreHTTP.ErrorResponse{Code: http.StatusNotFound, Message: "unknown service"}, errResp)
+}
+
+func TestInProcessConnAfterStart(t *testing.T) {
+	srv, err := NewServer(Config{Host: "127.0.0.1"})
+	require.NoError(t, err)
+	grpc_health_v1.RegisterHealthServer(srv.Srv(), gatewayHealthServer{})
+
+	go func() { _ = srv.Start(context.Background()) }()
+	defer srv.Close(context.Background())
+	// Соединение запрашивается, когда сервер уже запущен, как у gateway, созданного после Start
+	time.Sleep(100 * time.Millisecond)
+
+	conn, err := srv.InProcessConn()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, TraceIDMetadataKey, "trace-1")
+	resp, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "users"},
+		grpc.WaitForReady(true))
+	require.NoError(t, err)
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, resp.Status)
+}
@@ -0,0 +1,42 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/Rasikrr/core/tracing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const inProcessBufSize = 1 << 20
+
+// inProcess - listener в памяти, который сервер обслуживает наравне с TCP.
+// Вызовы через него проходят все серверные интерцепторы, но не выходят в сеть.
+// Listener создается в NewServer и обслуживается всегда, поэтому InProcessConn можно вызывать и после Start.
+type inProcess struct {
+	lis  *bufconn.Listener
+	once sync.Once
+	conn *grpc.ClientConn
+	err  error
+}
+
+// InProcessConn возвращает соединение с этим же сервером через память.
+// Используется gateway и тестами; вызовы обслуживаются после Start.
+func (s *Server) InProcessConn() (*grpc.ClientConn, error) {
+	s.inproc.once.Do(func() {
+		opts := []grpc.DialOption{
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return s.inproc.lis.DialContext(ctx)
+			}),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		}
+		if tracing.Enabled() {
+			opts = append(opts, tracingClientInterceptor())
+		}
+		s.inproc.conn, s.inproc.err = grpc.NewClient("passthrough:///in-process", opts...)
+	})
+	return s.inproc.conn, s.inproc.err
+}
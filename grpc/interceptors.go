@@ -2,9 +2,8 @@ package grpc
 
 import (
 	"context"
-	"runtime/debug"
 
-	"github.com/Rasikrr/core/log"
+	"github.com/Rasikrr/core/recovery"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -13,13 +12,13 @@ import (
 func streamPanicRecoveryInterceptor(
 	srv interface{},
 	ss grpc.ServerStream,
-	_ *grpc.StreamServerInfo,
+	info *grpc.StreamServerInfo,
 	handler grpc.StreamHandler,
-) error {
-	ctx := ss.Context()
+) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			log.Warnf(ctx, "Recovered from panic in stream: %v\n%s", r, debug.Stack())
+			recovery.Handle(ss.Context(), recovery.ComponentGRPC, r, panicTags("stream", info.FullMethod))
+			err = status.Errorf(codes.Internal, "internal server error")
 		}
 	}()
 	return handler(srv, ss)
@@ -28,14 +27,23 @@ func streamPanicRecoveryInterceptor(
 func unaryPanicRecoveryInterceptor(
 	ctx context.Context,
 	req interface{},
-	_ *grpc.UnaryServerInfo,
+	info *grpc.UnaryServerInfo,
 	handler grpc.UnaryHandler,
 ) (resp interface{}, err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			log.Warnf(ctx, "Recovered from panic in unary: %v\n%s", r, debug.Stack())
+			recovery.Handle(ctx, recovery.ComponentGRPC, r, panicTags("unary", info.FullMethod))
 			err = status.Errorf(codes.Internal, "internal server error")
 		}
 	}()
 	return handler(ctx, req)
 }
+
+func panicTags(callType, fullMethod string) map[string]string {
+	service, method := split(fullMethod)
+	return map[string]string{
+		"grpc.type":    callType,
+		"grpc.service": service,
+		"grpc.method":  method,
+	}
+}
@@ -0,0 +1,60 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	coreCtx "github.com/Rasikrr/core/context"
+	"github.com/Rasikrr/core/log"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type fakeServerStream struct {
+	grpc.ServerStream
+}
+
+func (fakeServerStream) Context() context.Context {
+	return context.Background()
+}
+
+func TestPanicRecoveryReturnsInternal(t *testing.T) {
+	_, err := unaryPanicRecoveryInterceptor(context.Background(), nil,
+		&grpc.UnaryServerInfo{FullMethod: "/test.Service/Unary"},
+		func(context.Context, interface{}) (interface{}, error) {
+			panic("boom")
+		},
+	)
+	require.Equal(t, codes.Internal, status.Code(err))
+
+	err = streamPanicRecoveryInterceptor(nil, fakeServerStream{},
+		&grpc.StreamServerInfo{FullMethod: "/test.Service/Stream"},
+		func(interface{}, grpc.ServerStream) error {
+			panic("boom")
+		},
+	)
+	require.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestTraceInterceptorAddsLogAttrs(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		TraceIDMetadataKey, "trace-1",
+		RequestIDMetadataKey, "req-1",
+	))
+	_, err := UnaryServerTraceInterceptor(ctx, nil,
+		&grpc.UnaryServerInfo{FullMethod: "/test.Service/Unary"},
+		func(ctx context.Context, _ interface{}) (interface{}, error) {
+			traceID, _ := coreCtx.TraceID(ctx)
+			require.Equal(t, "trace-1", traceID)
+			require.Equal(t, []log.Attr{
+				log.String(log.KeyGRPCMethod, "/test.Service/Unary"),
+				log.String(log.KeyRequestID, "req-1"),
+			}, log.ContextAttrs(ctx))
+			return nil, nil
+		},
+	)
+	require.NoError(t, err)
+}
@@ -2,8 +2,6 @@ package grpc
 
 import (
 	"context"
-	"fmt"
-	"runtime/debug"
 
 	"github.com/Rasikrr/core/sentry"
 	sentrySDK "github.com/getsentry/sentry-go"
@@ -33,34 +31,7 @@ func UnaryServerSentryInterceptor(
 		"method":  method,
 	})
 
-	defer func() {
-		if r := recover(); r != nil {
-			hub.WithScope(func(scope *sentrySDK.Scope) {
-				scope.SetLevel(sentrySDK.LevelFatal)
-				scope.SetTag("grpc.type", "unary")
-				scope.SetTag("grpc.service", service)
-				scope.SetTag("grpc.method", method)
-				scope.SetContext("panic", map[string]interface{}{
-					"value":      fmt.Sprintf("%v", r),
-					"stacktrace": string(debug.Stack()),
-				})
-
-				var panicErr error
-				switch x := r.(type) {
-				case error:
-					panicErr = x
-				case string:
-					panicErr = fmt.Errorf("panic: %s", x)
-				default:
-					panicErr = fmt.Errorf("panic: %v", r)
-				}
-
-				hub.CaptureException(panicErr)
-			})
-			panic(r) // re-panic, чтобы другие интерцепторы могли обработать
-		}
-	}()
-
+	// Паники отправляет в Sentry внешний recovery интерцептор
 	resp, err = handler(ctx, req)
 
 	if err != nil {
@@ -110,34 +81,7 @@ func StreamServerSentryInterceptor(
 		"method":  method,
 	})
 
-	defer func() {
-		if r := recover(); r != nil {
-			hub.WithScope(func(scope *sentrySDK.Scope) {
-				scope.SetLevel(sentrySDK.LevelFatal)
-				scope.SetTag("grpc.type", "stream")
-				scope.SetTag("grpc.service", service)
-				scope.SetTag("grpc.method", method)
-				scope.SetContext("panic", map[string]interface{}{
-					"value":      fmt.Sprintf("%v", r),
-					"stacktrace": string(debug.Stack()),
-				})
-
-				var panicErr error
-				switch x := r.(type) {
-				case error:
-					panicErr = x
-				case string:
-					panicErr = fmt.Errorf("panic: %s", x)
-				default:
-					panicErr = fmt.Errorf("panic: %v", r)
-				}
-
-				hub.CaptureException(panicErr)
-			})
-			panic(r) // re-panic, чтобы другие интерцепторы могли обработать
-		}
-	}()
-
+	// Паники отправляет в Sentry внешний recovery интерцептор
 	err := handler(srv, &sentryServerStream{ServerStream: ss, hub: hub, service: service, method: method})
 
 	if err != nil {
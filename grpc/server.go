@@ -10,6 +10,7 @@ import (
 	"github.com/Rasikrr/core/tracing"
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
 )
 
 const (
@@ -18,19 +19,32 @@ const (
 )
 
 type Server struct {
-	host   string
-	port   int
-	server *grpc.Server
+	host      string
+	port      int
+	server    *grpc.Server
+	validator *requestValidator
+	inproc    inProcess
 }
 
+// NewServer создает сервер. Ошибка возвращается, если не удалось включить проверку входящих сообщений:
+// без нее сервер не запускается, отключить проверку можно только через validation.disabled.
 func NewServer(
 	cfg Config,
-) *Server {
-	return &Server{
-		host:   cfg.Host,
-		port:   cfg.Port,
-		server: newGrpcServer(),
+) (*Server, error) {
+	s := &Server{
+		host: cfg.Host,
+		port: cfg.Port,
 	}
+	s.inproc.lis = bufconn.Listen(inProcessBufSize)
+	if !cfg.Validation.Disabled {
+		v, err := newRequestValidator(cfg.Validation.SkipMethods)
+		if err != nil {
+			return nil, fmt.Errorf("init grpc request validation: %w", err)
+		}
+		s.validator = v
+	}
+	s.server = newGrpcServer(s.validator)
+	return s, nil
 }
 
 func (s *Server) Start(ctx context.Context) error {
@@ -39,6 +53,11 @@ func (s *Server) Start(ctx context.Context) error {
 		return err
 	}
 	log.Info(ctx, "starting grpc server")
+	go func() {
+		if err := s.server.Serve(s.inproc.lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			log.Error(ctx, "in-process grpc listener stopped", log.Err(err))
+		}
+	}()
 	if err := s.server.Serve(lis); err != nil {
 		if errors.Is(err, grpc.ErrServerStopped) {
 			return nil
@@ -50,6 +69,10 @@ func (s *Server) Start(ctx context.Context) error {
 
 func (s *Server) Close(ctx context.Context) error {
 	s.server.GracefulStop()
+	_ = s.inproc.lis.Close()
+	if s.inproc.conn != nil {
+		_ = s.inproc.conn.Close()
+	}
 	log.Info(ctx, "grpc server closed")
 	return nil
 }
@@ -58,11 +81,19 @@ func (s *Server) Srv() *grpc.Server {
 	return s.server
 }
 
+// SkipValidation отключает проверку входящих сообщений для методов,
+// например SkipValidation("/users.v1.UserService/Import")
+func (s *Server) SkipValidation(fullMethods ...string) {
+	if s.validator != nil {
+		s.validator.skipMethods(fullMethods...)
+	}
+}
+
 func (s *Server) addr(host string) string {
 	return fmt.Sprintf("%s:%d", host, s.port)
 }
 
-func newGrpcServer() *grpc.Server {
+func newGrpcServer(validator *requestValidator) *grpc.Server {
 	initGRPCMetrics()
 
 	unaryInterceptors := []grpc.UnaryServerInterceptor{
@@ -71,20 +102,24 @@ func newGrpcServer() *grpc.Server {
 		metrics.UnaryServer(),
 	}
 
-	if tracing.Enabled() {
-		unaryInterceptors = append(unaryInterceptors, UnaryServerTraceInterceptor)
+	if validator != nil {
+		unaryInterceptors = append(unaryInterceptors, validator.Unary())
 	}
 
+	unaryInterceptors = append(unaryInterceptors, UnaryServerTraceInterceptor)
+
 	streamInterceptors := []grpc.StreamServerInterceptor{
 		streamPanicRecoveryInterceptor,
 		StreamServerSentryInterceptor,
 		metrics.StreamServer(),
 	}
 
-	if tracing.Enabled() {
-		streamInterceptors = append(streamInterceptors, StreamServerTraceInterceptor)
+	if validator != nil {
+		streamInterceptors = append(streamInterceptors, validator.Stream())
 	}
 
+	streamInterceptors = append(streamInterceptors, StreamServerTraceInterceptor)
+
 	unary := grpc.UnaryInterceptor(
 		grpc_middleware.ChainUnaryServer(
 			unaryInterceptors...,
@@ -4,9 +4,11 @@ import (
 	"context"
 
 	coreCtx "github.com/Rasikrr/core/context"
+	"github.com/Rasikrr/core/log"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 func tracingClientInterceptor() grpc.DialOption {
@@ -20,15 +22,10 @@ func tracingServerInterceptor() grpc.ServerOption {
 func UnaryServerTraceInterceptor(
 	ctx context.Context,
 	req interface{},
-	_ *grpc.UnaryServerInfo,
+	info *grpc.UnaryServerInfo,
 	handler grpc.UnaryHandler,
 ) (interface{}, error) {
-	sc := trace.SpanContextFromContext(ctx)
-	if sc.HasTraceID() {
-		ctx = coreCtx.WithTraceID(ctx, sc.TraceID().String())
-	}
-
-	return handler(ctx, req)
+	return handler(incomingContext(ctx, info.FullMethod), req)
 }
 
 type wrappedStream struct {
@@ -43,18 +40,40 @@ func (w *wrappedStream) Context() context.Context {
 func StreamServerTraceInterceptor(
 	srv interface{},
 	ss grpc.ServerStream,
-	_ *grpc.StreamServerInfo,
+	info *grpc.StreamServerInfo,
 	handler grpc.StreamHandler,
 ) error {
-	sc := trace.SpanContextFromContext(ss.Context())
-	if sc.IsValid() && sc.HasTraceID() {
-		newCtx := coreCtx.WithTraceID(ss.Context(), sc.TraceID().String())
-
-		ss = &wrappedStream{
-			ServerStream: ss,
-			ctx:          newCtx,
-		}
+	ss = &wrappedStream{
+		ServerStream: ss,
+		ctx:          incomingContext(ss.Context(), info.FullMethod),
 	}
 
 	return handler(srv, ss)
 }
+
+// incomingContext добавляет trace ID и атрибуты логов вызова: метод, а также request_id и tenant_id из metadata
+func incomingContext(ctx context.Context, method string) context.Context {
+	if traceID, ok := incomingTraceID(ctx); ok {
+		ctx = coreCtx.WithTraceID(ctx, traceID)
+	}
+
+	attrs := []log.Attr{log.String(log.KeyGRPCMethod, method)}
+	if values := metadata.ValueFromIncomingContext(ctx, RequestIDMetadataKey); len(values) > 0 && values[0] != "" {
+		attrs = append(attrs, log.String(log.KeyRequestID, values[0]))
+	}
+	if values := metadata.ValueFromIncomingContext(ctx, TenantIDMetadataKey); len(values) > 0 && values[0] != "" {
+		attrs = append(attrs, log.String(log.KeyTenantID, values[0]))
+	}
+	return log.WithContextAttrs(ctx, attrs...)
+}
+
+// incomingTraceID берет trace ID из span, а без трейсинга - из metadata, например от gateway
+func incomingTraceID(ctx context.Context) (string, bool) {
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		return sc.TraceID().String(), true
+	}
+	if values := metadata.ValueFromIncomingContext(ctx, TraceIDMetadataKey); len(values) > 0 && values[0] != "" {
+		return values[0], true
+	}
+	return "", false
+}
@@ -0,0 +1,161 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"buf.build/go/protovalidate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// validatable - сообщения, для которых сгенерирован Validate() error (protoc-gen-validate и аналоги)
+type validatable interface {
+	Validate() error
+}
+
+// pgvFieldError - ошибка поля в формате protoc-gen-validate
+type pgvFieldError interface {
+	Field() string
+	Reason() string
+}
+
+// pgvMultiError - результат ValidateAll() в формате protoc-gen-validate
+type pgvMultiError interface {
+	AllErrors() []error
+}
+
+// requestValidator проверяет входящие сообщения по аннотациям buf.validate и методу Validate().
+// Методы из skip не проверяются.
+type requestValidator struct {
+	pv protovalidate.Validator
+
+	mu   sync.RWMutex
+	skip map[string]struct{}
+}
+
+func newRequestValidator(skipMethods []string) (*requestValidator, error) {
+	pv, err := protovalidate.New()
+	if err != nil {
+		return nil, err
+	}
+	v := &requestValidator{
+		pv:   pv,
+		skip: make(map[string]struct{}, len(skipMethods)),
+	}
+	v.skipMethods(skipMethods...)
+	return v, nil
+}
+
+func (v *requestValidator) skipMethods(fullMethods ...string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for _, m := range fullMethods {
+		v.skip[m] = struct{}{}
+	}
+}
+
+func (v *requestValidator) skipped(fullMethod string) bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	_, ok := v.skip[fullMethod]
+	return ok
+}
+
+func (v *requestValidator) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !v.skipped(info.FullMethod) {
+			if err := v.validate(req); err != nil {
+				return nil, err
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (v *requestValidator) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if v.skipped(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		return handler(srv, &validatingServerStream{ServerStream: ss, v: v})
+	}
+}
+
+type validatingServerStream struct {
+	grpc.ServerStream
+	v *requestValidator
+}
+
+func (s *validatingServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return s.v.validate(m)
+}
+
+// validate возвращает codes.InvalidArgument с errdetails.BadRequest при нарушениях
+func (v *requestValidator) validate(msg interface{}) error {
+	if pm, ok := msg.(proto.Message); ok {
+		if err := v.pv.Validate(pm); err != nil {
+			var valErr *protovalidate.ValidationError
+			if !errors.As(err, &valErr) {
+				// Ошибка компиляции или выполнения правил - проблема сервера, а не запроса
+				return status.Errorf(codes.Internal, "validate request: %v", err)
+			}
+			return invalidArgument(err)
+		}
+	}
+	if m, ok := msg.(validatable); ok {
+		if err := m.Validate(); err != nil {
+			return invalidArgument(err)
+		}
+	}
+	return nil
+}
+
+func invalidArgument(err error) error {
+	var (
+		violations []*errdetails.BadRequest_FieldViolation
+		valErr     *protovalidate.ValidationError
+		multiErr   pgvMultiError
+	)
+	switch {
+	case errors.As(err, &valErr):
+		for _, violation := range valErr.Violations {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       protovalidate.FieldPathString(violation.Proto.GetField()),
+				Description: violation.Proto.GetMessage(),
+				Reason:      violation.Proto.GetRuleId(),
+			})
+		}
+	case errors.As(err, &multiErr):
+		for _, e := range multiErr.AllErrors() {
+			violations = append(violations, fieldViolation(e))
+		}
+	default:
+		violations = append(violations, fieldViolation(err))
+	}
+
+	st := status.New(codes.InvalidArgument, "invalid request")
+	withDetails, detailsErr := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations})
+	if detailsErr != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return withDetails.Err()
+}
+
+func fieldViolation(err error) *errdetails.BadRequest_FieldViolation {
+	var fieldErr pgvFieldError
+	if errors.As(err, &fieldErr) {
+		return &errdetails.BadRequest_FieldViolation{
+			Field:       fieldErr.Field(),
+			Description: fieldErr.Reason(),
+		}
+	}
+	return &errdetails.BadRequest_FieldViolation{Description: err.Error()}
+}
@@ -0,0 +1,105 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// newAnnotatedRequest собирает сообщение с правилом (buf.validate.field).string.min_len = 3 без кодогенерации
+func newAnnotatedRequest(t *testing.T, name string) proto.Message {
+	t.Helper()
+
+	fieldOpts := &descriptorpb.FieldOptions{}
+	proto.SetExtension(fieldOpts, validate.E_Field, validate.FieldRules_builder{
+		String: validate.StringRules_builder{MinLen: proto.Uint64(3)}.Build(),
+	}.Build())
+
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("core/grpc/validation_test.proto"),
+		Package:    proto.String("core.test"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"buf/validate/validate.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("CreateUserRequest"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:     proto.String("name"),
+				JsonName: proto.String("name"),
+				Number:   proto.Int32(1),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Options:  fieldOpts,
+			}},
+		}},
+	}, protoregistry.GlobalFiles)
+	require.NoError(t, err)
+
+	md := fd.Messages().ByName("CreateUserRequest")
+	msg := dynamicpb.NewMessage(md)
+	msg.Set(md.Fields().ByName("name"), protoreflect.ValueOfString(name))
+	return msg
+}
+
+type pgvError struct{ field, reason string }
+
+func (e pgvError) Error() string  { return e.field + ": " + e.reason }
+func (e pgvError) Field() string  { return e.field }
+func (e pgvError) Reason() string { return e.reason }
+
+type legacyRequest struct{ email string }
+
+func (r legacyRequest) Validate() error {
+	if r.email == "" {
+		return pgvError{field: "email", reason: "value is required"}
+	}
+	return nil
+}
+
+func TestValidationInterceptor(t *testing.T) {
+	v, err := newRequestValidator([]string{"/core.test.Users/Import"})
+	require.NoError(t, err)
+
+	interceptor := v.Unary()
+	handler := func(context.Context, interface{}) (interface{}, error) { return "ok", nil }
+	call := func(method string, req interface{}) error {
+		_, err := interceptor(context.Background(), req, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return err
+	}
+
+	require.NoError(t, call("/core.test.Users/Create", newAnnotatedRequest(t, "Alice")))
+	requireFieldViolation(t, call("/core.test.Users/Create", newAnnotatedRequest(t, "Al")), "name")
+	requireFieldViolation(t, call("/core.test.Users/Create", legacyRequest{}), "email")
+
+	// Метод из skip_methods не проверяется
+	require.NoError(t, call("/core.test.Users/Import", legacyRequest{}))
+}
+
+func requireFieldViolation(t *testing.T, err error, field string) {
+	t.Helper()
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+
+	var badRequest *errdetails.BadRequest
+	for _, d := range st.Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			badRequest = br
+		}
+	}
+	require.NotNil(t, badRequest)
+	require.Len(t, badRequest.GetFieldViolations(), 1)
+	require.Equal(t, field, badRequest.GetFieldViolations()[0].GetField())
+}
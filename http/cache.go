@@ -0,0 +1,150 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type conditionalCtxKey struct{}
+
+// conditionalRequest - данные запроса, нужные SendData для ответа 304
+type conditionalRequest struct {
+	method      string
+	ifNoneMatch string
+}
+
+// conditionalRequestMiddleware сохраняет в контексте метод и If-None-Match,
+// чтобы SendData мог ответить 304 без доступа к *http.Request
+func conditionalRequestMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx := context.WithValue(r.Context(), conditionalCtxKey{}, conditionalRequest{
+			method:      r.Method,
+			ifNoneMatch: r.Header.Get(IfNoneMatchHeader),
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// writeNotModified выставляет ETag и возвращает true, если клиент уже имеет актуальную версию
+func writeNotModified(ctx context.Context, w http.ResponseWriter, statusCode int, body []byte) bool {
+	cond, ok := ctx.Value(conditionalCtxKey{}).(conditionalRequest)
+	if !ok || statusCode != http.StatusOK {
+		return false
+	}
+
+	etag := w.Header().Get(ETagHeader)
+	if etag == "" {
+		etag = WeakETag(body)
+		w.Header().Set(ETagHeader, etag)
+	}
+
+	if cond.ifNoneMatch == "" || !ETagMatch(cond.ifNoneMatch, etag) {
+		return false
+	}
+	w.Header().Del(ContentTypeHeader)
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// WeakETag вычисляет слабый ETag по содержимому ответа
+func WeakETag(body []byte) string {
+	h := fnv.New64a()
+	_, _ = h.Write(body)
+	return fmt.Sprintf(`W/"%x-%x"`, len(body), h.Sum64())
+}
+
+// ETagMatch сравнивает If-None-Match с ETag по правилам слабого сравнения (RFC 9110 13.1.2)
+func ETagMatch(ifNoneMatch, etag string) bool {
+	if strings.TrimSpace(ifNoneMatch) == "*" {
+		return true
+	}
+	want := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == want {
+			return true
+		}
+	}
+	return false
+}
+
+// CacheControl описывает директивы заголовка Cache-Control
+type CacheControl struct {
+	Public               bool
+	Private              bool
+	NoCache              bool
+	NoStore              bool
+	MustRevalidate       bool
+	Immutable            bool
+	MaxAge               time.Duration
+	SharedMaxAge         time.Duration
+	StaleWhileRevalidate time.Duration
+}
+
+func (c CacheControl) String() string {
+	directives := make([]string, 0, 4)
+	appendIf := func(cond bool, d string) {
+		if cond {
+			directives = append(directives, d)
+		}
+	}
+	appendIf(c.Public, "public")
+	appendIf(c.Private, "private")
+	appendIf(c.NoCache, "no-cache")
+	appendIf(c.NoStore, "no-store")
+	appendIf(c.MustRevalidate, "must-revalidate")
+	appendIf(c.Immutable, "immutable")
+	if c.MaxAge > 0 {
+		directives = append(directives, "max-age="+seconds(c.MaxAge))
+	}
+	if c.SharedMaxAge > 0 {
+		directives = append(directives, "s-maxage="+seconds(c.SharedMaxAge))
+	}
+	if c.StaleWhileRevalidate > 0 {
+		directives = append(directives, "stale-while-revalidate="+seconds(c.StaleWhileRevalidate))
+	}
+	return strings.Join(directives, ", ")
+}
+
+// SetCacheControl выставляет Cache-Control. Вызывать до SendData.
+func SetCacheControl(w http.ResponseWriter, cc CacheControl) {
+	w.Header().Set(CacheControlHeader, cc.String())
+}
+
+// SetNoStore запрещает кэширование ответа
+func SetNoStore(w http.ResponseWriter) {
+	SetCacheControl(w, CacheControl{NoStore: true})
+}
+
+// SetPrivateCache разрешает кэширование только на клиенте с обязательной ревалидацией по ETag
+func SetPrivateCache(w http.ResponseWriter, maxAge time.Duration) {
+	SetCacheControl(w, CacheControl{Private: true, MaxAge: maxAge, MustRevalidate: true})
+}
+
+// CacheControlMiddleware выставляет Cache-Control для всех ответов, если обработчик не задал свой
+type CacheControlMiddleware struct {
+	value string
+}
+
+func NewCacheControlMiddleware(cc CacheControl) *CacheControlMiddleware {
+	return &CacheControlMiddleware{value: cc.String()}
+}
+
+func (m *CacheControlMiddleware) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(CacheControlHeader, m.value)
+		next.ServeHTTP(w, r)
+	})
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64(d/time.Second), 10)
+}
@@ -0,0 +1,315 @@
+package http
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	AcceptEncodingHeader  = "Accept-Encoding"
+	ContentEncodingHeader = "Content-Encoding"
+	VaryHeader            = "Vary"
+
+	EncodingZstd   = "zstd"
+	EncodingBrotli = "br"
+	EncodingGzip   = "gzip"
+
+	defaultCompressionMinSize = 1024
+)
+
+// DefaultCompressibleTypes - типы контента, которые сжимаются по умолчанию
+var DefaultCompressibleTypes = []string{
+	"application/json",
+	"application/problem+json",
+	"application/javascript",
+	"application/xml",
+	"text/*",
+	"image/svg+xml",
+}
+
+// CompressionOptions настраивает CompressionMiddleware
+type CompressionOptions struct {
+	// MinSize - ответы меньше этого размера отдаются без сжатия
+	MinSize int
+	// ContentTypes - allowlist типов контента, поддерживает маски вида text/*
+	ContentTypes []string
+	// Encodings - поддерживаемые кодировки в порядке предпочтения сервера
+	Encodings []string
+}
+
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// CompressionMiddleware сжимает ответы gzip, zstd или brotli в зависимости от Accept-Encoding
+type CompressionMiddleware struct {
+	minSize   int
+	types     []string
+	encodings []string
+	pools     map[string]*sync.Pool
+}
+
+func NewCompressionMiddleware(opts CompressionOptions) *CompressionMiddleware {
+	if opts.MinSize <= 0 {
+		opts.MinSize = defaultCompressionMinSize
+	}
+	if len(opts.ContentTypes) == 0 {
+		opts.ContentTypes = DefaultCompressibleTypes
+	}
+	if len(opts.Encodings) == 0 {
+		opts.Encodings = []string{EncodingZstd, EncodingBrotli, EncodingGzip}
+	}
+
+	m := &CompressionMiddleware{
+		minSize: opts.MinSize,
+		types:   opts.ContentTypes,
+		pools:   make(map[string]*sync.Pool, len(opts.Encodings)),
+	}
+	for _, enc := range opts.Encodings {
+		newFn := newEncoderFunc(enc)
+		if newFn == nil {
+			continue
+		}
+		m.encodings = append(m.encodings, enc)
+		m.pools[enc] = &sync.Pool{New: func() any { return newFn() }}
+	}
+	return m
+}
+
+func newEncoderFunc(encoding string) func() encoder {
+	switch encoding {
+	case EncodingGzip:
+		return func() encoder {
+			w, _ := gzip.NewWriterLevel(io.Discard, gzip.DefaultCompression)
+			return w
+		}
+	case EncodingZstd:
+		return func() encoder {
+			w, _ := zstd.NewWriter(io.Discard,
+				zstd.WithEncoderLevel(zstd.SpeedDefault),
+				zstd.WithEncoderConcurrency(1),
+			)
+			return w
+		}
+	case EncodingBrotli:
+		return func() encoder {
+			return brotli.NewWriterLevel(io.Discard, 4)
+		}
+	default:
+		return nil
+	}
+}
+
+func (m *CompressionMiddleware) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add(VaryHeader, AcceptEncodingHeader)
+
+		encoding := m.negotiate(r.Header.Get(AcceptEncodingHeader))
+		if encoding == "" || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{
+			ResponseWriter: w,
+			mw:             m,
+			encoding:       encoding,
+			status:         http.StatusOK,
+		}
+		defer cw.close()
+
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiate выбирает кодировку с максимальным q, при равенстве - по порядку сервера
+func (m *CompressionMiddleware) negotiate(header string) string {
+	if header == "" {
+		return ""
+	}
+	accepted := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		accepted[strings.ToLower(strings.TrimSpace(name))] = q
+	}
+
+	var (
+		best  string
+		bestQ float64
+	)
+	for _, enc := range m.encodings {
+		q, ok := accepted[enc]
+		if !ok {
+			q, ok = accepted["*"]
+		}
+		if ok && q > bestQ {
+			best, bestQ = enc, q
+		}
+	}
+	return best
+}
+
+func (m *CompressionMiddleware) compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, t := range m.types {
+		if prefix, ok := strings.CutSuffix(t, "/*"); ok {
+			if strings.HasPrefix(mediaType, prefix+"/") {
+				return true
+			}
+			continue
+		}
+		if mediaType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// compressWriter буферизует начало ответа, пока не станет ясно, нужно ли сжатие:
+// маленькие ответы и неподходящие типы контента уходят как есть.
+type compressWriter struct {
+	http.ResponseWriter
+	mw       *CompressionMiddleware
+	encoding string
+
+	status      int
+	buf         []byte
+	decided     bool
+	enc         encoder
+	wroteHeader bool
+}
+
+func (cw *compressWriter) WriteHeader(statusCode int) {
+	if cw.decided || cw.wroteHeader {
+		return
+	}
+	cw.status = statusCode
+	cw.wroteHeader = true
+	// Ответы без тела не сжимаются
+	if statusCode < http.StatusOK || statusCode == http.StatusNoContent || statusCode == http.StatusNotModified {
+		cw.passthrough()
+	}
+}
+
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	if !cw.decided {
+		cw.buf = append(cw.buf, b...)
+		if len(cw.buf) < cw.mw.minSize {
+			return len(b), nil
+		}
+		if err := cw.decide(true); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+	if cw.enc != nil {
+		return cw.enc.Write(b)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+// decide принимает решение о сжатии и сбрасывает накопленный буфер
+func (cw *compressWriter) decide(bigEnough bool) error {
+	h := cw.Header()
+	if h.Get(ContentTypeHeader) == "" && len(cw.buf) > 0 {
+		h.Set(ContentTypeHeader, http.DetectContentType(cw.buf))
+	}
+	if !bigEnough || h.Get(ContentEncodingHeader) != "" || !cw.mw.compressible(h.Get(ContentTypeHeader)) {
+		return cw.passthrough()
+	}
+
+	cw.decided = true
+	h.Set(ContentEncodingHeader, cw.encoding)
+	h.Del("Content-Length")
+	// Сильный ETag не может совпадать у разных представлений
+	if etag := h.Get(ETagHeader); etag != "" && !strings.HasPrefix(etag, "W/") {
+		h.Set(ETagHeader, "W/"+etag)
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	cw.enc = cw.mw.pools[cw.encoding].Get().(encoder)
+	cw.enc.Reset(cw.ResponseWriter)
+	buf := cw.buf
+	cw.buf = nil
+	_, err := cw.enc.Write(buf)
+	return err
+}
+
+func (cw *compressWriter) passthrough() error {
+	cw.decided = true
+	cw.ResponseWriter.WriteHeader(cw.status)
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	_, err := cw.ResponseWriter.Write(buf)
+	return err
+}
+
+func (cw *compressWriter) close() {
+	if !cw.decided {
+		if !cw.wroteHeader && len(cw.buf) == 0 {
+			// Обработчик ничего не записал - net/http сам ответит 200
+			return
+		}
+		_ = cw.decide(len(cw.buf) >= cw.mw.minSize)
+	}
+	if cw.enc != nil {
+		_ = cw.enc.Close()
+		cw.enc.Reset(io.Discard)
+		cw.mw.pools[cw.encoding].Put(cw.enc)
+		cw.enc = nil
+	}
+}
+
+// Flush отправляет клиенту все накопленные данные. Для потоковых ответов
+// порог MinSize не применяется - решение принимается по типу контента.
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		if !cw.wroteHeader && len(cw.buf) == 0 {
+			return
+		}
+		_ = cw.decide(true)
+	}
+	if cw.enc != nil {
+		_ = cw.enc.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (cw *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := cw.ResponseWriter.(http.Hijacker); ok {
+		cw.decided = true
+		return h.Hijack()
+	}
+	return nil, nil, errors.New("http: response writer does not support hijacking")
+}
+
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
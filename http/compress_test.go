@@ -0,0 +1,66 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressionAndETag(t *testing.T) {
+	srv := NewServer(context.Background(), Config{Name: "test", Compression: CompressionConfig{Enabled: true}})
+	payload := map[string]string{"text": strings.Repeat("compress me ", 200)}
+	srv.router.Get("/data", func(w http.ResponseWriter, r *http.Request) {
+		SendData(r.Context(), w, payload, http.StatusOK)
+	})
+	srv.router.Get("/small", func(w http.ResponseWriter, r *http.Request) {
+		SendData(r.Context(), w, map[string]string{"ok": "1"}, http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/data", nil)
+	req.Header.Set(AcceptEncodingHeader, "gzip")
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, EncodingGzip, rec.Header().Get(ContentEncodingHeader))
+	etag := rec.Header().Get(ETagHeader)
+	require.True(t, strings.HasPrefix(etag, `W/"`))
+
+	zr, err := gzip.NewReader(rec.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	require.Contains(t, string(body), "compress me")
+
+	req = httptest.NewRequest(http.MethodGet, "/data", nil)
+	req.Header.Set(AcceptEncodingHeader, "gzip")
+	req.Header.Set(IfNoneMatchHeader, etag)
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNotModified, rec.Code)
+	require.Empty(t, rec.Header().Get(ContentEncodingHeader))
+	require.Zero(t, rec.Body.Len())
+
+	req = httptest.NewRequest(http.MethodGet, "/small", nil)
+	req.Header.Set(AcceptEncodingHeader, "zstd, gzip;q=0.5")
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Empty(t, rec.Header().Get(ContentEncodingHeader))
+	require.JSONEq(t, `{"ok":"1"}`, rec.Body.String())
+}
+
+func TestCompressionNegotiate(t *testing.T) {
+	m := NewCompressionMiddleware(CompressionOptions{})
+	require.Equal(t, EncodingZstd, m.negotiate("gzip, br, zstd"))
+	require.Equal(t, EncodingGzip, m.negotiate("gzip;q=1.0, br;q=0.5"))
+	require.Equal(t, EncodingZstd, m.negotiate("*"))
+	require.Equal(t, "", m.negotiate("identity"))
+	require.Equal(t, "", m.negotiate("gzip;q=0"))
+}
@@ -12,6 +12,16 @@ type Config struct {
 	Host     string `yaml:"host" env:"HTTP_HOST" env-default:"0.0.0.0"`
 	Port     string `yaml:"port" env:"HTTP_PORT" env-default:"8080"`
 	Required bool   `yaml:"required" env:"HTTP_REQUIRED" env-default:"false"`
+
+	Compression CompressionConfig `yaml:"compression"`
+}
+
+// CompressionConfig включает сжатие ответов сервера
+type CompressionConfig struct {
+	Enabled      bool     `yaml:"enabled" env:"HTTP_COMPRESSION_ENABLED" env-default:"false"`
+	MinSize      int      `yaml:"min_size" env-default:"1024"`
+	ContentTypes []string `yaml:"content_types"`
+	Encodings    []string `yaml:"encodings"`
 }
 
 // Validate проверяет корректность конфигурации
@@ -25,5 +35,10 @@ func (c Config) Validate() error {
 	if c.Port == "" {
 		return fmt.Errorf("port is empty: %w", errConfigRequired)
 	}
+	for _, enc := range c.Compression.Encodings {
+		if newEncoderFunc(enc) == nil {
+			return fmt.Errorf("unsupported compression encoding %q: %w", enc, errConfigRequired)
+		}
+	}
 	return nil
 }
@@ -1,6 +1,11 @@
 package http
 
 const (
-	ContentTypeHeader = "Content-Type"
-	TraceIDHeader     = "Trace-id"
+	ContentTypeHeader  = "Content-Type"
+	TraceIDHeader      = "Trace-id"
+	RequestIDHeader    = "X-Request-Id"
+	TenantIDHeader     = "X-Tenant-Id"
+	ETagHeader         = "ETag"
+	IfNoneMatchHeader  = "If-None-Match"
+	CacheControlHeader = "Cache-Control"
 )
@@ -1,6 +1,9 @@
 package http
 
 import (
+	"bufio"
+	"errors"
+	"net"
 	"net/http"
 	"strconv"
 	"sync"
@@ -20,6 +23,9 @@ type Metrics struct {
 	latencySec metrics.HistogramVec
 	reqBytes   metrics.Histogram
 	resBytes   metrics.Histogram
+
+	streamsActive  metrics.GaugeVec   // {kind=sse|websocket}
+	streamMessages metrics.CounterVec // {kind, direction=in|out}
 }
 
 func initHTTPMetrics() {
@@ -33,6 +39,9 @@ func initHTTPMetrics() {
 			latencySec: metrics.NewHistogramVec("http", "request_seconds", "HTTP request latency", durBuckets, []string{"method", "code"}, nil),
 			reqBytes:   metrics.NewHistogram("http", "request_bytes", "HTTP request size", sizeBuckets, nil),
 			resBytes:   metrics.NewHistogram("http", "response_bytes", "HTTP response size", sizeBuckets, nil),
+
+			streamsActive:  metrics.NewGaugeVec("http", "streams_active", "Active SSE and WebSocket connections", []string{"kind"}, nil),
+			streamMessages: metrics.NewCounterVec("http", "stream_messages_total", "SSE and WebSocket messages", []string{"kind", "direction"}, nil),
 		}
 	})
 }
@@ -74,3 +83,21 @@ func (w *rw) Write(b []byte) (int, error) {
 	w.bytes += int64(n)
 	return n, err
 }
+
+func (w *rw) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *rw) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		w.status = http.StatusSwitchingProtocols
+		return h.Hijack()
+	}
+	return nil, nil, errors.New("http: response writer does not support hijacking")
+}
+
+func (w *rw) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
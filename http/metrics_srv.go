@@ -27,7 +27,8 @@ func NewMetricsServer(_ context.Context, port string) *Server {
 			WriteTimeout: writeTimeout,
 			IdleTimeout:  idleTimeout,
 		},
-		router: router,
+		router:  router,
+		streams: newStreamRegistry(),
 	}
 	srv.WithMiddlewares(NewRecoverMiddleware())
 	srv.registerDefaultMiddlewares()
@@ -1,9 +1,13 @@
 package http
 
 import (
+	"bufio"
+	"errors"
+	"net"
 	"net/http"
 
 	"github.com/Rasikrr/core/log"
+	"github.com/Rasikrr/core/recovery"
 	"github.com/go-chi/cors"
 )
 
@@ -41,8 +45,15 @@ func (m *RecoverMiddleware) Handle(next http.Handler) http.Handler {
 		}
 
 		defer func() {
-			if err := recover(); err != nil {
-				log.Warnf(ctx, "Recovered from panic in http: %v", err)
+			if rec := recover(); rec != nil {
+				// ErrAbortHandler - штатный способ оборвать ответ, net/http обрабатывает его сам
+				if rec == http.ErrAbortHandler { //nolint: errorlint
+					panic(rec)
+				}
+				recovery.Handle(ctx, recovery.ComponentHTTP, rec, map[string]string{
+					"http.method": r.Method,
+					"http.path":   r.URL.Path,
+				})
 				if !wrappedWriter.written {
 					SendError(ctx, w, NewError("Internal Server Error", http.StatusInternalServerError))
 				} else {
@@ -77,3 +88,25 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	}
 	return rw.ResponseWriter.Write(b)
 }
+
+func (rw *responseWriter) Flush() {
+	if !rw.written {
+		rw.statusCode = http.StatusOK
+		rw.written = true
+	}
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := rw.ResponseWriter.(http.Hijacker); ok {
+		rw.written = true
+		return h.Hijack()
+	}
+	return nil, nil, errors.New("http: response writer does not support hijacking")
+}
+
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -0,0 +1,291 @@
+package http
+
+import (
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/Rasikrr/core/enum"
+	"github.com/Rasikrr/core/environment"
+	"github.com/Rasikrr/core/version"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	OpenAPIPath = "/openapi.json"
+	DocsPath    = "/docs"
+)
+
+// APIController регистрирует маршруты через API, чтобы они попали в OpenAPI спецификацию.
+// Обычные Controller продолжают работать, но в спецификации не отображаются.
+type APIController interface {
+	InitAPI(api *API)
+}
+
+// API регистрирует маршруты на chi роутере и запоминает их описание
+type API struct {
+	router   chi.Router
+	prefix   string
+	registry *routeRegistry
+}
+
+type routeRegistry struct {
+	mu     sync.RWMutex
+	routes []*route
+}
+
+type route struct {
+	method         string
+	pattern        string
+	operationID    string
+	summary        string
+	description    string
+	tags           []string
+	deprecated     bool
+	request        any
+	responses      map[int]any
+	errors         []int
+	pathParamsDecl []Parameter
+	queryParams    []Parameter
+}
+
+// RouteOption описывает маршрут для OpenAPI спецификации
+type RouteOption func(*route)
+
+func WithSummary(summary string) RouteOption {
+	return func(r *route) {
+		r.summary = summary
+	}
+}
+
+func WithDescription(description string) RouteOption {
+	return func(r *route) {
+		r.description = description
+	}
+}
+
+func WithTags(tags ...string) RouteOption {
+	return func(r *route) {
+		r.tags = append(r.tags, tags...)
+	}
+}
+
+func WithOperationID(id string) RouteOption {
+	return func(r *route) {
+		r.operationID = id
+	}
+}
+
+func WithDeprecated() RouteOption {
+	return func(r *route) {
+		r.deprecated = true
+	}
+}
+
+// WithRequest задает тип тела запроса. Передается нулевое значение типа: WithRequest(CreateUserRequest{})
+func WithRequest(body any) RouteOption {
+	return func(r *route) {
+		r.request = body
+	}
+}
+
+// WithResponse задает тип тела ответа для статуса. nil означает ответ без тела.
+// Для SuccessResponse в спецификацию попадает тип Data: WithResponse(200, NewSuccessResponse(User{}))
+func WithResponse(code int, body any) RouteOption {
+	return func(r *route) {
+		r.responses[code] = body
+	}
+}
+
+// WithErrors добавляет ответы с ErrorResponse для перечисленных статусов
+func WithErrors(codes ...int) RouteOption {
+	return func(r *route) {
+		r.errors = append(r.errors, codes...)
+	}
+}
+
+// WithPathParam описывает параметр пути. Параметры из шаблона chi добавляются автоматически,
+// опция нужна только для описания или типа, отличного от string.
+func WithPathParam(name, schemaType, description string) RouteOption {
+	return func(r *route) {
+		r.pathParamsDecl = append(r.pathParamsDecl, Parameter{
+			Name:        name,
+			In:          "path",
+			Description: description,
+			Required:    true,
+			Schema:      &Schema{Type: schemaType},
+		})
+	}
+}
+
+// WithQueryParam описывает query параметр. schemaType - string, integer, number, boolean
+func WithQueryParam(name, schemaType, description string, required bool) RouteOption {
+	return func(r *route) {
+		r.queryParams = append(r.queryParams, Parameter{
+			Name:        name,
+			In:          "query",
+			Description: description,
+			Required:    required,
+			Schema:      &Schema{Type: schemaType},
+		})
+	}
+}
+
+func newAPI(router chi.Router) *API {
+	return &API{
+		router:   router,
+		registry: &routeRegistry{},
+	}
+}
+
+// Group возвращает API, добавляющий prefix ко всем маршрутам
+func (a *API) Group(prefix string) *API {
+	return &API{
+		router:   a.router,
+		prefix:   a.prefix + prefix,
+		registry: a.registry,
+	}
+}
+
+// With возвращает API, применяющий middlewares только к маршрутам, зарегистрированным через него
+func (a *API) With(middlewares ...func(http.Handler) http.Handler) *API {
+	return &API{
+		router:   a.router.With(middlewares...),
+		prefix:   a.prefix,
+		registry: a.registry,
+	}
+}
+
+func (a *API) Get(pattern string, h http.HandlerFunc, opts ...RouteOption) {
+	a.Handle(http.MethodGet, pattern, h, opts...)
+}
+
+func (a *API) Post(pattern string, h http.HandlerFunc, opts ...RouteOption) {
+	a.Handle(http.MethodPost, pattern, h, opts...)
+}
+
+func (a *API) Put(pattern string, h http.HandlerFunc, opts ...RouteOption) {
+	a.Handle(http.MethodPut, pattern, h, opts...)
+}
+
+func (a *API) Patch(pattern string, h http.HandlerFunc, opts ...RouteOption) {
+	a.Handle(http.MethodPatch, pattern, h, opts...)
+}
+
+func (a *API) Delete(pattern string, h http.HandlerFunc, opts ...RouteOption) {
+	a.Handle(http.MethodDelete, pattern, h, opts...)
+}
+
+// Handle регистрирует обработчик на роутере и сохраняет описание маршрута
+func (a *API) Handle(method, pattern string, h http.HandlerFunc, opts ...RouteOption) {
+	full := a.prefix + pattern
+	a.router.Method(method, full, h)
+
+	rt := &route{
+		method:    strings.ToUpper(method),
+		pattern:   full,
+		responses: make(map[int]any),
+	}
+	for _, opt := range opts {
+		opt(rt)
+	}
+
+	a.registry.mu.Lock()
+	a.registry.routes = append(a.registry.routes, rt)
+	a.registry.mu.Unlock()
+}
+
+// Spec строит OpenAPI документ по зарегистрированным маршрутам
+func (a *API) Spec(info OpenAPIInfo) *OpenAPI {
+	a.registry.mu.RLock()
+	defer a.registry.mu.RUnlock()
+	return buildOpenAPI(info, a.registry.routes)
+}
+
+// WithAPIControllers регистрирует документированные контроллеры.
+// При первом вызове на роутер добавляются /openapi.json и, вне prod, /docs со Swagger UI.
+func (s *Server) WithAPIControllers(controllers ...APIController) {
+	if s.api == nil {
+		s.api = newAPI(s.router)
+		s.registerDocsRoutes()
+	}
+	for _, c := range controllers {
+		c.InitAPI(s.api)
+	}
+}
+
+// OpenAPI возвращает текущую спецификацию сервера
+func (s *Server) OpenAPI() *OpenAPI {
+	if s.api == nil {
+		return buildOpenAPI(s.openAPIInfo(), nil)
+	}
+	return s.api.Spec(s.openAPIInfo())
+}
+
+func (s *Server) openAPIInfo() OpenAPIInfo {
+	v := version.GetVersion()
+	if v == "" {
+		v = "unknown"
+	}
+	return OpenAPIInfo{
+		Title:   s.name,
+		Version: v,
+	}
+}
+
+func (s *Server) registerDocsRoutes() {
+	s.router.Get(OpenAPIPath, func(w http.ResponseWriter, _ *http.Request) {
+		bb, err := json.MarshalIndent(s.OpenAPI(), "", "  ")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set(ContentTypeHeader, "application/json")
+		_, _ = w.Write(bb)
+	})
+
+	if environment.GetEnv() == enum.EnvironmentProd {
+		return
+	}
+	s.router.Get(DocsPath, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(ContentTypeHeader, "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(swaggerUIPage))
+	})
+	s.router.Get(DocsPath+"/swagger-initializer.js", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(ContentTypeHeader, "text/javascript; charset=utf-8")
+		_, _ = w.Write([]byte(swaggerUIInitializer))
+	})
+	assets, _ := fs.Sub(swaggerUIAssets, "swaggerui")
+	s.router.Handle(DocsPath+"/*", http.StripPrefix(DocsPath, http.FileServerFS(assets)))
+}
+
+// swaggerUIAssets - swagger-ui-dist 5.18.2, встроен в бинарник, чтобы документация открывалась
+// без доступа в интернет и под строгим CSP: страница не загружает внешние и inline скрипты
+//
+//go:embed swaggerui
+var swaggerUIAssets embed.FS
+
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8"/>
+  <meta name="viewport" content="width=device-width, initial-scale=1"/>
+  <title>API docs</title>
+  <link rel="icon" type="image/png" href="` + DocsPath + `/favicon-32x32.png"/>
+  <link rel="stylesheet" href="` + DocsPath + `/swagger-ui.css"/>
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="` + DocsPath + `/swagger-ui-bundle.js"></script>
+<script src="` + DocsPath + `/swagger-initializer.js"></script>
+</body>
+</html>
+`
+
+const swaggerUIInitializer = `window.onload = () => {
+  window.ui = SwaggerUIBundle({url: "` + OpenAPIPath + `", dom_id: "#swagger-ui"});
+};
+`
@@ -0,0 +1,199 @@
+package http
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Schema описывает JSON Schema объекта в OpenAPI 3.1 документе
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	durationType      = reflect.TypeOf(time.Duration(0))
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// schemaRegistry собирает именованные структуры в components/schemas
+type schemaRegistry struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+// schemaOf возвращает схему для значения v. Именованные структуры
+// выносятся в components и возвращаются как $ref.
+func (r *schemaRegistry) schemaOf(v any) *Schema {
+	if v == nil {
+		return &Schema{}
+	}
+	return r.schemaOfType(reflect.TypeOf(v))
+}
+
+// nolint: gocyclo
+func (r *schemaRegistry) schemaOfType(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == durationType:
+		return &Schema{Type: "integer", Format: "int64"}
+	case t == rawMessageType:
+		return &Schema{}
+	case t.Implements(textMarshalerType), reflect.PointerTo(t).Implements(textMarshalerType):
+		// enum типы (enumer -text), uuid.UUID и т.п. сериализуются строкой
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: r.schemaOfType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schemaOfType(t.Elem())}
+	case reflect.Struct:
+		return r.structSchema(t)
+	default:
+		// interface{}, any и прочие типы без фиксированной формы
+		return &Schema{}
+	}
+}
+
+func (r *schemaRegistry) structSchema(t reflect.Type) *Schema {
+	if t.Name() == "" {
+		return r.buildStruct(t)
+	}
+
+	if name, ok := r.names[t]; ok {
+		return &Schema{Ref: componentRef(name)}
+	}
+
+	name := r.componentName(t)
+	r.names[t] = name
+	// Резервируем имя до построения, чтобы рекурсивные типы ссылались сами на себя
+	r.schemas[name] = &Schema{}
+	r.schemas[name] = r.buildStruct(t)
+
+	return &Schema{Ref: componentRef(name)}
+}
+
+func (r *schemaRegistry) buildStruct(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	r.collectFields(t, s)
+	return s
+}
+
+func (r *schemaRegistry) collectFields(t reflect.Type, s *Schema) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+
+		name, omitEmpty, skip := jsonFieldName(f)
+		if skip {
+			continue
+		}
+
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		// Встроенные структуры без json тега разворачиваются в родителя
+		if f.Anonymous && f.Tag.Get("json") == "" && ft.Kind() == reflect.Struct {
+			r.collectFields(ft, s)
+			continue
+		}
+
+		fs := r.schemaOfType(f.Type)
+		if desc := f.Tag.Get("description"); desc != "" && fs.Ref == "" {
+			fs.Description = desc
+		}
+		s.Properties[name] = fs
+
+		if !omitEmpty && f.Type.Kind() != reflect.Pointer {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+func (r *schemaRegistry) componentName(t reflect.Type) string {
+	name := t.Name()
+	// Generic типы содержат в имени путь пакета параметра: Page[github.com/x.Item]
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		name = name[:i]
+	}
+	if _, taken := r.schemas[name]; !taken {
+		return name
+	}
+	pkg := t.PkgPath()
+	if i := strings.LastIndexByte(pkg, '/'); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+	candidate := pkg + "." + name
+	for n := 2; ; n++ {
+		if _, taken := r.schemas[candidate]; !taken {
+			return candidate
+		}
+		candidate = pkg + "." + name + strings.Repeat("_", n-1)
+	}
+}
+
+func jsonFieldName(f reflect.StructField) (name string, omitEmpty, skip bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = f.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" || opt == "omitzero" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty, false
+}
+
+func componentRef(name string) string {
+	return "#/components/schemas/" + name
+}
@@ -0,0 +1,226 @@
+package http
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const openAPIVersion = "3.1.0"
+
+// OpenAPI - корневой объект OpenAPI 3.1 документа
+type OpenAPI struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       OpenAPIInfo          `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+type PathItem struct {
+	Get     *Operation `json:"get,omitempty"`
+	Put     *Operation `json:"put,omitempty"`
+	Post    *Operation `json:"post,omitempty"`
+	Delete  *Operation `json:"delete,omitempty"`
+	Patch   *Operation `json:"patch,omitempty"`
+	Head    *Operation `json:"head,omitempty"`
+	Options *Operation `json:"options,omitempty"`
+}
+
+type Operation struct {
+	OperationID string               `json:"operationId,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Deprecated  bool                 `json:"deprecated,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+var pathParamRe = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?}`)
+
+// buildOpenAPI собирает документ по зарегистрированным маршрутам
+func buildOpenAPI(info OpenAPIInfo, routes []*route) *OpenAPI {
+	reg := newSchemaRegistry()
+	doc := &OpenAPI{
+		OpenAPI: openAPIVersion,
+		Info:    info,
+		Paths:   make(map[string]*PathItem),
+	}
+
+	for _, rt := range routes {
+		path := openAPIPath(rt.pattern)
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[path] = item
+		}
+		item.set(rt.method, rt.operation(reg))
+	}
+
+	if len(reg.schemas) > 0 {
+		doc.Components.Schemas = reg.schemas
+	}
+	return doc
+}
+
+func (rt *route) operation(reg *schemaRegistry) *Operation {
+	op := &Operation{
+		OperationID: rt.operationID,
+		Summary:     rt.summary,
+		Description: rt.description,
+		Tags:        rt.tags,
+		Deprecated:  rt.deprecated,
+		Responses:   make(map[string]*Response),
+	}
+
+	op.Parameters = append(op.Parameters, rt.pathParams()...)
+	op.Parameters = append(op.Parameters, rt.queryParams...)
+
+	if rt.request != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  jsonContent(bodySchema(reg, rt.request)),
+		}
+	}
+
+	for _, code := range sortedCodes(rt.responses) {
+		body := rt.responses[code]
+		resp := &Response{Description: http.StatusText(code)}
+		if body != nil {
+			resp.Content = jsonContent(bodySchema(reg, body))
+		}
+		op.Responses[strconv.Itoa(code)] = resp
+	}
+
+	if len(rt.errors) > 0 {
+		errSchema := reg.schemaOf(ErrorResponse{})
+		for _, code := range rt.errors {
+			op.Responses[strconv.Itoa(code)] = &Response{
+				Description: http.StatusText(code),
+				Content:     jsonContent(errSchema),
+			}
+		}
+	}
+
+	if len(op.Responses) == 0 {
+		op.Responses[strconv.Itoa(http.StatusOK)] = &Response{Description: http.StatusText(http.StatusOK)}
+	}
+	return op
+}
+
+// pathParams объединяет явно описанные path параметры с найденными в шаблоне chi
+func (rt *route) pathParams() []Parameter {
+	declared := make(map[string]Parameter, len(rt.pathParamsDecl))
+	for _, p := range rt.pathParamsDecl {
+		declared[p.Name] = p
+	}
+
+	matches := pathParamRe.FindAllStringSubmatch(rt.pattern, -1)
+	params := make([]Parameter, 0, len(matches))
+	for _, m := range matches {
+		p, ok := declared[m[1]]
+		if !ok {
+			p = Parameter{Name: m[1], In: "path", Schema: &Schema{Type: "string"}}
+		}
+		p.Required = true
+		params = append(params, p)
+	}
+	return params
+}
+
+// bodySchema строит схему тела. SuccessResponse разворачивается,
+// чтобы в спецификации оказался тип поля Data, а не пустой объект.
+func bodySchema(reg *schemaRegistry, body any) *Schema {
+	var envelope *SuccessResponse
+	switch v := body.(type) {
+	case SuccessResponse:
+		envelope = &v
+	case *SuccessResponse:
+		envelope = v
+	}
+	if envelope == nil {
+		return reg.schemaOf(body)
+	}
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"data":    reg.schemaOf(envelope.Data),
+			"message": {Type: "string"},
+		},
+		Required: []string{"data", "message"},
+	}
+}
+
+func jsonContent(s *Schema) map[string]*MediaType {
+	return map[string]*MediaType{
+		"application/json": {Schema: s},
+	}
+}
+
+func (p *PathItem) set(method string, op *Operation) {
+	switch strings.ToUpper(method) {
+	case http.MethodGet:
+		p.Get = op
+	case http.MethodPut:
+		p.Put = op
+	case http.MethodPost:
+		p.Post = op
+	case http.MethodDelete:
+		p.Delete = op
+	case http.MethodPatch:
+		p.Patch = op
+	case http.MethodHead:
+		p.Head = op
+	case http.MethodOptions:
+		p.Options = op
+	}
+}
+
+// openAPIPath убирает из шаблона chi регулярные выражения: /users/{id:[0-9]+} -> /users/{id}
+func openAPIPath(pattern string) string {
+	return pathParamRe.ReplaceAllString(pattern, "{$1}")
+}
+
+func sortedCodes(m map[int]any) []int {
+	codes := make([]int, 0, len(m))
+	for code := range m {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	return codes
+}
@@ -0,0 +1,27 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocsServeEmbeddedAssets(t *testing.T) {
+	srv := NewServer(context.Background(), Config{Name: "test"})
+	srv.WithAPIControllers()
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, DocsPath, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotContains(t, rec.Body.String(), "https://", "docs must not load external assets")
+
+	for _, asset := range []string{"/swagger-ui-bundle.js", "/swagger-ui.css", "/swagger-initializer.js"} {
+		rec = httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, DocsPath+asset, nil))
+		require.Equal(t, http.StatusOK, rec.Code, asset)
+		require.NotEmpty(t, rec.Body.Bytes(), asset)
+	}
+}
@@ -0,0 +1,80 @@
+// Package openapitest содержит хелперы для проверки OpenAPI спецификации в тестах.
+package openapitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	coreHTTP "github.com/Rasikrr/core/http"
+)
+
+// UpdateEnv - переменная окружения, при установке которой golden файл перезаписывается
+const UpdateEnv = "UPDATE_OPENAPI_GOLDEN"
+
+// AssertGolden запрашивает /openapi.json у handler и сравнивает ответ с golden файлом.
+// Тест падает, если спецификация разошлась с закоммиченной версией.
+// Для обновления файла: UPDATE_OPENAPI_GOLDEN=1 go test ./...
+func AssertGolden(t testing.TB, handler http.Handler, goldenPath string) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, coreHTTP.OpenAPIPath, nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	res := rec.Result()
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("openapitest: GET %s returned %d", coreHTTP.OpenAPIPath, res.StatusCode)
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("openapitest: read spec: %v", err)
+	}
+	got, err := normalize(body)
+	if err != nil {
+		t.Fatalf("openapitest: served spec is not valid json: %v", err)
+	}
+
+	if os.Getenv(UpdateEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(goldenPath), 0o755); err != nil {
+			t.Fatalf("openapitest: create golden dir: %v", err)
+		}
+		if err := os.WriteFile(goldenPath, got, 0o600); err != nil {
+			t.Fatalf("openapitest: write golden file: %v", err)
+		}
+		return
+	}
+
+	raw, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("openapitest: read golden file (run with %s=1 to create it): %v", UpdateEnv, err)
+	}
+	want, err := normalize(raw)
+	if err != nil {
+		t.Fatalf("openapitest: golden file is not valid json: %v", err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Fatalf("openapitest: served spec drifted from %s (run with %s=1 to update)\n--- served ---\n%s",
+			goldenPath, UpdateEnv, got)
+	}
+}
+
+// normalize приводит json к каноничному виду, чтобы форматирование golden файла не влияло на сравнение
+func normalize(bb []byte) ([]byte, error) {
+	var v any
+	if err := json.Unmarshal(bb, &v); err != nil {
+		return nil, err
+	}
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
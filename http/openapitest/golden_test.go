@@ -0,0 +1,58 @@
+package openapitest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	coreHTTP "github.com/Rasikrr/core/http"
+)
+
+type user struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name" description:"display name"`
+	Email     *string   `json:"email,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Tags      []string  `json:"tags"`
+}
+
+type createUserRequest struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type usersController struct{}
+
+func (usersController) InitAPI(api *coreHTTP.API) {
+	v1 := api.Group("/api/v1")
+	v1.Get("/users/{id:[0-9]+}", noop,
+		coreHTTP.WithSummary("Get user"),
+		coreHTTP.WithTags("users"),
+		coreHTTP.WithPathParam("id", "integer", "user id"),
+		coreHTTP.WithResponse(http.StatusOK, coreHTTP.NewSuccessResponse(user{})),
+		coreHTTP.WithErrors(http.StatusNotFound),
+	)
+	v1.Post("/users", noop,
+		coreHTTP.WithSummary("Create user"),
+		coreHTTP.WithTags("users"),
+		coreHTTP.WithRequest(createUserRequest{}),
+		coreHTTP.WithResponse(http.StatusCreated, user{}),
+		coreHTTP.WithErrors(http.StatusBadRequest, http.StatusConflict),
+	)
+	v1.Get("/users", noop,
+		coreHTTP.WithQueryParam("limit", "integer", "page size", false),
+		coreHTTP.WithResponse(http.StatusOK, []user{}),
+	)
+}
+
+func noop(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestAssertGolden(t *testing.T) {
+	srv := coreHTTP.NewServer(context.Background(), coreHTTP.Config{Name: "users"})
+	srv.WithAPIControllers(usersController{})
+
+	AssertGolden(t, srv.Handler(), "testdata/openapi.golden.json")
+}
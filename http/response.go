@@ -11,28 +11,29 @@ import (
 )
 
 func SendData(ctx context.Context, w http.ResponseWriter, data interface{}, statusCode int) {
+	var (
+		bb  []byte
+		err error
+	)
+	if marshaller, ok := data.(json.Marshaler); ok {
+		bb, err = marshaller.MarshalJSON()
+	} else {
+		bb, err = json.Marshal(data)
+	}
+	if err != nil {
+		SendError(ctx, w, err)
+		return
+	}
+
 	traceID, ok := coreCtx.TraceID(ctx)
 	if ok {
 		w.Header().Set(TraceIDHeader, traceID)
 	}
 	w.Header().Set(ContentTypeHeader, "application/json")
-	w.WriteHeader(statusCode)
-
-	marshaller, ok := data.(json.Marshaler)
-	if ok {
-		bb, err := marshaller.MarshalJSON()
-		if err != nil {
-			SendError(ctx, w, err)
-			return
-		}
-		w.Write(bb)
-		return
-	}
-	bb, err := json.Marshal(data)
-	if err != nil {
-		SendError(ctx, w, err)
+	if writeNotModified(ctx, w, statusCode, bb) {
 		return
 	}
+	w.WriteHeader(statusCode)
 	w.Write(bb)
 }
 
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/Rasikrr/core/log"
@@ -19,11 +20,22 @@ const (
 )
 
 type Server struct {
-	name   string
-	port   string
-	host   string
-	srv    *http.Server
-	router *chi.Mux
+	name    string
+	port    string
+	host    string
+	srv     *http.Server
+	router  *chi.Mux
+	api     *API
+	streams *streamRegistry
+
+	// mounts монтируются при Start или Handler: chi запрещает добавлять middleware после первого маршрута
+	mu     sync.Mutex
+	mounts []mount
+}
+
+type mount struct {
+	pattern string
+	handler http.Handler
 }
 
 func NewServer(
@@ -43,16 +55,25 @@ func NewServer(
 			WriteTimeout: writeTimeout,
 			IdleTimeout:  idleTimeout,
 		},
-		router: router,
+		router:  router,
+		streams: newStreamRegistry(),
 	}
-	srv.WithMiddlewares(NewRecoverMiddleware())
-
 	srv.setupTracingMiddleware()
 	srv.setupSentryMiddleware()
+	// Recover стоит внутри sentry middleware: паника не доходит до sentryhttp и не отправляется дважды,
+	// а событие из recovery получает hub запроса
+	srv.WithMiddlewares(NewRecoverMiddleware())
 
 	initHTTPMetrics()
 	srv.WithMiddlewares(m)
 	srv.registerDefaultMiddlewares()
+	if cfg.Compression.Enabled {
+		srv.WithMiddlewares(NewCompressionMiddleware(CompressionOptions{
+			MinSize:      cfg.Compression.MinSize,
+			ContentTypes: cfg.Compression.ContentTypes,
+			Encodings:    cfg.Compression.Encodings,
+		}))
+	}
 	return srv
 }
 
@@ -68,8 +89,32 @@ func (s *Server) WithMiddlewares(middlewares ...Middleware) {
 	}
 }
 
+// Mount монтирует обработчик на все пути под pattern. URL запроса передается без изменений.
+// Обработчик подключается при Start, поэтому WithMiddlewares можно вызывать и после Mount.
+func (s *Server) Mount(pattern string, h http.Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mounts = append(s.mounts, mount{pattern: pattern, handler: h})
+}
+
+// Handler возвращает роутер сервера, например для тестов через httptest. Подключает обработчики из Mount.
+func (s *Server) Handler() http.Handler {
+	s.mountPending()
+	return s.router
+}
+
+func (s *Server) mountPending() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, m := range s.mounts {
+		s.router.Mount(m.pattern, m.handler)
+	}
+	s.mounts = nil
+}
+
 func (s *Server) Start(ctx context.Context) error {
 	log.Infof(ctx, "starting %s http server on %s", s.name, address(s.host, s.port))
+	s.mountPending()
 	addHealthRoute(s.router)
 	if err := s.srv.ListenAndServe(); err != nil {
 		if errors.Is(err, http.ErrServerClosed) {
@@ -84,9 +129,14 @@ func (s *Server) registerDefaultMiddlewares() {
 	// use default chi middlewares
 	s.router.Use(middleware.RealIP)
 	s.router.Use(middleware.Logger)
+	s.router.Use(conditionalRequestMiddleware)
 }
 
 func (s *Server) Close(ctx context.Context) error {
+	// SSE и WebSocket соединения не завершаются сами, Shutdown ждал бы их до таймаута
+	if err := s.streams.shutdown(ctx); err != nil {
+		log.Warnf(ctx, "%s HTTP server: long-lived connections were not drained: %v", s.name, err)
+	}
 	if err := s.srv.Shutdown(ctx); err != nil {
 		log.Infof(ctx, "HTTP server shutdown error: %v", err)
 		return fmt.Errorf("HTTP server shutdown error: %w", err)
@@ -0,0 +1,33 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type headerMiddleware struct{}
+
+func (headerMiddleware) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Test", "1")
+		next.ServeHTTP(w, r)
+	})
+}
+
+func TestMiddlewaresAfterMount(t *testing.T) {
+	srv := NewServer(context.Background(), Config{Name: "test"})
+	// Так приложение монтирует webhook Telegram и gRPC gateway до пользовательских middleware
+	srv.Mount("/webhook", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	require.NotPanics(t, func() { srv.WithMiddlewares(headerMiddleware{}) })
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhook/token", nil))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	require.Equal(t, "1", rec.Header().Get("X-Test"))
+}
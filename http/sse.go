@@ -0,0 +1,208 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	LastEventIDHeader = "Last-Event-ID"
+
+	// SSEShutdownEvent отправляется клиентам при остановке сервера, чтобы они переподключились к другому поду
+	SSEShutdownEvent = "shutdown"
+
+	defaultSSEHeartbeat = 15 * time.Second
+	sseWriteTimeout     = 10 * time.Second
+)
+
+// SSEEvent - событие Server-Sent Events. Data типа string и []byte отправляется как есть, остальное - как JSON.
+type SSEEvent struct {
+	ID    string
+	Event string
+	Data  any
+	Retry time.Duration
+}
+
+// SSEOptions настраивает SSE поток
+type SSEOptions struct {
+	// Heartbeat - интервал комментариев-пингов, не дающих прокси закрыть соединение
+	Heartbeat time.Duration
+	// Retry - рекомендуемая клиенту задержка переподключения
+	Retry time.Duration
+}
+
+// SSEHandler обслуживает поток. ctx отменяется при отключении клиента или остановке сервера.
+type SSEHandler func(ctx context.Context, stream *SSEStream) error
+
+// SSEStream пишет события клиенту. Методы безопасны для конкурентного использования.
+type SSEStream struct {
+	mu          sync.Mutex
+	w           http.ResponseWriter
+	rc          *http.ResponseController
+	lastEventID string
+}
+
+// LastEventID возвращает ID последнего полученного клиентом события для продолжения потока
+func (s *SSEStream) LastEventID() string {
+	return s.lastEventID
+}
+
+// Send отправляет событие и сразу сбрасывает буфер
+func (s *SSEStream) Send(e SSEEvent) error {
+	var buf bytes.Buffer
+	if e.ID != "" {
+		writeSSEField(&buf, "id", e.ID)
+	}
+	if e.Event != "" {
+		writeSSEField(&buf, "event", e.Event)
+	}
+	if e.Retry > 0 {
+		writeSSEField(&buf, "retry", strconv.FormatInt(e.Retry.Milliseconds(), 10))
+	}
+
+	data, err := sseData(e.Data)
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(data, "\n") {
+		writeSSEField(&buf, "data", line)
+	}
+	buf.WriteByte('\n')
+
+	if err := s.write(buf.Bytes()); err != nil {
+		return err
+	}
+	m.streamMessages.WithLabelValues(streamKindSSE, "out").Inc()
+	return nil
+}
+
+func (s *SSEStream) heartbeat() error {
+	return s.write([]byte(": ping\n\n"))
+}
+
+func (s *SSEStream) write(b []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_ = s.rc.SetWriteDeadline(time.Now().Add(sseWriteTimeout))
+	if _, err := s.w.Write(b); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+// SSE возвращает обработчик, переводящий запрос в поток Server-Sent Events.
+// Поток отслеживается сервером: Server.Close отправляет клиентам событие shutdown и дожидается завершения.
+func (s *Server) SSE(handler SSEHandler, opts SSEOptions) http.HandlerFunc {
+	if opts.Heartbeat <= 0 {
+		opts.Heartbeat = defaultSSEHeartbeat
+	}
+
+	initHTTPMetrics()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, done, err := s.streams.track(r.Context())
+		if err != nil {
+			SendError(r.Context(), w, NewError(err.Error(), http.StatusServiceUnavailable))
+			return
+		}
+		defer done()
+
+		rc := http.NewResponseController(w)
+		// Общий WriteTimeout сервера оборвал бы поток, дедлайн выставляется на каждую запись
+		if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			SendError(ctx, w, err)
+			return
+		}
+
+		h := w.Header()
+		h.Set(ContentTypeHeader, "text/event-stream")
+		h.Set(CacheControlHeader, "no-cache")
+		h.Set("Connection", "keep-alive")
+		h.Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+
+		stream := &SSEStream{
+			w:           w,
+			rc:          rc,
+			lastEventID: r.Header.Get(LastEventIDHeader),
+		}
+		if err := stream.write([]byte(retryPreamble(opts.Retry))); err != nil {
+			return
+		}
+
+		m.streamsActive.WithLabelValues(streamKindSSE).Inc()
+		defer m.streamsActive.WithLabelValues(streamKindSSE).Dec()
+
+		ctx, span := startStreamSpan(ctx, streamKindSSE, r.URL.Path)
+		hbCtx, stopHeartbeat := context.WithCancel(ctx)
+		hbDone := make(chan struct{})
+		go func() {
+			defer close(hbDone)
+			stream.runHeartbeat(hbCtx, opts.Heartbeat)
+		}()
+
+		err = handler(ctx, stream)
+		stopHeartbeat()
+		<-hbDone
+
+		if isShutdown(ctx) {
+			_ = stream.Send(SSEEvent{Event: SSEShutdownEvent, Data: "server is shutting down"})
+		}
+		finishStream(ctx, span, streamKindSSE, err)
+	}
+}
+
+func (s *SSEStream) runHeartbeat(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.heartbeat(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func retryPreamble(retry time.Duration) string {
+	if retry <= 0 {
+		// Комментарий заставляет прокси и браузер сразу получить заголовки
+		return ": connected\n\n"
+	}
+	return "retry: " + strconv.FormatInt(retry.Milliseconds(), 10) + "\n\n"
+}
+
+func sseData(v any) (string, error) {
+	switch d := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return d, nil
+	case []byte:
+		return string(d), nil
+	default:
+		bb, err := json.Marshal(d)
+		if err != nil {
+			return "", err
+		}
+		return string(bb), nil
+	}
+}
+
+func writeSSEField(buf *bytes.Buffer, field, value string) {
+	buf.WriteString(field)
+	buf.WriteString(": ")
+	buf.WriteString(strings.ReplaceAll(value, "\r", ""))
+	buf.WriteByte('\n')
+}
@@ -0,0 +1,120 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/Rasikrr/core/log"
+	"github.com/Rasikrr/core/sentry"
+	"github.com/Rasikrr/core/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	streamKindSSE       = "sse"
+	streamKindWebSocket = "websocket"
+
+	streamTracerName = "github.com/Rasikrr/core/http"
+)
+
+// ErrServerShuttingDown - причина отмены контекста долгоживущих соединений при Server.Close
+var ErrServerShuttingDown = errors.New("http: server is shutting down")
+
+// streamRegistry отслеживает SSE и WebSocket соединения, которые http.Server.Shutdown не дожидается:
+// SSE никогда не становится idle, а WebSocket после hijack вообще не виден серверу.
+type streamRegistry struct {
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+	closing bool
+	seq     uint64
+	cancels map[uint64]context.CancelCauseFunc
+}
+
+func newStreamRegistry() *streamRegistry {
+	return &streamRegistry{cancels: make(map[uint64]context.CancelCauseFunc)}
+}
+
+// track регистрирует соединение. Возвращаемый контекст отменяется с ErrServerShuttingDown при закрытии сервера.
+func (r *streamRegistry) track(ctx context.Context) (context.Context, func(), error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closing {
+		return nil, nil, ErrServerShuttingDown
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	r.seq++
+	id := r.seq
+	r.cancels[id] = cancel
+	r.wg.Add(1)
+
+	done := func() {
+		r.mu.Lock()
+		delete(r.cancels, id)
+		r.mu.Unlock()
+		cancel(nil)
+		r.wg.Done()
+	}
+	return ctx, done, nil
+}
+
+// shutdown уведомляет все соединения и ждет их завершения или отмены ctx
+func (r *streamRegistry) shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	r.closing = true
+	for _, cancel := range r.cancels {
+		cancel(ErrServerShuttingDown)
+	}
+	r.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func isShutdown(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), ErrServerShuttingDown)
+}
+
+func startStreamSpan(ctx context.Context, kind, path string) (context.Context, trace.Span) {
+	// Без трейсинга span не создается. Span из ctx принадлежит вызывающему, его нельзя завершать здесь.
+	if !tracing.Enabled() {
+		return ctx, trace.SpanFromContext(context.Background())
+	}
+	return tracing.GetTracer(streamTracerName).Start(ctx,
+		kind+" "+path,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("stream.kind", kind),
+			attribute.String("http.route", path),
+		),
+	)
+}
+
+// finishStream логирует ошибку обработчика и закрывает span
+func finishStream(ctx context.Context, span trace.Span, kind string, err error) {
+	defer span.End()
+	if err == nil || errors.Is(err, context.Canceled) || isShutdown(ctx) {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	if hub := sentry.GetHubFromContext(ctx); hub != nil {
+		hub.Scope().SetTag("http.stream", kind)
+	}
+	// Ошибка уходит в Sentry через sentry handler логгера
+	log.Error(ctx, "stream handler error", log.String("kind", kind), log.Err(err))
+}
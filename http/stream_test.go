@@ -0,0 +1,107 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSSEResumeAndShutdown(t *testing.T) {
+	srv := NewServer(context.Background(), Config{Name: "test"})
+	srv.router.Get("/events", srv.SSE(func(ctx context.Context, stream *SSEStream) error {
+		if err := stream.Send(SSEEvent{ID: "2", Event: "resumed", Data: stream.LastEventID()}); err != nil {
+			return err
+		}
+		<-ctx.Done()
+		return nil
+	}, SSEOptions{Heartbeat: 10 * time.Millisecond}))
+
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/events", nil)
+	require.NoError(t, err)
+	req.Header.Set(LastEventIDHeader, "1")
+	res, err := ts.Client().Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, "text/event-stream", res.Header.Get(ContentTypeHeader))
+
+	reader := bufio.NewReader(res.Body)
+	readUntil := func(prefix string) string {
+		for {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			if strings.HasPrefix(line, prefix) {
+				return strings.TrimSpace(strings.TrimPrefix(line, prefix))
+			}
+		}
+	}
+	require.Equal(t, "resumed", readUntil("event: "))
+	require.Equal(t, "1", readUntil("data: "))
+	readUntil(": ping")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, srv.Close(ctx))
+	require.Equal(t, SSEShutdownEvent, readUntil("event: "))
+}
+
+func TestWebSocketEchoAndShutdown(t *testing.T) {
+	srv := NewServer(context.Background(), Config{Name: "test"})
+	srv.router.Get("/ws", srv.WebSocket(func(ctx context.Context, conn *WebSocketConn) error {
+		for {
+			typ, data, err := conn.Read(ctx)
+			if err != nil {
+				return err
+			}
+			if err := conn.Send(ctx, typ, data); err != nil {
+				return err
+			}
+		}
+	}, WebSocketOptions{}))
+
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	client, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(ts.URL, "http")+"/ws", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, client.Write(ctx, websocket.MessageText, []byte("hello")))
+	_, data, err := client.Read(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(data))
+
+	// Закрывающее рукопожатие требует, чтобы клиент читал во время остановки сервера
+	readErr := make(chan error, 1)
+	go func() {
+		_, _, err := client.Read(ctx)
+		readErr <- err
+	}()
+	require.NoError(t, srv.Close(ctx))
+	require.Equal(t, websocket.StatusGoingAway, websocket.CloseStatus(<-readErr))
+}
+
+func TestStreamSpanWithoutTracingKeepsCallerSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	ctx, parent := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test").Start(context.Background(), "caller")
+
+	ctx, span := startStreamSpan(ctx, "sse", "/events")
+	finishStream(ctx, span, "sse", errors.New("boom"))
+
+	require.Empty(t, recorder.Ended(), "span of the caller is not ended by the stream")
+	parent.End()
+	require.Empty(t, recorder.Ended()[0].Events(), "stream error is not recorded on the caller span")
+}
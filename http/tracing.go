@@ -1,9 +1,11 @@
 package http
 
 import (
+	"context"
 	"net/http"
 
 	coreCtx "github.com/Rasikrr/core/context"
+	"github.com/Rasikrr/core/log"
 	"github.com/Rasikrr/core/tracing"
 	"github.com/google/uuid"
 	"github.com/riandyrn/otelchi"
@@ -39,6 +41,25 @@ func traceCtxMiddleware(next http.Handler) http.Handler {
 		}
 
 		ctx = coreCtx.WithTraceID(ctx, traceID)
+		ctx = withRequestLogAttrs(ctx, w, r)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// withRequestLogAttrs добавляет в логи запроса request_id из заголовка или новый и tenant_id, если он передан.
+// Request ID возвращается клиенту в ответе.
+func withRequestLogAttrs(ctx context.Context, w http.ResponseWriter, r *http.Request) context.Context {
+	requestID := r.Header.Get(RequestIDHeader)
+	if requestID == "" {
+		requestID = uuid.New().String()
+		// Заголовок нужен и дальше по цепочке, например gateway передаст его в gRPC metadata
+		r.Header.Set(RequestIDHeader, requestID)
+	}
+	w.Header().Set(RequestIDHeader, requestID)
+
+	attrs := []log.Attr{log.String(log.KeyRequestID, requestID)}
+	if tenantID := r.Header.Get(TenantIDHeader); tenantID != "" {
+		attrs = append(attrs, log.String(log.KeyTenantID, tenantID))
+	}
+	return log.WithContextAttrs(ctx, attrs...)
+}
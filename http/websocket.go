@@ -0,0 +1,287 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+const (
+	defaultWSPingInterval = 30 * time.Second
+	defaultWSWriteTimeout = 10 * time.Second
+	defaultWSReadTimeout  = time.Minute
+	defaultWSReadLimit    = 1 << 20
+	defaultWSSendBuffer   = 64
+)
+
+type (
+	WSMessageType = websocket.MessageType
+	WSStatusCode  = websocket.StatusCode
+)
+
+const (
+	WSText   = websocket.MessageText
+	WSBinary = websocket.MessageBinary
+)
+
+// ErrWSClosed возвращается при отправке в уже закрытое соединение
+var ErrWSClosed = errors.New("http: websocket connection closed")
+
+// WebSocketOptions настраивает WebSocket соединение
+type WebSocketOptions struct {
+	// PingInterval - интервал ping. Pong обрабатывается только пока обработчик читает сообщения.
+	PingInterval time.Duration
+	// ReadTimeout - максимальное время ожидания сообщения от клиента
+	ReadTimeout time.Duration
+	// WriteTimeout - дедлайн записи одного сообщения
+	WriteTimeout time.Duration
+	// ReadLimit - максимальный размер входящего сообщения в байтах
+	ReadLimit int64
+	// SendBuffer - размер очереди исходящих сообщений. При заполнении Send блокируется (backpressure).
+	SendBuffer int
+	// OriginPatterns - разрешенные Origin помимо хоста запроса
+	OriginPatterns []string
+	Subprotocols   []string
+}
+
+// WebSocketHandler обслуживает соединение. При остановке сервера соединение закрывается с кодом 1001
+// и Read возвращает ошибку, после чего обработчик должен завершиться.
+type WebSocketHandler func(ctx context.Context, conn *WebSocketConn) error
+
+type wsMessage struct {
+	typ  WSMessageType
+	data []byte
+}
+
+// WebSocketConn - соединение с ограниченной очередью отправки, ping/pong и дедлайнами чтения/записи
+type WebSocketConn struct {
+	conn *websocket.Conn
+	opts WebSocketOptions
+
+	out       chan wsMessage
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// Read читает следующее сообщение с учетом ReadTimeout
+func (c *WebSocketConn) Read(ctx context.Context) (WSMessageType, []byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.opts.ReadTimeout)
+	defer cancel()
+
+	typ, data, err := c.conn.Read(ctx)
+	if err != nil {
+		return typ, nil, err
+	}
+	m.streamMessages.WithLabelValues(streamKindWebSocket, "in").Inc()
+	return typ, data, nil
+}
+
+// ReadJSON читает сообщение и декодирует его в v
+func (c *WebSocketConn) ReadJSON(ctx context.Context, v any) error {
+	_, data, err := c.Read(ctx)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Send ставит сообщение в очередь отправки. Если очередь заполнена, ждет освобождения или отмены ctx.
+func (c *WebSocketConn) Send(ctx context.Context, typ WSMessageType, data []byte) error {
+	select {
+	case <-c.closed:
+		return ErrWSClosed
+	default:
+	}
+
+	select {
+	case c.out <- wsMessage{typ: typ, data: data}:
+		return nil
+	case <-c.closed:
+		return ErrWSClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SendJSON кодирует v и ставит его в очередь как текстовое сообщение
+func (c *WebSocketConn) SendJSON(ctx context.Context, v any) error {
+	bb, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.Send(ctx, WSText, bb)
+}
+
+// Close закрывает соединение с кодом и причиной
+func (c *WebSocketConn) Close(code WSStatusCode, reason string) error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		err = c.conn.Close(code, reason)
+	})
+	return err
+}
+
+func (c *WebSocketConn) writeLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			c.drain()
+			return
+		case <-c.closed:
+			return
+		case msg := <-c.out:
+			if err := c.write(context.WithoutCancel(ctx), msg); err != nil {
+				_ = c.Close(websocket.StatusInternalError, "write failed")
+				return
+			}
+		}
+	}
+}
+
+// drain дописывает сообщения, поставленные в очередь до завершения обработчика
+func (c *WebSocketConn) drain() {
+	for {
+		select {
+		case msg := <-c.out:
+			if err := c.write(context.Background(), msg); err != nil {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (c *WebSocketConn) write(ctx context.Context, msg wsMessage) error {
+	ctx, cancel := context.WithTimeout(ctx, c.opts.WriteTimeout)
+	defer cancel()
+	if err := c.conn.Write(ctx, msg.typ, msg.data); err != nil {
+		return err
+	}
+	m.streamMessages.WithLabelValues(streamKindWebSocket, "out").Inc()
+	return nil
+}
+
+func (c *WebSocketConn) pingLoop(ctx context.Context) {
+	ticker := time.NewTicker(c.opts.PingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.closed:
+			return
+		case <-ticker.C:
+			pctx, cancel := context.WithTimeout(ctx, c.opts.WriteTimeout)
+			err := c.conn.Ping(pctx)
+			cancel()
+			if err != nil {
+				_ = c.Close(websocket.StatusPolicyViolation, "pong timeout")
+				return
+			}
+		}
+	}
+}
+
+// WebSocket возвращает обработчик, выполняющий upgrade соединения.
+// Соединение отслеживается сервером: Server.Close закрывает его с кодом 1001 (going away).
+func (s *Server) WebSocket(handler WebSocketHandler, opts WebSocketOptions) http.HandlerFunc {
+	opts = withWebSocketDefaults(opts)
+	initHTTPMetrics()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		trackCtx, done, err := s.streams.track(r.Context())
+		if err != nil {
+			SendError(r.Context(), w, NewError(err.Error(), http.StatusServiceUnavailable))
+			return
+		}
+		defer done()
+
+		ws, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+			OriginPatterns: opts.OriginPatterns,
+			Subprotocols:   opts.Subprotocols,
+		})
+		if err != nil {
+			// Accept уже записал ответ с ошибкой
+			return
+		}
+		ws.SetReadLimit(opts.ReadLimit)
+
+		conn := &WebSocketConn{
+			conn:   ws,
+			opts:   opts,
+			out:    make(chan wsMessage, opts.SendBuffer),
+			closed: make(chan struct{}),
+		}
+
+		m.streamsActive.WithLabelValues(streamKindWebSocket).Inc()
+		defer m.streamsActive.WithLabelValues(streamKindWebSocket).Dec()
+
+		// Отмена контекста во время Read закрывает соединение без close frame,
+		// поэтому при остановке сервера соединение закрывается явно с кодом 1001,
+		// а обработчик получает ошибку из Read.
+		ctx, span := startStreamSpan(r.Context(), streamKindWebSocket, r.URL.Path)
+		loopCtx, stopLoops := context.WithCancel(ctx)
+		var wg sync.WaitGroup
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			conn.writeLoop(loopCtx)
+		}()
+		go func() {
+			defer wg.Done()
+			conn.pingLoop(loopCtx)
+		}()
+		go func() {
+			defer wg.Done()
+			select {
+			case <-trackCtx.Done():
+				if isShutdown(trackCtx) {
+					_ = conn.Close(websocket.StatusGoingAway, "server is shutting down")
+				}
+			case <-loopCtx.Done():
+			}
+		}()
+
+		err = handler(ctx, conn)
+		stopLoops()
+		wg.Wait()
+
+		if err != nil {
+			_ = conn.Close(websocket.StatusInternalError, "internal error")
+		} else {
+			_ = conn.Close(websocket.StatusNormalClosure, "")
+		}
+
+		if websocket.CloseStatus(err) != -1 || isShutdown(trackCtx) {
+			// Соединение закрыто клиентом или сервером при остановке - это не ошибка обработчика
+			err = nil
+		}
+		finishStream(ctx, span, streamKindWebSocket, err)
+	}
+}
+
+func withWebSocketDefaults(opts WebSocketOptions) WebSocketOptions {
+	if opts.PingInterval <= 0 {
+		opts.PingInterval = defaultWSPingInterval
+	}
+	if opts.ReadTimeout <= 0 {
+		opts.ReadTimeout = defaultWSReadTimeout
+	}
+	if opts.WriteTimeout <= 0 {
+		opts.WriteTimeout = defaultWSWriteTimeout
+	}
+	if opts.ReadLimit <= 0 {
+		opts.ReadLimit = defaultWSReadLimit
+	}
+	if opts.SendBuffer <= 0 {
+		opts.SendBuffer = defaultWSSendBuffer
+	}
+	return opts
+}
@@ -0,0 +1,51 @@
+package httpclient
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/Rasikrr/core/resilience"
+)
+
+// ErrCircuitOpen возвращается без отправки запроса, пока breaker хоста открыт
+var ErrCircuitOpen = resilience.ErrCircuitOpen
+
+// breakers держит отдельный breaker на каждый хост: отказ одной зависимости не блокирует остальные
+type breakers struct {
+	client string
+	cfg    BreakerConfig
+
+	mu    sync.Mutex
+	hosts map[string]*resilience.CircuitBreaker
+}
+
+func (b *breakers) get(host string) *resilience.CircuitBreaker {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	cb, ok := b.hosts[host]
+	if !ok {
+		// Окно из FailureThreshold вызовов с порогом 100% - размыкание после N ошибок подряд
+		cb = resilience.NewCircuitBreaker(resilience.BreakerConfig{
+			Name:             b.client + ":" + host,
+			WindowSize:       b.cfg.FailureThreshold,
+			MinCalls:         b.cfg.FailureThreshold,
+			FailureRate:      1,
+			OpenTimeout:      b.cfg.OpenTimeout,
+			HalfOpenMaxCalls: 1,
+		})
+		b.hosts[host] = cb
+	}
+	return cb
+}
+
+// breakerResult - результат попытки с точки зрения breaker'а: 5xx считается отказом хоста
+func breakerResult(resp *http.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("%w: %s", resilience.ErrHTTPServer, resp.Status)
+	}
+	return nil
+}
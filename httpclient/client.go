@@ -0,0 +1,64 @@
+// Package httpclient собирает *http.Client для исходящих запросов с трейсингом,
+// метриками, breadcrumbs Sentry, повторами идемпотентных запросов и circuit breaker'ом.
+//
+// Использование:
+//
+//	client := httpclient.New(httpclient.Config{Name: "billing"})
+//	ctx = httpclient.WithRoute(ctx, "/invoices/{id}")
+//	var invoice Invoice
+//	err := httpclient.GetJSON(ctx, client, billingURL+"/invoices/"+id, &invoice)
+package httpclient
+
+import (
+	"net/http"
+
+	"github.com/Rasikrr/core/resilience"
+)
+
+type options struct {
+	base http.RoundTripper
+}
+
+type Option func(*options)
+
+// WithTransport задает базовый транспорт, поверх которого добавляется инструментирование
+func WithTransport(rt http.RoundTripper) Option {
+	return func(o *options) {
+		o.base = rt
+	}
+}
+
+// New возвращает клиент с инструментированным транспортом.
+// http.Client.Timeout не выставляется: он ограничил бы все попытки вместе, общий дедлайн задается контекстом.
+func New(cfg Config, opts ...Option) *http.Client {
+	return &http.Client{
+		Transport: NewTransport(cfg, opts...),
+	}
+}
+
+// NewTransport возвращает инструментированный http.RoundTripper для использования в сторонних клиентах
+func NewTransport(cfg Config, opts ...Option) http.RoundTripper {
+	initHTTPClientMetrics()
+	cfg = cfg.withDefaults()
+
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.base == nil {
+		o.base = http.DefaultTransport.(*http.Transport).Clone()
+	}
+
+	t := &transport{
+		base: o.base,
+		cfg:  cfg,
+	}
+	if cfg.CircuitBreaker.Enabled {
+		t.breakers = &breakers{
+			client: cfg.Name,
+			cfg:    cfg.CircuitBreaker,
+			hosts:  make(map[string]*resilience.CircuitBreaker),
+		}
+	}
+	return t
+}
@@ -0,0 +1,110 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	coreCtx "github.com/Rasikrr/core/context"
+	coreHTTP "github.com/Rasikrr/core/http"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type user struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestDoJSONRetriesIdempotentRequests(t *testing.T) {
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "trace-1", r.Header.Get(coreHTTP.TraceIDHeader))
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		coreHTTP.SendData(r.Context(), w, coreHTTP.NewSuccessResponse(user{ID: "1", Name: "Alice"}), http.StatusOK)
+	}))
+	defer ts.Close()
+
+	client := New(Config{
+		Name:  "test",
+		Retry: RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond},
+	})
+
+	ctx := coreCtx.WithTraceID(context.Background(), "trace-1")
+	var u user
+	require.NoError(t, GetJSON(ctx, client, ts.URL+"/users/1", &u))
+	require.Equal(t, user{ID: "1", Name: "Alice"}, u)
+	require.EqualValues(t, 3, calls.Load())
+
+	// POST без Idempotency-Key не повторяется
+	calls.Store(0)
+	err := PostJSON(ctx, client, ts.URL+"/users", user{Name: "Bob"}, nil)
+	var httpErr *coreHTTP.Error
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusServiceUnavailable, httpErr.StatusCode())
+	require.EqualValues(t, 1, calls.Load())
+}
+
+func TestDoJSONDecodesErrorResponse(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		coreHTTP.SendError(r.Context(), w, coreHTTP.NewError("user not found", http.StatusNotFound))
+	}))
+	defer ts.Close()
+
+	err := GetJSON(context.Background(), New(Config{}), ts.URL, &user{})
+	var httpErr *coreHTTP.Error
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusNotFound, httpErr.Code)
+	require.Equal(t, "user not found", httpErr.Message)
+}
+
+func TestCircuitBreakerAndRequestTimeout(t *testing.T) {
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer ts.Close()
+
+	client := New(Config{
+		Name:           "breaker",
+		Retry:          RetryConfig{MaxAttempts: 1},
+		CircuitBreaker: BreakerConfig{Enabled: true, FailureThreshold: 2, OpenTimeout: time.Minute},
+	})
+
+	ctx := WithRequestTimeout(context.Background(), 20*time.Millisecond)
+	for range 2 {
+		err := GetJSON(ctx, client, ts.URL, nil)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	}
+
+	err := GetJSON(ctx, client, ts.URL, nil)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	require.EqualValues(t, 2, calls.Load())
+}
+
+func TestRequestWithoutTracingKeepsCallerSpan(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	ctx, parent := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test").Start(context.Background(), "caller")
+
+	err := GetJSON(ctx, New(Config{}), ts.URL, &user{})
+	require.Error(t, err)
+	require.Empty(t, recorder.Ended(), "span of the caller is not ended by the client")
+	parent.End()
+	require.Empty(t, recorder.Ended()[0].Attributes(), "request attributes are not set on the caller span")
+}
@@ -0,0 +1,83 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var errInvalidConfig = errors.New("http client config error")
+
+const (
+	defaultTimeout          = 10 * time.Second
+	defaultMaxAttempts      = 3
+	defaultInitialBackoff   = 100 * time.Millisecond
+	defaultMaxBackoff       = 2 * time.Second
+	defaultFailureThreshold = 5
+	defaultOpenTimeout      = 30 * time.Second
+)
+
+type Config struct {
+	// Name используется в метриках и логах для различения клиентов
+	Name string `yaml:"name"`
+	// Timeout - таймаут одной попытки. Общее время вызова ограничивается контекстом.
+	Timeout        time.Duration `yaml:"timeout" env-default:"10s"`
+	Retry          RetryConfig   `yaml:"retry"`
+	CircuitBreaker BreakerConfig `yaml:"circuit_breaker"`
+}
+
+type RetryConfig struct {
+	// MaxAttempts - общее число попыток, включая первую. 1 отключает повторы.
+	MaxAttempts    int           `yaml:"max_attempts" env-default:"3"`
+	InitialBackoff time.Duration `yaml:"initial_backoff" env-default:"100ms"`
+	MaxBackoff     time.Duration `yaml:"max_backoff" env-default:"2s"`
+}
+
+type BreakerConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// FailureThreshold - число ошибок подряд, после которого запросы к хосту перестают отправляться
+	FailureThreshold int `yaml:"failure_threshold" env-default:"5"`
+	// OpenTimeout - время, через которое разрешается пробный запрос
+	OpenTimeout time.Duration `yaml:"open_timeout" env-default:"30s"`
+}
+
+func (c Config) Validate() error {
+	if c.Timeout < 0 {
+		return fmt.Errorf("timeout is negative: %w", errInvalidConfig)
+	}
+	if c.Retry.MaxAttempts < 0 {
+		return fmt.Errorf("retry.max_attempts is negative: %w", errInvalidConfig)
+	}
+	if c.Retry.InitialBackoff < 0 || c.Retry.MaxBackoff < 0 {
+		return fmt.Errorf("retry backoff is negative: %w", errInvalidConfig)
+	}
+	if c.CircuitBreaker.FailureThreshold < 0 || c.CircuitBreaker.OpenTimeout < 0 {
+		return fmt.Errorf("circuit_breaker values are negative: %w", errInvalidConfig)
+	}
+	return nil
+}
+
+func (c Config) withDefaults() Config {
+	if c.Name == "" {
+		c.Name = "default"
+	}
+	if c.Timeout == 0 {
+		c.Timeout = defaultTimeout
+	}
+	if c.Retry.MaxAttempts == 0 {
+		c.Retry.MaxAttempts = defaultMaxAttempts
+	}
+	if c.Retry.InitialBackoff == 0 {
+		c.Retry.InitialBackoff = defaultInitialBackoff
+	}
+	if c.Retry.MaxBackoff == 0 {
+		c.Retry.MaxBackoff = defaultMaxBackoff
+	}
+	if c.CircuitBreaker.FailureThreshold == 0 {
+		c.CircuitBreaker.FailureThreshold = defaultFailureThreshold
+	}
+	if c.CircuitBreaker.OpenTimeout == 0 {
+		c.CircuitBreaker.OpenTimeout = defaultOpenTimeout
+	}
+	return c
+}
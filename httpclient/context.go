@@ -0,0 +1,36 @@
+package httpclient
+
+import (
+	"context"
+	"time"
+)
+
+type (
+	routeKey   struct{}
+	timeoutKey struct{}
+)
+
+// WithRoute задает шаблон маршрута (например /users/{id}) для метрик и имени span.
+// Без него в метки попадает "other", чтобы сырые пути не раздували кардинальность.
+func WithRoute(ctx context.Context, route string) context.Context {
+	return context.WithValue(ctx, routeKey{}, route)
+}
+
+// WithRequestTimeout переопределяет таймаут одной попытки для запроса
+func WithRequestTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, timeoutKey{}, timeout)
+}
+
+func routeFromContext(ctx context.Context) string {
+	if r, ok := ctx.Value(routeKey{}).(string); ok && r != "" {
+		return r
+	}
+	return "other"
+}
+
+func timeoutFromContext(ctx context.Context, def time.Duration) time.Duration {
+	if t, ok := ctx.Value(timeoutKey{}).(time.Duration); ok && t > 0 {
+		return t
+	}
+	return def
+}
@@ -0,0 +1,91 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	coreHTTP "github.com/Rasikrr/core/http"
+)
+
+// maxErrorBody ограничивает чтение тела ответа с ошибкой
+const maxErrorBody = 1 << 20
+
+// envelope повторяет SuccessResponse, но оставляет Data сырым для декодирования в тип вызывающего
+type envelope struct {
+	Data    json.RawMessage `json:"data"`
+	Message string          `json:"message"`
+}
+
+func GetJSON(ctx context.Context, client *http.Client, url string, out any) error {
+	return DoJSON(ctx, client, http.MethodGet, url, nil, out)
+}
+
+func PostJSON(ctx context.Context, client *http.Client, url string, in, out any) error {
+	return DoJSON(ctx, client, http.MethodPost, url, in, out)
+}
+
+// DoJSON отправляет in как JSON и декодирует поле data из SuccessResponse в out.
+// Ответ с ошибкой возвращается как *http.Error фреймворка с кодом статуса и сообщением из ErrorResponse.
+func DoJSON(ctx context.Context, client *http.Client, method, url string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		bb, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("httpclient: marshal request: %w", err)
+		}
+		body = bytes.NewReader(bb)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return fmt.Errorf("httpclient: create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set(coreHTTP.ContentTypeHeader, "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return DecodeResponse(resp, out)
+}
+
+// DecodeResponse разбирает конверт SuccessResponse/ErrorResponse ответа сервера на фреймворке
+func DecodeResponse(resp *http.Response, out any) error {
+	if resp.StatusCode >= http.StatusBadRequest {
+		return decodeError(resp)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	var env envelope
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		return fmt.Errorf("httpclient: decode response: %w", err)
+	}
+	if len(env.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(env.Data, out); err != nil {
+		return fmt.Errorf("httpclient: decode response data: %w", err)
+	}
+	return nil
+}
+
+func decodeError(resp *http.Response) error {
+	bb, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+
+	var errResp coreHTTP.ErrorResponse
+	if err := json.Unmarshal(bb, &errResp); err != nil || errResp.Message == "" {
+		errResp.Message = http.StatusText(resp.StatusCode)
+	}
+	return coreHTTP.NewError(errResp.Message, resp.StatusCode)
+}
@@ -0,0 +1,32 @@
+package httpclient
+
+import (
+	"sync"
+
+	coreMetrics "github.com/Rasikrr/core/metrics"
+)
+
+type Metrics struct {
+	reqTotal   coreMetrics.CounterVec   // {client, host, route, method, code}
+	latencySec coreMetrics.HistogramVec // {client, host, route, method, code}
+	inflight   coreMetrics.GaugeVec     // {client, host}
+	retries    coreMetrics.CounterVec   // {client, host, route}
+}
+
+var (
+	metrics *Metrics
+	once    sync.Once
+)
+
+func initHTTPClientMetrics() {
+	once.Do(func() {
+		dur := []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+		metrics = &Metrics{
+			reqTotal:   coreMetrics.NewCounterVec("http_client", "requests_total", "Outbound HTTP requests", []string{"client", "host", "route", "method", "code"}, nil),
+			latencySec: coreMetrics.NewHistogramVec("http_client", "request_seconds", "Outbound HTTP request latency", dur, []string{"client", "host", "route", "method", "code"}, nil),
+			inflight:   coreMetrics.NewGaugeVec("http_client", "inflight", "In-flight outbound HTTP requests", []string{"client", "host"}, nil),
+			retries:    coreMetrics.NewCounterVec("http_client", "retries_total", "Outbound HTTP request retries", []string{"client", "host", "route"}, nil),
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Rasikrr/core/sentry"
+	sentrySDK "github.com/getsentry/sentry-go"
+)
+
+// addBreadcrumb записывает попытку запроса, чтобы она попала в событие Sentry при последующей ошибке
+func addBreadcrumb(ctx context.Context, req *http.Request, resp *http.Response, err error, elapsed time.Duration) {
+	hub := sentry.GetHubFromContext(ctx)
+	if hub == nil {
+		var hubErr error
+		if hub, hubErr = sentry.CurrentHub(); hubErr != nil {
+			return
+		}
+	}
+
+	data := map[string]interface{}{
+		"url":         req.URL.Redacted(),
+		"method":      req.Method,
+		"duration_ms": elapsed.Milliseconds(),
+	}
+	level := sentrySDK.LevelInfo
+	switch {
+	case err != nil:
+		data["error"] = err.Error()
+		level = sentrySDK.LevelError
+	case resp.StatusCode >= http.StatusInternalServerError:
+		data["status_code"] = resp.StatusCode
+		level = sentrySDK.LevelError
+	case resp.StatusCode >= http.StatusBadRequest:
+		data["status_code"] = resp.StatusCode
+		level = sentrySDK.LevelWarning
+	default:
+		data["status_code"] = resp.StatusCode
+	}
+
+	hub.AddBreadcrumb(&sentrySDK.Breadcrumb{
+		Type:     "http",
+		Category: "http",
+		Data:     data,
+		Level:    level,
+	}, nil)
+}
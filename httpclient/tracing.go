@@ -0,0 +1,66 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+
+	coreCtx "github.com/Rasikrr/core/context"
+	coreHTTP "github.com/Rasikrr/core/http"
+	"github.com/Rasikrr/core/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/Rasikrr/core/httpclient"
+
+func startSpan(ctx context.Context, req *http.Request, route string, attempt int) (context.Context, trace.Span) {
+	// Без трейсинга span не создается. Span из ctx принадлежит вызывающему, его нельзя завершать здесь.
+	if !tracing.Enabled() {
+		return ctx, trace.SpanFromContext(context.Background())
+	}
+	return tracing.GetTracer(tracerName).Start(ctx,
+		"HTTP "+req.Method+" "+route,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("server.address", req.URL.Host),
+			attribute.String("url.full", req.URL.Redacted()),
+			attribute.String("http.route", route),
+			attribute.Int("http.request.resend_count", attempt-1),
+		),
+	)
+}
+
+// injectTraceHeaders добавляет W3C trace context и TraceIDHeader фреймворка,
+// чтобы сервер на нашем фреймворке продолжил тот же trace ID даже без OpenTelemetry
+func injectTraceHeaders(ctx context.Context, req *http.Request) {
+	if tracing.Enabled() {
+		otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	}
+	if req.Header.Get(coreHTTP.TraceIDHeader) != "" {
+		return
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		req.Header.Set(coreHTTP.TraceIDHeader, sc.TraceID().String())
+		return
+	}
+	if traceID, ok := coreCtx.TraceID(ctx); ok {
+		req.Header.Set(coreHTTP.TraceIDHeader, traceID)
+	}
+}
+
+func finishSpan(span trace.Span, resp *http.Response, err error) {
+	defer span.End()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+}
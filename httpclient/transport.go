@@ -0,0 +1,122 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Rasikrr/core/resilience"
+)
+
+type transport struct {
+	base     http.RoundTripper
+	cfg      Config
+	breakers *breakers
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	host := req.URL.Host
+	route := routeFromContext(ctx)
+
+	attempts := 1
+	if isIdempotent(req) && canRewind(req) {
+		attempts = t.cfg.Retry.MaxAttempts
+	}
+
+	var cb *resilience.CircuitBreaker
+	if t.breakers != nil {
+		cb = t.breakers.get(host)
+	}
+
+	for attempt := 1; ; attempt++ {
+		done := func(error) {}
+		if cb != nil {
+			var err error
+			if done, err = cb.Allow(); err != nil {
+				metrics.reqTotal.WithLabelValues(t.cfg.Name, host, route, req.Method, "circuit_open").Inc()
+				return nil, err
+			}
+		}
+
+		resp, err := t.attempt(req, host, route, attempt)
+		done(breakerResult(resp, err))
+
+		if attempt >= attempts || !shouldRetry(resp, err) || ctx.Err() != nil {
+			return resp, err
+		}
+
+		delay := backoff(t.cfg.Retry, attempt, resp)
+		discard(resp)
+		if req, err = rewind(req); err != nil {
+			return nil, err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		metrics.retries.WithLabelValues(t.cfg.Name, host, route).Inc()
+	}
+}
+
+func (t *transport) attempt(req *http.Request, host, route string, attempt int) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), timeoutFromContext(req.Context(), t.cfg.Timeout))
+	ctx, span := startSpan(ctx, req, route, attempt)
+
+	out := req.Clone(ctx)
+	injectTraceHeaders(ctx, out)
+
+	metrics.inflight.WithLabelValues(t.cfg.Name, host).Inc()
+	start := time.Now()
+	resp, err := t.base.RoundTrip(out)
+	elapsed := time.Since(start)
+	metrics.inflight.WithLabelValues(t.cfg.Name, host).Dec()
+
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	metrics.reqTotal.WithLabelValues(t.cfg.Name, host, route, req.Method, code).Inc()
+	metrics.latencySec.WithLabelValues(t.cfg.Name, host, route, req.Method, code).Observe(elapsed.Seconds())
+	addBreadcrumb(req.Context(), req, resp, err, elapsed)
+	finishSpan(span, resp, err)
+
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// Таймаут попытки должен действовать и на чтение тела, поэтому cancel вызывается при его закрытии
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// rewind подготавливает запрос к повторной отправке тела
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	out := req.Clone(req.Context())
+	out.Body = body
+	return out, nil
+}
+
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
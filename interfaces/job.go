@@ -1,7 +1,16 @@
 package interfaces
 
+import "context"
+
 type Job interface {
 	Name() string
 	Schedule() string
 	Run()
 }
+
+// ContextJob - джоба, которой нужен контекст запуска. JobManager вызывает RunContext вместо Run,
+// в контексте уже есть имя джобы для логов.
+type ContextJob interface {
+	Job
+	RunContext(ctx context.Context)
+}
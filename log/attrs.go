@@ -2,6 +2,7 @@ package log
 
 import (
 	"log/slog"
+	"reflect"
 	"time"
 )
 
@@ -19,6 +20,10 @@ func Int(key string, value int) Attr {
 	return Attr{Key: key, Value: slog.IntValue(value)}
 }
 
+func Int64(key string, value int64) Attr {
+	return Attr{Key: key, Value: slog.Int64Value(value)}
+}
+
 func Float(key string, value float64) Attr {
 	return Attr{Key: key, Value: slog.Float64Value(value)}
 }
@@ -35,7 +40,15 @@ func Time(key string, value time.Time) Attr {
 	return Attr{Key: key, Value: slog.TimeValue(value)}
 }
 
+// Any добавляет произвольное значение. Для Redactable в лог попадает результат Redact, а структуры
+// с полями log:"redact" или чувствительными именами выводятся группой полей со скрытыми значениями.
 func Any(key string, value any) Attr {
+	if rv, ok := value.(Redactable); ok {
+		return Attr{Key: key, Value: slog.AnyValue(rv.Redact())}
+	}
+	if r := redaction.Load(); r.needsRedaction(reflect.TypeOf(value)) {
+		return Attr{Key: key, Value: slog.AnyValue(redactedStruct{r: r, v: reflect.ValueOf(value)})}
+	}
 	return Attr{Key: key, Value: slog.AnyValue(value)}
 }
 
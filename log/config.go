@@ -1,9 +1,97 @@
 package log
 
-import "github.com/Rasikrr/core/enum"
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Rasikrr/core/enum"
+)
+
+var (
+	errConfigRequired = errors.New("log config error")
+)
 
 type Config struct {
 	Level     enum.LogLevel  `yaml:"level"`
 	Format    enum.LogFormat `yaml:"format"`
 	AddSource bool           `yaml:"add_source"`
+	// LevelTTL - через сколько уровень, измененный сигналом или через LevelHandler без ttl, возвращается к Level
+	LevelTTL time.Duration `yaml:"level_ttl" env-default:"15m"`
+	// Systems - уровни логгеров с атрибутом system, например redis: warn
+	Systems map[string]enum.LogLevel `yaml:"systems"`
+	// Outputs - куда писать логи. Без выходов логи пишутся в stdout в формате Format.
+	Outputs  []OutputConfig `yaml:"outputs"`
+	Redact   RedactConfig   `yaml:"redact"`
+	Sampling SamplingConfig `yaml:"sampling"`
+	// LevelEndpoint - ручка изменения уровня на порту метрик, по умолчанию выключена
+	LevelEndpoint LevelEndpointConfig `yaml:"level_endpoint"`
+	// ServiceName - имя сервиса для OTLP, заполняется приложением из name
+	ServiceName string `yaml:"-"`
+}
+
+type OutputType string
+
+const (
+	OutputStdout OutputType = "stdout"
+	// OutputStderr по умолчанию пишет только warn и выше
+	OutputStderr OutputType = "stderr"
+	OutputFile   OutputType = "file"
+	OutputOTLP   OutputType = "otlp"
+)
+
+// OutputConfig - один получатель логов. Level - дополнительный порог поверх общего уровня,
+// Level и Format без значения берутся из Config.
+type OutputConfig struct {
+	Type   OutputType      `yaml:"type"`
+	Level  *enum.LogLevel  `yaml:"level"`
+	Format *enum.LogFormat `yaml:"format"`
+	File   FileConfig      `yaml:"file"`
+	OTLP   OTLPConfig      `yaml:"otlp"`
+}
+
+// FileConfig - файл с ротацией по размеру и, при RotateInterval, по времени.
+// Старые файлы удаляются по MaxAge и MaxBackups.
+type FileConfig struct {
+	Path string `yaml:"path"`
+	// MaxSize - размер файла в мегабайтах до ротации, по умолчанию 100
+	MaxSize        int           `yaml:"max_size"`
+	MaxAge         time.Duration `yaml:"max_age"`
+	MaxBackups     int           `yaml:"max_backups"`
+	RotateInterval time.Duration `yaml:"rotate_interval"`
+	Compress       bool          `yaml:"compress"`
+	LocalTime      bool          `yaml:"local_time"`
+}
+
+// OTLPConfig - экспорт в OTel Collector. Endpoint без значения берется из tracing, записи получают
+// trace_id и span_id активного спана из контекста.
+type OTLPConfig struct {
+	Endpoint string `yaml:"endpoint"`
+}
+
+func (c Config) Validate() error {
+	if err := c.Redact.validate(); err != nil {
+		return err
+	}
+	if err := c.Sampling.validate(); err != nil {
+		return err
+	}
+	if err := c.LevelEndpoint.validate(); err != nil {
+		return err
+	}
+	for i, out := range c.Outputs {
+		switch out.Type {
+		case OutputStdout, OutputStderr, OutputOTLP:
+		case OutputFile:
+			if out.File.Path == "" {
+				return fmt.Errorf("outputs[%d].file.path is empty: %w", i, errConfigRequired)
+			}
+			if out.File.MaxSize < 0 || out.File.MaxAge < 0 || out.File.MaxBackups < 0 || out.File.RotateInterval < 0 {
+				return fmt.Errorf("outputs[%d].file limits must not be negative: %w", i, errConfigRequired)
+			}
+		default:
+			return fmt.Errorf("outputs[%d] has unknown type %q: %w", i, out.Type, errConfigRequired)
+		}
+	}
+	return nil
 }
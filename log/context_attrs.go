@@ -2,20 +2,78 @@ package log
 
 import (
 	"context"
+	"log/slog"
 
 	coreCtx "github.com/Rasikrr/core/context"
+	"go.opentelemetry.io/otel/trace"
+)
 
-	"log/slog"
+// Встроенные ключи атрибутов контекста. HTTP, gRPC, NATS и cron интеграции заполняют их сами.
+const (
+	KeyTraceID     = string(coreCtx.CtxKeyTraceID)
+	KeyUserID      = string(coreCtx.CtxKeyUserID)
+	KeySpanID      = "span_id"
+	KeyRequestID   = "request_id"
+	KeyTenantID    = "tenant_id"
+	KeyNATSSubject = "nats_subject"
+	KeyGRPCMethod  = "grpc_method"
+	KeyJob         = "job"
 )
 
+type ctxAttrsKey struct{}
+
+// WithContextAttrs возвращает контекст, с которым каждая запись лога получает attrs.
+// Атрибуты накапливаются по цепочке контекстов, значение с тем же ключом заменяет прежнее.
+func WithContextAttrs(ctx context.Context, attrs ...Attr) context.Context {
+	if len(attrs) == 0 {
+		return ctx
+	}
+	prev := ContextAttrs(ctx)
+	merged := make([]Attr, 0, len(prev)+len(attrs))
+	for _, attr := range prev {
+		if !hasKey(attrs, attr.Key) {
+			merged = append(merged, attr)
+		}
+	}
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, ctxAttrsKey{}, merged)
+}
+
+// ContextAttrs возвращает атрибуты, добавленные через WithContextAttrs
+func ContextAttrs(ctx context.Context) []Attr {
+	attrs, _ := ctx.Value(ctxAttrsKey{}).([]Attr)
+	return attrs
+}
+
+func hasKey(attrs []Attr, key string) bool {
+	for _, attr := range attrs {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// getAttrsFromCtx собирает атрибуты для каждой записи: trace_id и user_id из пакета context,
+// span_id активного спана и атрибуты WithContextAttrs
 func getAttrsFromCtx(ctx context.Context) []slog.Attr {
-	var attrs []slog.Attr
-	if traceID, ok := coreCtx.TraceID(ctx); ok {
-		attrs = append(attrs, slog.String(string(coreCtx.CtxKeyTraceID), traceID))
+	if ctx == nil {
+		return nil
 	}
+	ctxAttrs := ContextAttrs(ctx)
+	attrs := make([]slog.Attr, 0, 3+len(ctxAttrs))
 
-	if userID, ok := coreCtx.UserID(ctx); ok {
-		attrs = append(attrs, slog.String(string(coreCtx.CtxKeyUserID), userID))
+	if traceID, ok := coreCtx.TraceID(ctx); ok && !hasKey(ctxAttrs, KeyTraceID) {
+		attrs = append(attrs, slog.String(KeyTraceID, traceID))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.HasSpanID() && !hasKey(ctxAttrs, KeySpanID) {
+		attrs = append(attrs, slog.String(KeySpanID, sc.SpanID().String()))
+	}
+	if userID, ok := coreCtx.UserID(ctx); ok && !hasKey(ctxAttrs, KeyUserID) {
+		attrs = append(attrs, slog.String(KeyUserID, userID))
+	}
+	for _, attr := range ctxAttrs {
+		attrs = append(attrs, slog.Attr(attr))
 	}
 	return attrs
 }
@@ -0,0 +1,28 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	coreCtx "github.com/Rasikrr/core/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithContextAttrs(t *testing.T) {
+	ctx := coreCtx.WithTraceID(context.Background(), "trace-1")
+	ctx = WithContextAttrs(ctx, String(KeyRequestID, "req-1"), String(KeyTenantID, "acme"))
+	ctx = WithContextAttrs(ctx, String(KeyTenantID, "globex"), String(KeyJob, "cleanup"))
+
+	require.Equal(t, []Attr{
+		String(KeyRequestID, "req-1"),
+		String(KeyTenantID, "globex"),
+		String(KeyJob, "cleanup"),
+	}, ContextAttrs(ctx), "later values replace earlier ones")
+
+	var buf bytes.Buffer
+	logger := (&slogWrapper{base: slog.New(slog.NewTextHandler(&buf, nil))}).With(String("system", "redis"))
+	logger.Info(ctx, "done")
+	require.Contains(t, buf.String(), `msg=done system=redis trace_id=trace-1 request_id=req-1 tenant_id=globex job=cleanup`)
+}
@@ -0,0 +1,246 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const systemAttr = "system"
+
+// levels - уровень логирования процесса. Меняется во время работы через SetLevel, SetSystemLevel,
+// LevelHandler и сигналы, не требуя перезапуска.
+var levels = newLevelState()
+
+type levelOverride struct {
+	level   slog.Level
+	expires time.Time
+	timer   *time.Timer
+}
+
+type levelSnapshot struct {
+	systems map[string]slog.Level
+	// min - минимальный уровень среди systems, чтобы Enabled без system не отсекал записи заранее
+	min slog.Level
+}
+
+type levelState struct {
+	level slog.LevelVar
+
+	mu          sync.Mutex
+	base        slog.Level
+	baseSystems map[string]slog.Level
+	defaultTTL  time.Duration
+	global      levelOverride
+	systems     map[string]*levelOverride
+	snapshot    atomic.Pointer[levelSnapshot]
+}
+
+func newLevelState() *levelState {
+	s := &levelState{
+		baseSystems: make(map[string]slog.Level),
+		systems:     make(map[string]*levelOverride),
+	}
+	s.level.Set(slog.LevelInfo)
+	s.publish()
+	return s
+}
+
+// configure задает уровни из конфигурации. К ним возвращаются ResetLevel, ResetSystemLevel и истекшие TTL.
+func (s *levelState) configure(cfg Config) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.base = cfg.Level.ToSlogLevel().Level()
+	s.defaultTTL = cfg.LevelTTL
+	s.level.Set(s.base)
+	for system, level := range cfg.Systems {
+		s.baseSystems[system] = level.ToSlogLevel().Level()
+	}
+	s.publish()
+}
+
+// publish пересчитывает снимок уровней систем. Вызывается под s.mu.
+func (s *levelState) publish() {
+	snap := &levelSnapshot{systems: maps.Clone(s.baseSystems), min: math.MaxInt}
+	for system, override := range s.systems {
+		snap.systems[system] = override.level
+	}
+	for _, level := range snap.systems {
+		snap.min = min(snap.min, level)
+	}
+	s.snapshot.Store(snap)
+}
+
+func (s *levelState) forSystem(system string) slog.Level {
+	if level, ok := s.snapshot.Load().systems[system]; ok {
+		return level
+	}
+	return s.level.Level()
+}
+
+func (s *levelState) min() slog.Level {
+	return min(s.level.Level(), s.snapshot.Load().min)
+}
+
+// ttl - время возврата уровня по умолчанию из Config.LevelTTL
+func (s *levelState) ttl() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.defaultTTL
+}
+
+// Level возвращает текущий общий уровень логирования
+func Level() slog.Level {
+	return levels.level.Level()
+}
+
+// SetLevel меняет общий уровень. При ttl > 0 уровень вернется к значению из конфигурации по истечении ttl.
+func SetLevel(level slog.Level, ttl time.Duration) {
+	s := levels
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stopTimer(&s.global)
+	s.level.Set(level)
+	s.global.level = level
+	if ttl <= 0 {
+		return
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(ttl, func() {
+		s.mu.Lock()
+		// Уровень мог быть переустановлен после запуска таймера
+		if s.global.timer != timer {
+			s.mu.Unlock()
+			return
+		}
+		stopTimer(&s.global)
+		base := s.base
+		s.level.Set(base)
+		s.mu.Unlock()
+		Info(context.Background(), "log level reverted", String("level", base.String()))
+	})
+	s.global.expires = time.Now().Add(ttl)
+	s.global.timer = timer
+}
+
+// ResetLevel возвращает общий уровень к значению из конфигурации
+func ResetLevel() {
+	s := levels
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stopTimer(&s.global)
+	s.level.Set(s.base)
+}
+
+// SetSystemLevel задает уровень для логгеров с атрибутом system, например log.With(log.String("system", "redis")).
+// При ttl > 0 уровень вернется к значению из конфигурации по истечении ttl.
+func SetSystemLevel(system string, level slog.Level, ttl time.Duration) {
+	s := levels
+	s.mu.Lock()
+	if prev, ok := s.systems[system]; ok {
+		stopTimer(prev)
+	}
+	override := &levelOverride{level: level}
+	if ttl > 0 {
+		override.expires = time.Now().Add(ttl)
+		override.timer = time.AfterFunc(ttl, func() {
+			s.mu.Lock()
+			// Уровень мог быть переустановлен после запуска таймера
+			current := s.systems[system] == override
+			if current {
+				delete(s.systems, system)
+				s.publish()
+			}
+			s.mu.Unlock()
+			if current {
+				Info(context.Background(), "log level reverted", String(systemAttr, system))
+			}
+		})
+	}
+	s.systems[system] = override
+	s.publish()
+	s.mu.Unlock()
+}
+
+// ResetSystemLevel возвращает уровень системы к значению из конфигурации или к общему уровню
+func ResetSystemLevel(system string) {
+	s := levels
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if prev, ok := s.systems[system]; ok {
+		stopTimer(prev)
+		delete(s.systems, system)
+		s.publish()
+	}
+}
+
+func stopTimer(o *levelOverride) {
+	if o.timer != nil {
+		o.timer.Stop()
+		o.timer = nil
+	}
+	o.expires = time.Time{}
+}
+
+// levelHandler отсекает записи по текущему уровню с учетом уровня системы из атрибута system
+// и собственного порога выхода floor
+type levelHandler struct {
+	next   slog.Handler
+	floor  slog.Level
+	system string
+	// grouped - атрибуты внутри группы не считаются атрибутом system
+	grouped bool
+}
+
+func newLevelHandler(next slog.Handler, floor slog.Level) *levelHandler {
+	return &levelHandler{next: next, floor: floor}
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if level < h.floor {
+		return false
+	}
+	if h.system != "" {
+		return level >= levels.forSystem(h.system) && h.next.Enabled(ctx, level)
+	}
+	return level >= levels.min() && h.next.Enabled(ctx, level)
+}
+
+func (h *levelHandler) Handle(ctx context.Context, record slog.Record) error {
+	system := h.system
+	if system == "" && !h.grouped {
+		record.Attrs(func(attr slog.Attr) bool {
+			if attr.Key == systemAttr {
+				system = attr.Value.String()
+				return false
+			}
+			return true
+		})
+	}
+	threshold := levels.level.Level()
+	if system != "" {
+		threshold = levels.forSystem(system)
+	}
+	if record.Level < max(threshold, h.floor) {
+		return nil
+	}
+	return h.next.Handle(ctx, record)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	system := h.system
+	for _, attr := range attrs {
+		if attr.Key == systemAttr && !h.grouped {
+			system = attr.Value.String()
+		}
+	}
+	return &levelHandler{next: h.next.WithAttrs(attrs), floor: h.floor, system: system, grouped: h.grouped}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{next: h.next.WithGroup(name), floor: h.floor, system: h.system, grouped: h.grouped || name != ""}
+}
@@ -0,0 +1,135 @@
+package log
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// LevelEndpointConfig включает LevelHandler на порту метрик. Ручка меняет поведение сервиса,
+// поэтому по умолчанию выключена и требует токен.
+type LevelEndpointConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Token - значение заголовка Authorization: Bearer <token>, обязателен при Enabled
+	Token string `yaml:"-" env:"LOG_LEVEL_TOKEN" log:"redact"`
+}
+
+func (c LevelEndpointConfig) validate() error {
+	if c.Enabled && c.Token == "" {
+		return fmt.Errorf("level_endpoint.token is empty: %w", errConfigRequired)
+	}
+	return nil
+}
+
+type levelStatus struct {
+	Level     slog.Level  `json:"level"`
+	ExpiresAt *time.Time  `json:"expires_at,omitempty"`
+	Systems   []levelInfo `json:"systems,omitempty"`
+}
+
+type levelInfo struct {
+	System    string     `json:"system"`
+	Level     slog.Level `json:"level"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type levelRequest struct {
+	Level  *slog.Level `json:"level"`
+	System string      `json:"system"`
+	// TTL - длительность вида "10m". Без поля используется Config.LevelTTL, "0s" отключает возврат.
+	TTL *string `json:"ttl"`
+}
+
+func (s *levelState) status() levelStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := levelStatus{Level: s.level.Level(), ExpiresAt: expiresAt(s.global.expires)}
+	for system, level := range s.snapshot.Load().systems {
+		info := levelInfo{System: system, Level: level}
+		if override, ok := s.systems[system]; ok {
+			info.ExpiresAt = expiresAt(override.expires)
+		}
+		out.Systems = append(out.Systems, info)
+	}
+	slices.SortFunc(out.Systems, func(a, b levelInfo) int { return strings.Compare(a.System, b.System) })
+	return out
+}
+
+func expiresAt(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// LevelHandler - HTTP ручка для изменения уровня логирования без перезапуска:
+//
+//	GET    - текущие уровни
+//	PUT    - {"level": "debug", "system": "redis", "ttl": "10m"}, system и ttl необязательны
+//	DELETE - ?system=redis, без system возвращает общий уровень к значению из конфигурации
+//
+// Запрос должен содержать заголовок Authorization: Bearer <token>. С пустым token ручка отклоняет все запросы.
+func LevelHandler(token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(r, token) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodPost:
+			var req levelRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			if req.Level == nil {
+				http.Error(w, "level is required", http.StatusBadRequest)
+				return
+			}
+			ttl := levels.ttl()
+			if req.TTL != nil {
+				d, err := time.ParseDuration(*req.TTL)
+				if err != nil {
+					http.Error(w, "invalid ttl: "+err.Error(), http.StatusBadRequest)
+					return
+				}
+				ttl = d
+			}
+			if req.System != "" {
+				SetSystemLevel(req.System, *req.Level, ttl)
+			} else {
+				SetLevel(*req.Level, ttl)
+			}
+			Info(r.Context(), "log level changed",
+				String("level", req.Level.String()),
+				String(systemAttr, req.System),
+				Duration("ttl", ttl),
+			)
+		case http.MethodDelete:
+			if system := r.URL.Query().Get(systemAttr); system != "" {
+				ResetSystemLevel(system)
+			} else {
+				ResetLevel()
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT, POST, DELETE")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(levels.status())
+	})
+}
+
+func authorized(r *http.Request, token string) bool {
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && token != "" && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
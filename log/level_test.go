@@ -0,0 +1,89 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevelHandler(t *testing.T) {
+	levels = newLevelState()
+	t.Cleanup(func() { levels = newLevelState() })
+
+	var buf bytes.Buffer
+	logger := slog.New(newLevelHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.Level(-8)}), slog.Level(-8)))
+	redis := logger.With(systemAttr, "redis")
+	ctx := context.Background()
+
+	logger.DebugContext(ctx, "hidden")
+	redis.DebugContext(ctx, "hidden")
+	require.Empty(t, buf.String())
+
+	SetSystemLevel("redis", slog.LevelDebug, 0)
+	logger.DebugContext(ctx, "hidden")
+	logger.DebugContext(ctx, "redis record", systemAttr, "redis")
+	redis.DebugContext(ctx, "redis logger")
+	require.NotContains(t, buf.String(), "hidden")
+	require.Contains(t, buf.String(), "redis record")
+	require.Contains(t, buf.String(), "redis logger")
+
+	buf.Reset()
+	SetLevel(slog.LevelDebug, 50*time.Millisecond)
+	logger.DebugContext(ctx, "debug enabled")
+	require.Contains(t, buf.String(), "debug enabled")
+	require.Eventually(t, func() bool { return Level() == slog.LevelInfo }, time.Second, 10*time.Millisecond)
+
+	ResetSystemLevel("redis")
+	buf.Reset()
+	redis.DebugContext(ctx, "hidden")
+	require.Empty(t, buf.String())
+}
+
+func TestLevelHTTPHandler(t *testing.T) {
+	levels = newLevelState()
+	t.Cleanup(func() { levels = newLevelState() })
+	h := LevelHandler("secret")
+	request := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := request(http.MethodPut, "/", `{"level":"debug","system":"redis","ttl":"1h"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"system":"redis","level":"DEBUG","expires_at"`)
+	require.Equal(t, slog.LevelDebug, levels.forSystem("redis"))
+
+	rec = request(http.MethodPut, "/", `{"level":"warn","ttl":"soon"}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = request(http.MethodPut, "/", `{}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code, "level is required")
+	require.Equal(t, slog.LevelInfo, Level())
+
+	rec = request(http.MethodDelete, "/?system=redis", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, slog.LevelInfo, levels.forSystem("redis"))
+
+	for _, auth := range []string{"", "Bearer wrong", "secret"} {
+		req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"level":"debug"}`))
+		req.Header.Set("Authorization", auth)
+		rec = httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusUnauthorized, rec.Code, auth)
+	}
+	require.Equal(t, slog.LevelInfo, Level())
+
+	rec = httptest.NewRecorder()
+	LevelHandler("").ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusUnauthorized, rec.Code, "empty token rejects every request")
+}
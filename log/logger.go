@@ -6,7 +6,6 @@ import (
 	"os"
 	"sync"
 
-	"github.com/Rasikrr/core/enum"
 	"github.com/Rasikrr/core/sentry"
 	slogmulti "github.com/samber/slog-multi"
 )
@@ -64,36 +63,44 @@ func Default() Logger {
 	return defaultLogger
 }
 
-func Init(cfg Config) error {
-	opts := &slog.HandlerOptions{
-		Level:       cfg.Level.ToSlogLevel(),
-		AddSource:   cfg.AddSource,
-		ReplaceAttr: replaceLevelAttr,
-	}
-
+// Init настраивает логгер по умолчанию. Записи уходят в выходы из cfg.Outputs, в Sentry и в handlers -
+// дополнительные получатели, например алерты в Telegram. Все они подключаются через slogmulti.Fanout
+// и получают записи после отбора по cfg.Sampling и скрытия чувствительных данных по cfg.Redact.
+func Init(cfg Config, handlers ...slog.Handler) error {
 	var onceErr error
 	once.Do(func() {
-		var (
-			handlers    = make([]slog.Handler, 0, 2)
-			baseHandler slog.Handler
-		)
-
-		switch cfg.Format {
-		case enum.LogFormatJSON:
-			baseHandler = slog.NewJSONHandler(os.Stdout, opts)
-		default:
-			baseHandler = slog.NewTextHandler(os.Stdout, opts)
+		levels.configure(cfg)
+		if err := configureRedaction(cfg.Redact); err != nil {
+			onceErr = err
+			return
+		}
+
+		outputs, closers, err := newOutputs(cfg)
+		if err != nil {
+			onceErr = err
+			return
 		}
-		handlers = append(handlers, baseHandler)
+		outputClosers = closers
+
+		extra := handlers
+		handlers = make([]slog.Handler, 0, len(outputs)+1+len(extra))
+		handlers = append(handlers, outputs...)
 
 		if sentry.Enabled() {
 			handlers = append(handlers, sentryHandler())
 		}
+		handlers = append(handlers, extra...)
+
+		var handler slog.Handler = newRedactHandler(slogmulti.Fanout(handlers...))
+		if cfg.Sampling.Mode != SamplingOff {
+			sampler := newSamplingHandler(handler, cfg.Sampling)
+			// Повторы выводятся до закрытия выходов
+			outputClosers = append([]closeFunc{sampler.Close}, outputClosers...)
+			handler = sampler
+		}
 
 		defaultLogger = &slogWrapper{
-			base: slog.New(
-				slogmulti.Fanout(handlers...),
-			),
+			base: slog.New(handler),
 		}
 	})
 	return onceErr
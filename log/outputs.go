@@ -0,0 +1,182 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"time"
+
+	"github.com/Rasikrr/core/enum"
+	"github.com/Rasikrr/core/environment"
+	"github.com/Rasikrr/core/tracing"
+	"github.com/Rasikrr/core/version"
+	"go.opentelemetry.io/contrib/bridges/otelslog"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const instrumentationName = "github.com/Rasikrr/core/log"
+
+type closeFunc func(ctx context.Context) error
+
+// outputClosers дописывают и закрывают выходы в Close
+var outputClosers []closeFunc
+
+// newOutputs создает handler на каждый выход. Общий уровень и уровень выхода проверяет levelHandler.
+func newOutputs(cfg Config) ([]slog.Handler, []closeFunc, error) {
+	outputs := cfg.Outputs
+	if len(outputs) == 0 {
+		outputs = []OutputConfig{{Type: OutputStdout}}
+	}
+
+	var (
+		handlers = make([]slog.Handler, 0, len(outputs))
+		closers  []closeFunc
+	)
+	for i, out := range outputs {
+		handler, closer, err := newOutput(cfg, out)
+		if err != nil {
+			for _, c := range closers {
+				_ = c(context.Background())
+			}
+			return nil, nil, fmt.Errorf("log output %d (%s): %w", i, out.Type, err)
+		}
+		handlers = append(handlers, newLevelHandler(handler, outputLevel(out)))
+		if closer != nil {
+			closers = append(closers, closer)
+		}
+	}
+	return handlers, closers, nil
+}
+
+func newOutput(cfg Config, out OutputConfig) (slog.Handler, closeFunc, error) {
+	format := cfg.Format
+	if out.Format != nil {
+		format = *out.Format
+	}
+
+	switch out.Type {
+	case OutputStdout:
+		return newFormatHandler(os.Stdout, format, cfg.AddSource), nil, nil
+	case OutputStderr:
+		return newFormatHandler(os.Stderr, format, cfg.AddSource), nil, nil
+	case OutputFile:
+		file := newRotatingFile(out.File)
+		return newFormatHandler(file, format, cfg.AddSource), file.Close, nil
+	case OutputOTLP:
+		return newOTLPHandler(cfg, out.OTLP)
+	default:
+		return nil, nil, fmt.Errorf("unknown output type %q", out.Type)
+	}
+}
+
+// outputLevel - порог выхода поверх общего уровня. Stderr по умолчанию принимает warn и выше.
+func outputLevel(out OutputConfig) slog.Level {
+	if out.Level != nil {
+		return out.Level.ToSlogLevel().Level()
+	}
+	if out.Type == OutputStderr {
+		return slog.LevelWarn
+	}
+	return math.MinInt
+}
+
+func newFormatHandler(w io.Writer, format enum.LogFormat, addSource bool) slog.Handler {
+	// Уровень проверяет levelHandler, чтобы его можно было менять во время работы
+	opts := &slog.HandlerOptions{
+		Level:       slog.Level(math.MinInt),
+		AddSource:   addSource,
+		ReplaceAttr: replaceLevelAttr,
+	}
+	if format == enum.LogFormatJSON {
+		return slog.NewJSONHandler(w, opts)
+	}
+	return slog.NewTextHandler(w, opts)
+}
+
+// rotatingFile - файл lumberjack с дополнительной ротацией по времени
+type rotatingFile struct {
+	*lumberjack.Logger
+	stop chan struct{}
+}
+
+func newRotatingFile(cfg FileConfig) *rotatingFile {
+	file := &rotatingFile{
+		Logger: &lumberjack.Logger{
+			Filename:   cfg.Path,
+			MaxSize:    cfg.MaxSize,
+			MaxAge:     int(math.Ceil(cfg.MaxAge.Hours() / 24)),
+			MaxBackups: cfg.MaxBackups,
+			LocalTime:  cfg.LocalTime,
+			Compress:   cfg.Compress,
+		},
+		stop: make(chan struct{}),
+	}
+	if cfg.RotateInterval > 0 {
+		go file.rotateEvery(cfg.RotateInterval)
+	}
+	return file
+}
+
+func (f *rotatingFile) rotateEvery(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := f.Rotate(); err != nil {
+				fmt.Fprintf(os.Stderr, "log: failed to rotate %s: %v\n", f.Filename, err)
+			}
+		case <-f.stop:
+			return
+		}
+	}
+}
+
+func (f *rotatingFile) Close(_ context.Context) error {
+	close(f.stop)
+	return f.Logger.Close()
+}
+
+func newOTLPHandler(cfg Config, otlp OTLPConfig) (slog.Handler, closeFunc, error) {
+	if otlp.Endpoint == "" {
+		return nil, nil, errors.New("otlp endpoint is empty, set otlp.endpoint or tracing exporter dsn")
+	}
+	// Соединение устанавливается лениво, контекст нужен только для создания экспортера
+	exporter, err := otlploggrpc.New(context.Background(),
+		otlploggrpc.WithEndpoint(otlp.Endpoint),
+		otlploggrpc.WithInsecure(),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	res, err := tracing.NewResource(cfg.ServiceName, environment.GetEnv().String())
+	if err != nil {
+		return nil, nil, err
+	}
+	provider := sdklog.NewLoggerProvider(
+		sdklog.WithResource(res),
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+	)
+	handler := otelslog.NewHandler(instrumentationName,
+		otelslog.WithLoggerProvider(provider),
+		otelslog.WithVersion(version.GetVersion()),
+		otelslog.WithSource(cfg.AddSource),
+	)
+	return handler, provider.Shutdown, nil
+}
+
+// Close дописывает буферы выходов: отправляет записи в OTLP и закрывает файлы.
+// Вызывается при остановке приложения после остальных компонентов.
+func Close(ctx context.Context) error {
+	var errs []error
+	for _, c := range outputClosers {
+		errs = append(errs, c(ctx))
+	}
+	return errors.Join(errs...)
+}
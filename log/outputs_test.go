@@ -0,0 +1,52 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Rasikrr/core/enum"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileOutput(t *testing.T) {
+	levels = newLevelState()
+	t.Cleanup(func() { levels = newLevelState() })
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	warn, jsonFormat := enum.LogLevelWarn, enum.LogFormatJSON
+	handlers, closers, err := newOutputs(Config{
+		Outputs: []OutputConfig{{
+			Type:   OutputFile,
+			Level:  &warn,
+			Format: &jsonFormat,
+			File:   FileConfig{Path: path},
+		}},
+	})
+	require.NoError(t, err)
+	require.Len(t, handlers, 1)
+
+	logger := slog.New(handlers[0])
+	ctx := context.Background()
+	logger.InfoContext(ctx, "below output level")
+	logger.WarnContext(ctx, "disk is almost full", "free", "1%")
+	for _, c := range closers {
+		require.NoError(t, c(ctx))
+	}
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "below output level")
+	require.Contains(t, string(data), `"msg":"disk is almost full","free":"1%"`)
+}
+
+func TestOutputsValidate(t *testing.T) {
+	require.ErrorIs(t, Config{Outputs: []OutputConfig{{Type: OutputFile}}}.Validate(), errConfigRequired)
+	require.ErrorIs(t, Config{Outputs: []OutputConfig{{Type: "syslog"}}}.Validate(), errConfigRequired)
+	require.NoError(t, Config{Outputs: []OutputConfig{{Type: OutputStderr}, {Type: OutputOTLP}}}.Validate())
+
+	_, _, err := newOutputs(Config{Outputs: []OutputConfig{{Type: OutputOTLP}}})
+	require.Error(t, err, "otlp needs an endpoint")
+}
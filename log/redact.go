@@ -0,0 +1,508 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// RedactedValue заменяет скрытые значения
+const RedactedValue = "[REDACTED]"
+
+var defaultRedactKeys = []string{
+	"password", "passwd", "secret", "token", "authorization", "cookie", "dsn", "api_key", "private_key",
+}
+
+// Встроенные шаблоны значений. Учетные данные в URL и DSN вида "password=..." идут первыми,
+// иначе пароль в user:pass@host совпадет с email.
+var defaultRedactPatterns = []redactPattern{
+	{re: regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://[^:/@\s]*:)[^@\s]+@`), replace: "${1}" + RedactedValue + "@"},
+	{re: regexp.MustCompile(`(?i)(\bpassword\s*=\s*)('[^']*'|[^\s']+)`), replace: "${1}" + RedactedValue},
+	{re: regexp.MustCompile(`eyJ[a-zA-Z0-9_-]+\.eyJ[a-zA-Z0-9_-]+\.[a-zA-Z0-9_-]*`)},
+	{re: regexp.MustCompile(`[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}`)},
+	{re: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`), match: isCardNumber},
+}
+
+// Redactable реализуют типы с чувствительными данными. В лог попадает результат Redact, в том числе
+// когда значение передано через log.Any.
+type Redactable interface {
+	Redact() any
+}
+
+// RedactConfig настраивает скрытие данных в логах. Скрытие работает для всех выходов, Sentry и алертов.
+type RedactConfig struct {
+	// Keys - ключи атрибутов и поля структур, значения которых скрываются целиком. Регистр, "_" и "-"
+	// не учитываются, ключ совпадает по подстроке: token скрывает access_token и X-Auth-Token.
+	// Без значения используется password, passwd, secret, token, authorization, cookie, dsn, api_key, private_key.
+	Keys []string `yaml:"keys"`
+	// Patterns - регулярные выражения для значений в дополнение к встроенным: учетные данные в URL, JWT,
+	// email и номера карт. Совпадения заменяются на RedactedValue.
+	Patterns []string `yaml:"patterns"`
+}
+
+func (c RedactConfig) validate() error {
+	for _, p := range c.Patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			return fmt.Errorf("redact pattern %q: %w", p, errConfigRequired)
+		}
+	}
+	return nil
+}
+
+type redactPattern struct {
+	re *regexp.Regexp
+	// replace - шаблон замены с группами, без него совпадение заменяется целиком
+	replace string
+	// match дополнительно проверяет совпадение, например контрольную сумму номера карты
+	match func(string) bool
+}
+
+type redactor struct {
+	keys     []string
+	patterns []redactPattern
+	// types кэширует, есть ли в типе структуры поля, которые нужно скрыть
+	types sync.Map
+	// texts кэширует, может ли значение типа содержать строки или скрываемые поля
+	texts sync.Map
+}
+
+// redaction - текущие правила. До Init действуют правила по умолчанию.
+var redaction atomic.Pointer[redactor]
+
+func init() {
+	redaction.Store(newRedactor(nil, nil))
+}
+
+func newRedactor(keys []string, patterns []*regexp.Regexp) *redactor {
+	if len(keys) == 0 {
+		keys = defaultRedactKeys
+	}
+	r := &redactor{patterns: defaultRedactPatterns}
+	for _, key := range keys {
+		r.keys = append(r.keys, normalizeKey(key))
+	}
+	for _, re := range patterns {
+		r.patterns = append(r.patterns, redactPattern{re: re})
+	}
+	return r
+}
+
+func configureRedaction(cfg RedactConfig) error {
+	patterns := make([]*regexp.Regexp, 0, len(cfg.Patterns))
+	for _, p := range cfg.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return fmt.Errorf("redact pattern %q: %w", p, err)
+		}
+		patterns = append(patterns, re)
+	}
+	redaction.Store(newRedactor(cfg.Keys, patterns))
+	return nil
+}
+
+func normalizeKey(key string) string {
+	key = strings.ToLower(key)
+	return strings.NewReplacer("_", "", "-", "").Replace(key)
+}
+
+func (r *redactor) sensitiveKey(key string) bool {
+	key = normalizeKey(key)
+	for _, k := range r.keys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *redactor) redactString(s string) string {
+	for _, p := range r.patterns {
+		switch {
+		case p.match != nil:
+			s = p.re.ReplaceAllStringFunc(s, func(m string) string {
+				if p.match(m) {
+					return RedactedValue
+				}
+				return m
+			})
+		case p.replace != "":
+			s = p.re.ReplaceAllString(s, p.replace)
+		default:
+			s = p.re.ReplaceAllLiteralString(s, RedactedValue)
+		}
+	}
+	return s
+}
+
+func (r *redactor) redactAttr(attr slog.Attr) slog.Attr {
+	if r.sensitiveKey(attr.Key) {
+		return slog.String(attr.Key, RedactedValue)
+	}
+	attr.Value = r.redactValue(attr.Value)
+	return attr
+}
+
+func (r *redactor) redactValue(v slog.Value) slog.Value {
+	if v.Kind() == slog.KindAny {
+		if rv, ok := v.Any().(Redactable); ok {
+			v = slog.AnyValue(rv.Redact())
+		}
+	}
+	v = v.Resolve()
+
+	switch v.Kind() {
+	case slog.KindString:
+		return slog.StringValue(r.redactString(v.String()))
+	case slog.KindGroup:
+		group := v.Group()
+		attrs := make([]slog.Attr, len(group))
+		for i, attr := range group {
+			attrs[i] = r.redactAttr(attr)
+		}
+		return slog.GroupValue(attrs...)
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			if msg := r.redactString(err.Error()); msg != err.Error() {
+				return slog.StringValue(msg)
+			}
+			return v
+		}
+		if r.needsRedaction(reflect.TypeOf(v.Any())) {
+			return redactedStruct{r: r, v: reflect.ValueOf(v.Any())}.LogValue()
+		}
+		if m := r.redactMap(v); m.Kind() == slog.KindGroup {
+			return m
+		}
+		if cleaned, ok := r.scrub(reflect.ValueOf(v.Any()), 0); ok {
+			return slog.AnyValue(cleaned)
+		}
+		return v
+	}
+	return v
+}
+
+// redactMap выводит группой map со строковыми ключами, если в ней есть чувствительные ключи,
+// например http.Header с Authorization
+func (r *redactor) redactMap(v slog.Value) slog.Value {
+	m := reflect.ValueOf(v.Any())
+	if m.Kind() != reflect.Map || m.Type().Key().Kind() != reflect.String {
+		return v
+	}
+	sensitive := false
+	for _, key := range m.MapKeys() {
+		if r.sensitiveKey(key.String()) {
+			sensitive = true
+			break
+		}
+	}
+	if !sensitive {
+		return v
+	}
+	attrs := make([]slog.Attr, 0, m.Len())
+	iter := m.MapRange()
+	for iter.Next() {
+		attrs = append(attrs, r.redactAttr(slog.Any(iter.Key().String(), iter.Value().Interface())))
+	}
+	slices.SortFunc(attrs, func(a, b slog.Attr) int { return strings.Compare(a.Key, b.Key) })
+	return slog.GroupValue(attrs...)
+}
+
+// maxScrubDepth ограничивает обход вложенных значений, в том числе циклических через указатели
+const maxScrubDepth = 10
+
+// scrub применяет шаблоны значений к строкам внутри структур без тегов, срезов и map.
+// Значение с найденными данными возвращается копией из map[string]any, []any и строк,
+// без совпадений scrub возвращает false, и значение логируется как есть.
+func (r *redactor) scrub(v reflect.Value, depth int) (any, bool) {
+	if !v.IsValid() || depth > maxScrubDepth || !r.hasText(v.Type()) {
+		return nil, false
+	}
+	if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+		return nil, false
+	}
+	switch x := v.Interface().(type) {
+	case Redactable:
+		redacted := x.Redact()
+		if cleaned, ok := r.scrub(reflect.ValueOf(redacted), depth+1); ok {
+			return cleaned, true
+		}
+		return redacted, true
+	case error:
+		if msg := r.redactString(x.Error()); msg != x.Error() {
+			return msg, true
+		}
+		return nil, false
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		if s := r.redactString(v.String()); s != v.String() {
+			return s, true
+		}
+	case reflect.Pointer, reflect.Interface:
+		return r.scrub(v.Elem(), depth+1)
+	case reflect.Struct:
+		return r.scrubStruct(v, depth)
+	case reflect.Map:
+		return r.scrubMap(v, depth)
+	case reflect.Slice, reflect.Array:
+		return r.scrubSlice(v, depth)
+	}
+	return nil, false
+}
+
+// scrubStruct скрывает поля так же, как redactedStruct
+func (r *redactor) scrubStruct(v reflect.Value, depth int) (any, bool) {
+	t := v.Type()
+	out := make(map[string]any, t.NumField())
+	changed := false
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("log")
+		if tag == "-" {
+			changed = true
+			continue
+		}
+		name := fieldName(field)
+		if tag == "redact" || r.sensitiveKey(field.Name) || r.sensitiveKey(name) {
+			out[name], changed = RedactedValue, true
+			continue
+		}
+		if cleaned, ok := r.scrub(v.Field(i), depth+1); ok {
+			out[name], changed = cleaned, true
+			continue
+		}
+		out[name] = v.Field(i).Interface()
+	}
+	return out, changed
+}
+
+func (r *redactor) scrubMap(v reflect.Value, depth int) (any, bool) {
+	out := make(map[string]any, v.Len())
+	changed := false
+	iter := v.MapRange()
+	for iter.Next() {
+		key := fmt.Sprint(iter.Key().Interface())
+		if r.sensitiveKey(key) {
+			out[key], changed = RedactedValue, true
+			continue
+		}
+		if cleaned, ok := r.scrub(iter.Value(), depth+1); ok {
+			out[key], changed = cleaned, true
+			continue
+		}
+		out[key] = iter.Value().Interface()
+	}
+	return out, changed
+}
+
+func (r *redactor) scrubSlice(v reflect.Value, depth int) (any, bool) {
+	out := make([]any, v.Len())
+	changed := false
+	for i := range v.Len() {
+		if cleaned, ok := r.scrub(v.Index(i), depth+1); ok {
+			out[i], changed = cleaned, true
+			continue
+		}
+		out[i] = v.Index(i).Interface()
+	}
+	return out, changed
+}
+
+// hasText сообщает, может ли значение типа содержать строки, ошибки, Redactable или поля, которые нужно скрыть.
+// Остальные значения, например []byte или структуры из чисел, scrub не обходит.
+func (r *redactor) hasText(t reflect.Type) bool {
+	if cached, ok := r.texts.Load(t); ok {
+		return cached.(bool)
+	}
+	has := r.checkText(t, make(map[reflect.Type]struct{}))
+	r.texts.Store(t, has)
+	return has
+}
+
+func (r *redactor) checkText(t reflect.Type, visiting map[reflect.Type]struct{}) bool {
+	if t.Implements(redactableType) || t.Implements(errorType) {
+		return true
+	}
+	// Рекурсивные типы: вложенное вхождение проверяемого типа ничего не добавляет
+	if _, ok := visiting[t]; ok {
+		return false
+	}
+	visiting[t] = struct{}{}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Interface:
+		return true
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return r.checkText(t.Elem(), visiting)
+	case reflect.Map:
+		return r.checkText(t.Key(), visiting) || r.checkText(t.Elem(), visiting)
+	case reflect.Struct:
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if field.IsExported() && (field.Tag.Get("log") != "" || r.sensitiveKey(field.Name) || r.checkText(field.Type, visiting)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+var errorType = reflect.TypeFor[error]()
+
+// needsRedaction сообщает, есть ли в структуре (или указателе на нее) поля с тегом log:"redact",
+// с чувствительным именем или типом Redactable, в том числе во вложенных структурах
+func (r *redactor) needsRedaction(t reflect.Type) bool {
+	return r.checkType(t, make(map[reflect.Type]struct{}))
+}
+
+func (r *redactor) checkType(t reflect.Type, visiting map[reflect.Type]struct{}) bool {
+	if t == nil {
+		return false
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	if cached, ok := r.types.Load(t); ok {
+		return cached.(bool)
+	}
+	// Рекурсивные типы: вложенное вхождение проверяемого типа ничего не добавляет
+	if _, ok := visiting[t]; ok {
+		return false
+	}
+	visiting[t] = struct{}{}
+
+	needs := false
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Tag.Get("log") != "" || r.sensitiveKey(field.Name) ||
+			field.Type.Implements(redactableType) || r.checkType(field.Type, visiting) {
+			needs = true
+			break
+		}
+	}
+	r.types.Store(t, needs)
+	return needs
+}
+
+var redactableType = reflect.TypeFor[Redactable]()
+
+// redactedStruct выводит структуру группой полей, скрывая помеченные и чувствительные поля.
+// Поля называются по тегу json, если он есть. Тег log:"-" убирает поле из лога.
+type redactedStruct struct {
+	r *redactor
+	v reflect.Value
+}
+
+func (s redactedStruct) LogValue() slog.Value {
+	v := s.v
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return slog.AnyValue(nil)
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return slog.AnyValue(v.Interface())
+	}
+
+	t := v.Type()
+	attrs := make([]slog.Attr, 0, t.NumField())
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("log")
+		if tag == "-" {
+			continue
+		}
+		name := fieldName(field)
+		if tag == "redact" || s.r.sensitiveKey(field.Name) || s.r.sensitiveKey(name) {
+			attrs = append(attrs, slog.String(name, RedactedValue))
+			continue
+		}
+		attrs = append(attrs, s.r.redactAttr(slog.Any(name, v.Field(i).Interface())))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+func fieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+// isCardNumber проверяет контрольную сумму Луна, чтобы не скрывать обычные длинные числа
+func isCardNumber(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c == ' ' || c == '-' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && sum%10 == 0
+}
+
+// redactHandler скрывает чувствительные данные до того, как запись попадет в выходы, Sentry и алерты
+type redactHandler struct {
+	next slog.Handler
+}
+
+func newRedactHandler(next slog.Handler) *redactHandler {
+	return &redactHandler{next: next}
+}
+
+func (h *redactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *redactHandler) Handle(ctx context.Context, record slog.Record) error {
+	r := redaction.Load()
+	out := slog.NewRecord(record.Time, record.Level, r.redactString(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		out.AddAttrs(r.redactAttr(attr))
+		return true
+	})
+	return h.next.Handle(ctx, out)
+}
+
+func (h *redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	r := redaction.Load()
+	redacted := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redacted[i] = r.redactAttr(attr)
+	}
+	return &redactHandler{next: h.next.WithAttrs(redacted)}
+}
+
+func (h *redactHandler) WithGroup(name string) slog.Handler {
+	return &redactHandler{next: h.next.WithGroup(name)}
+}
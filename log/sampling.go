@@ -0,0 +1,239 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type SamplingMode string
+
+const (
+	// SamplingOff пропускает все записи
+	SamplingOff SamplingMode = ""
+	// SamplingSample пропускает первые First записей с одинаковым сообщением за Interval, затем каждую Thereafter
+	SamplingSample SamplingMode = "sample"
+	// SamplingDedup пропускает первую запись за Interval, а повторы выводит одной строкой с их числом
+	SamplingDedup SamplingMode = "dedup"
+
+	repeatedAttr = "repeated"
+)
+
+// Причины отброса записей для DropHook
+const (
+	DropReasonSampled      = "sampled"
+	DropReasonDeduplicated = "deduplicated"
+)
+
+// SamplingConfig ограничивает поток одинаковых записей. Записи группируются по уровню и сообщению,
+// error и выше не отбрасываются никогда.
+type SamplingConfig struct {
+	Mode       SamplingMode  `yaml:"mode"`
+	Interval   time.Duration `yaml:"interval" env-default:"1s"`
+	First      int           `yaml:"first" env-default:"100"`
+	Thereafter int           `yaml:"thereafter" env-default:"100"`
+}
+
+func (c SamplingConfig) validate() error {
+	switch c.Mode {
+	case SamplingOff, SamplingSample, SamplingDedup:
+	default:
+		return fmt.Errorf("unknown sampling mode %q: %w", c.Mode, errConfigRequired)
+	}
+	if c.Interval < 0 || c.First < 0 || c.Thereafter < 0 {
+		return fmt.Errorf("sampling limits must not be negative: %w", errConfigRequired)
+	}
+	return nil
+}
+
+func (c SamplingConfig) withDefaults() SamplingConfig {
+	if c.Interval == 0 {
+		c.Interval = time.Second
+	}
+	if c.First == 0 {
+		c.First = 100
+	}
+	if c.Thereafter == 0 {
+		c.Thereafter = 100
+	}
+	return c
+}
+
+// DropHook вызывается на каждую отброшенную запись. Пакет log не зависит от metrics,
+// поэтому счетчики подключает приложение через SetDropHook.
+type DropHook func(level slog.Level, reason string)
+
+var dropHook atomic.Pointer[DropHook]
+
+// SetDropHook задает получателя отброшенных записей, nil отключает его
+func SetDropHook(hook DropHook) {
+	if hook == nil {
+		dropHook.Store(nil)
+		return
+	}
+	dropHook.Store(&hook)
+}
+
+func dropped(level slog.Level, reason string) {
+	if hook := dropHook.Load(); hook != nil {
+		(*hook)(level, reason)
+	}
+}
+
+type sampleKey struct {
+	level   slog.Level
+	message string
+}
+
+type sampleEntry struct {
+	start time.Time
+	count int
+	// first и next - первая запись окна и handler, которым она записана, для строки с числом повторов
+	first slog.Record
+	next  slog.Handler
+}
+
+type samplerCore struct {
+	cfg     SamplingConfig
+	mu      sync.Mutex
+	entries map[sampleKey]*sampleEntry
+	now     func() time.Time
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// samplingHandler отбрасывает повторяющиеся записи по SamplingConfig
+type samplingHandler struct {
+	core *samplerCore
+	next slog.Handler
+}
+
+func newSamplingHandler(next slog.Handler, cfg SamplingConfig) *samplingHandler {
+	core := &samplerCore{
+		cfg:     cfg.withDefaults(),
+		entries: make(map[sampleKey]*sampleEntry),
+		now:     time.Now,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go core.run()
+	return &samplingHandler{core: core, next: next}
+}
+
+func (h *samplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *samplingHandler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level >= slog.LevelError || h.core.allow(record, h.next) {
+		return h.next.Handle(ctx, record)
+	}
+	return nil
+}
+
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{core: h.core, next: h.next.WithAttrs(attrs)}
+}
+
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{core: h.core, next: h.next.WithGroup(name)}
+}
+
+// Close выводит накопленные повторы и останавливает фоновую очистку
+func (h *samplingHandler) Close(ctx context.Context) error {
+	h.core.closeOnce.Do(func() { close(h.core.stop) })
+	select {
+	case <-h.core.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *samplerCore) allow(record slog.Record, next slog.Handler) bool {
+	key := sampleKey{level: record.Level, message: record.Message}
+	now := c.now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	var expired *sampleEntry
+	if ok && now.Sub(entry.start) >= c.cfg.Interval {
+		expired, ok = entry, false
+	}
+	if !ok {
+		entry = &sampleEntry{start: now}
+		if c.cfg.Mode == SamplingDedup {
+			entry.first, entry.next = record.Clone(), next
+		}
+		c.entries[key] = entry
+	}
+	entry.count++
+	count := entry.count
+	c.mu.Unlock()
+
+	c.emitRepeats(expired)
+
+	switch c.cfg.Mode {
+	case SamplingDedup:
+		if count == 1 {
+			return true
+		}
+		dropped(record.Level, DropReasonDeduplicated)
+		return false
+	default:
+		if count <= c.cfg.First || (count-c.cfg.First)%c.cfg.Thereafter == 0 {
+			return true
+		}
+		dropped(record.Level, DropReasonSampled)
+		return false
+	}
+}
+
+func (c *samplerCore) run() {
+	defer close(c.done)
+	ticker := time.NewTicker(c.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.sweep(false)
+		case <-c.stop:
+			c.sweep(true)
+			return
+		}
+	}
+}
+
+// sweep удаляет истекшие окна, а в режиме dedup выводит по ним строку с числом повторов
+func (c *samplerCore) sweep(all bool) {
+	now := c.now()
+	var expired []*sampleEntry
+
+	c.mu.Lock()
+	for key, entry := range c.entries {
+		if all || now.Sub(entry.start) >= c.cfg.Interval {
+			delete(c.entries, key)
+			expired = append(expired, entry)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, entry := range expired {
+		c.emitRepeats(entry)
+	}
+}
+
+func (c *samplerCore) emitRepeats(entry *sampleEntry) {
+	if entry == nil || entry.next == nil || entry.count < 2 {
+		return
+	}
+	record := entry.first.Clone()
+	record.Time = c.now()
+	record.AddAttrs(slog.Int(repeatedAttr, entry.count-1))
+	_ = entry.next.Handle(context.Background(), record)
+}
@@ -0,0 +1,59 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSamplingHandler(t *testing.T) {
+	var drops []string
+	SetDropHook(func(_ slog.Level, reason string) { drops = append(drops, reason) })
+	t.Cleanup(func() { SetDropHook(nil) })
+
+	var buf bytes.Buffer
+	h := newSamplingHandler(slog.NewTextHandler(&buf, nil), SamplingConfig{Mode: SamplingSample, Interval: time.Hour, First: 2, Thereafter: 3})
+	logger := slog.New(h)
+	ctx := context.Background()
+
+	for range 8 {
+		logger.InfoContext(ctx, "query")
+	}
+	for range 3 {
+		logger.ErrorContext(ctx, "failed")
+	}
+	require.NoError(t, h.Close(ctx))
+
+	require.Equal(t, 4, strings.Count(buf.String(), "msg=query"), "records 1, 2, 5 and 8 pass")
+	require.Equal(t, 3, strings.Count(buf.String(), "msg=failed"), "errors are always kept")
+	require.Len(t, drops, 4)
+	require.Equal(t, DropReasonSampled, drops[0])
+}
+
+func TestDedupHandler(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Unix(0, 0)
+	h := newSamplingHandler(slog.NewTextHandler(&buf, nil), SamplingConfig{Mode: SamplingDedup, Interval: time.Hour})
+	h.core.now = func() time.Time { return now }
+	logger := slog.New(h).With("system", "nats")
+	ctx := context.Background()
+
+	for range 4 {
+		logger.InfoContext(ctx, "message handled", "subject", "orders")
+	}
+	require.Equal(t, 1, strings.Count(buf.String(), "message handled"))
+
+	now = now.Add(time.Hour)
+	logger.InfoContext(ctx, "message handled", "subject", "orders")
+	require.Contains(t, buf.String(), "system=nats subject=orders repeated=3", "expired window is summarized with the count")
+
+	logger.InfoContext(ctx, "message handled", "subject", "orders")
+	require.NoError(t, h.Close(ctx))
+	require.Contains(t, buf.String(), "repeated=1", "close flushes pending repeats")
+	require.Equal(t, 4, strings.Count(buf.String(), "message handled"))
+}
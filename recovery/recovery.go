@@ -0,0 +1,106 @@
+// Package recovery содержит общую обработку паник для HTTP, gRPC, NATS, cron джоб и Telegram ботов:
+// лог со стеком, событие в Sentry и счетчик паник, чтобы поведение было одинаковым везде.
+//
+// Использование:
+//
+//	defer func() {
+//		if r := recover(); r != nil {
+//			err = recovery.Handle(ctx, recovery.ComponentGRPC, r, map[string]string{"grpc.method": method})
+//		}
+//	}()
+package recovery
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+
+	"github.com/Rasikrr/core/log"
+	coreMetrics "github.com/Rasikrr/core/metrics"
+	"github.com/Rasikrr/core/sentry"
+	sentrySDK "github.com/getsentry/sentry-go"
+)
+
+const (
+	ComponentHTTP     = "http"
+	ComponentGRPC     = "grpc"
+	ComponentNATS     = "nats"
+	ComponentCron     = "cron"
+	ComponentTelegram = "telegram"
+)
+
+// PanicError - паника, превращенная в ошибку
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap позволяет проверять errors.Is/As, если паника была вызвана с ошибкой
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+var (
+	once   sync.Once
+	panics coreMetrics.CounterVec // {component}
+)
+
+func initRecoveryMetrics() {
+	once.Do(func() {
+		panics = coreMetrics.NewCounterVec("recovery", "panics_total", "Recovered panics", []string{"component"}, nil)
+	})
+}
+
+// Handle обрабатывает значение recover(): пишет лог со стеком, отправляет событие в Sentry
+// и увеличивает счетчик паник. tags добавляются к событию Sentry и логу.
+// Вызывающий сам решает, как ответить клиенту: статус 500, codes.Internal и т.п.
+func Handle(ctx context.Context, component string, r any, tags map[string]string) *PanicError {
+	initRecoveryMetrics()
+
+	perr := &PanicError{Value: r, Stack: debug.Stack()}
+	panics.WithLabelValues(component).Inc()
+
+	attrs := make([]log.Attr, 0, len(tags)+3)
+	attrs = append(attrs,
+		log.String("component", component),
+		log.String("panic", fmt.Sprint(r)),
+		log.String("stacktrace", string(perr.Stack)),
+	)
+	for k, v := range tags {
+		attrs = append(attrs, log.String(k, v))
+	}
+	// warn, а не error: событие в Sentry отправляется ниже со стеком, sentry handler логгера продублировал бы его
+	log.Warn(ctx, "recovered from panic", attrs...)
+
+	captureSentry(ctx, component, perr, tags)
+	return perr
+}
+
+func captureSentry(ctx context.Context, component string, perr *PanicError, tags map[string]string) {
+	hub := sentry.GetHubFromContext(ctx)
+	if hub == nil {
+		var err error
+		if hub, err = sentry.CurrentHub(); err != nil {
+			return
+		}
+	}
+
+	hub.WithScope(func(scope *sentrySDK.Scope) {
+		scope.SetLevel(sentrySDK.LevelFatal)
+		scope.SetTag("panic.component", component)
+		for k, v := range tags {
+			scope.SetTag(k, v)
+		}
+		scope.SetContext("panic", map[string]interface{}{
+			"value":      fmt.Sprintf("%v", perr.Value),
+			"stacktrace": string(perr.Stack),
+		})
+		hub.CaptureException(perr)
+	})
+}
@@ -0,0 +1,303 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Rasikrr/core/log"
+)
+
+// ErrCircuitOpen возвращается без выполнения вызова, пока breaker открыт
+var ErrCircuitOpen = errors.New("resilience: circuit breaker is open")
+
+// errPanicked учитывает панику в Execute как ошибку независимо от IsFailure
+var errPanicked = errors.New("resilience: call panicked")
+
+type State int
+
+const (
+	StateClosed State = iota
+	StateHalfOpen
+	StateOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateHalfOpen:
+		return "half-open"
+	case StateOpen:
+		return "open"
+	default:
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+}
+
+const (
+	defaultWindowSize       = 100
+	defaultMinCalls         = 20
+	defaultFailureRate      = 0.5
+	defaultSlowCallRate     = 1
+	defaultOpenTimeout      = 30 * time.Second
+	defaultHalfOpenMaxCalls = 5
+)
+
+type BreakerConfig struct {
+	Name string `yaml:"name"`
+	// WindowSize - число последних вызовов, по которым считаются доли ошибок и медленных вызовов
+	WindowSize int `yaml:"window_size" env-default:"100"`
+	// MinCalls - минимум вызовов в окне, до которого breaker не размыкается
+	MinCalls int `yaml:"min_calls" env-default:"20"`
+	// FailureRate - доля ошибок (0..1], при которой breaker размыкается
+	FailureRate float64 `yaml:"failure_rate" env-default:"0.5"`
+	// SlowCallDuration - вызовы дольше этого времени считаются медленными. 0 отключает учет.
+	SlowCallDuration time.Duration `yaml:"slow_call_duration"`
+	// SlowCallRate - доля медленных вызовов (0..1], при которой breaker размыкается
+	SlowCallRate float64 `yaml:"slow_call_rate" env-default:"1"`
+	// OpenTimeout - время в открытом состоянии до перехода в half-open
+	OpenTimeout time.Duration `yaml:"open_timeout" env-default:"30s"`
+	// HalfOpenMaxCalls - число пробных вызовов в half-open, после успеха которых breaker замыкается
+	HalfOpenMaxCalls int `yaml:"half_open_max_calls" env-default:"5"`
+
+	// IsFailure решает, считается ли ошибка отказом зависимости. По умолчанию - любая ошибка,
+	// кроме отмены контекста вызывающим.
+	IsFailure func(err error) bool `yaml:"-"`
+}
+
+func (c BreakerConfig) withDefaults() BreakerConfig {
+	if c.Name == "" {
+		c.Name = "default"
+	}
+	if c.WindowSize <= 0 {
+		c.WindowSize = defaultWindowSize
+	}
+	if c.MinCalls <= 0 {
+		c.MinCalls = defaultMinCalls
+	}
+	c.MinCalls = min(c.MinCalls, c.WindowSize)
+	if c.FailureRate <= 0 {
+		c.FailureRate = defaultFailureRate
+	}
+	if c.SlowCallRate <= 0 {
+		c.SlowCallRate = defaultSlowCallRate
+	}
+	if c.OpenTimeout <= 0 {
+		c.OpenTimeout = defaultOpenTimeout
+	}
+	if c.HalfOpenMaxCalls <= 0 {
+		c.HalfOpenMaxCalls = defaultHalfOpenMaxCalls
+	}
+	if c.IsFailure == nil {
+		c.IsFailure = defaultIsFailure
+	}
+	return c
+}
+
+func defaultIsFailure(err error) bool {
+	return err != nil && !errors.Is(err, context.Canceled)
+}
+
+type outcome struct {
+	failed bool
+	slow   bool
+}
+
+// CircuitBreaker перестает выполнять вызовы к зависимости, когда в скользящем окне
+// последних вызовов доля ошибок или медленных вызовов превышает порог.
+// Через OpenTimeout пропускаются HalfOpenMaxCalls пробных вызовов: любая ошибка снова
+// размыкает цепь, успех всех проб замыкает ее.
+type CircuitBreaker struct {
+	cfg BreakerConfig
+	now func() time.Time
+
+	mu         sync.Mutex
+	state      State
+	generation uint64
+	openedAt   time.Time
+
+	window   []outcome
+	pos      int
+	count    int
+	failures int
+	slow     int
+
+	halfOpenInflight  int
+	halfOpenSucceeded int
+}
+
+func NewCircuitBreaker(cfg BreakerConfig) *CircuitBreaker {
+	initResilienceMetrics()
+	cfg = cfg.withDefaults()
+	cb := &CircuitBreaker{
+		cfg:    cfg,
+		now:    time.Now,
+		window: make([]outcome, cfg.WindowSize),
+	}
+	metrics.breakerState.WithLabelValues(cfg.Name).Set(float64(StateClosed))
+	return cb
+}
+
+func (cb *CircuitBreaker) Name() string {
+	return cb.cfg.Name
+}
+
+func (cb *CircuitBreaker) State() State {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.refreshLocked()
+	return cb.state
+}
+
+// Execute выполняет fn, если breaker разрешает вызов, и учитывает результат
+func (cb *CircuitBreaker) Execute(ctx context.Context, fn func(ctx context.Context) error) error {
+	done, err := cb.Allow()
+	if err != nil {
+		return err
+	}
+	// Паника в fn считается ошибкой, иначе в half-open вызов навсегда занял бы слот
+	defer func() {
+		if r := recover(); r != nil {
+			done(errPanicked)
+			panic(r)
+		}
+	}()
+	err = fn(ctx)
+	done(err)
+	return err
+}
+
+// Allow резервирует вызов. Возвращаемую функцию нужно вызвать с результатом ровно один раз.
+// Используется адаптерами, которым нужно классифицировать результат самостоятельно.
+func (cb *CircuitBreaker) Allow() (func(err error), error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.refreshLocked()
+	switch cb.state {
+	case StateOpen:
+		metrics.breakerCalls.WithLabelValues(cb.cfg.Name, "rejected").Inc()
+		return nil, ErrCircuitOpen
+	case StateHalfOpen:
+		if cb.halfOpenInflight+cb.halfOpenSucceeded >= cb.cfg.HalfOpenMaxCalls {
+			metrics.breakerCalls.WithLabelValues(cb.cfg.Name, "rejected").Inc()
+			return nil, ErrCircuitOpen
+		}
+		cb.halfOpenInflight++
+	}
+
+	gen := cb.generation
+	start := cb.now()
+	var once sync.Once
+	return func(err error) {
+		once.Do(func() {
+			cb.record(gen, outcome{
+				failed: errors.Is(err, errPanicked) || cb.cfg.IsFailure(err),
+				slow:   cb.cfg.SlowCallDuration > 0 && cb.now().Sub(start) > cb.cfg.SlowCallDuration,
+			})
+		})
+	}, nil
+}
+
+func (cb *CircuitBreaker) record(gen uint64, o outcome) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch {
+	case o.failed:
+		metrics.breakerCalls.WithLabelValues(cb.cfg.Name, "failure").Inc()
+	case o.slow:
+		metrics.breakerCalls.WithLabelValues(cb.cfg.Name, "slow").Inc()
+	default:
+		metrics.breakerCalls.WithLabelValues(cb.cfg.Name, "success").Inc()
+	}
+
+	// Результат вызова, начатого в предыдущем состоянии, не влияет на текущее
+	if gen != cb.generation {
+		return
+	}
+
+	switch cb.state {
+	case StateClosed:
+		cb.push(o)
+		if cb.count < cb.cfg.MinCalls {
+			return
+		}
+		failureRate := float64(cb.failures) / float64(cb.count)
+		slowRate := float64(cb.slow) / float64(cb.count)
+		if failureRate >= cb.cfg.FailureRate || (cb.cfg.SlowCallDuration > 0 && slowRate >= cb.cfg.SlowCallRate) {
+			cb.transitionLocked(StateOpen)
+		}
+	case StateHalfOpen:
+		cb.halfOpenInflight--
+		if o.failed || o.slow {
+			cb.transitionLocked(StateOpen)
+			return
+		}
+		cb.halfOpenSucceeded++
+		if cb.halfOpenSucceeded >= cb.cfg.HalfOpenMaxCalls {
+			cb.transitionLocked(StateClosed)
+		}
+	}
+}
+
+func (cb *CircuitBreaker) push(o outcome) {
+	if cb.count == len(cb.window) {
+		old := cb.window[cb.pos]
+		if old.failed {
+			cb.failures--
+		}
+		if old.slow {
+			cb.slow--
+		}
+	} else {
+		cb.count++
+	}
+	cb.window[cb.pos] = o
+	cb.pos = (cb.pos + 1) % len(cb.window)
+	if o.failed {
+		cb.failures++
+	}
+	if o.slow {
+		cb.slow++
+	}
+}
+
+func (cb *CircuitBreaker) refreshLocked() {
+	if cb.state == StateOpen && cb.now().Sub(cb.openedAt) >= cb.cfg.OpenTimeout {
+		cb.transitionLocked(StateHalfOpen)
+	}
+}
+
+func (cb *CircuitBreaker) transitionLocked(to State) {
+	from := cb.state
+	cb.state = to
+	cb.generation++
+	cb.halfOpenInflight = 0
+	cb.halfOpenSucceeded = 0
+
+	switch to {
+	case StateOpen:
+		cb.openedAt = cb.now()
+	case StateClosed:
+		cb.window = make([]outcome, len(cb.window))
+		cb.pos, cb.count, cb.failures, cb.slow = 0, 0, 0, 0
+	}
+
+	metrics.breakerState.WithLabelValues(cb.cfg.Name).Set(float64(to))
+	metrics.breakerTransitions.WithLabelValues(cb.cfg.Name, from.String(), to.String()).Inc()
+
+	attrs := []log.Attr{
+		log.String("breaker", cb.cfg.Name),
+		log.String("from", from.String()),
+		log.String("to", to.String()),
+	}
+	if to == StateOpen {
+		log.Warn(context.Background(), "circuit breaker opened", attrs...)
+		return
+	}
+	log.Info(context.Background(), "circuit breaker state changed", attrs...)
+}
@@ -0,0 +1,92 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Rasikrr/core/log"
+)
+
+// ErrBulkheadFull возвращается, если слот не освободился за MaxWait
+var ErrBulkheadFull = errors.New("resilience: bulkhead is full")
+
+type BulkheadConfig struct {
+	Name string `yaml:"name"`
+	// MaxConcurrent - максимум одновременных вызовов
+	MaxConcurrent int `yaml:"max_concurrent" env-default:"10"`
+	// MaxWait - сколько ждать свободного слота. 0 - отказывать сразу.
+	MaxWait time.Duration `yaml:"max_wait"`
+}
+
+// Bulkhead ограничивает число одновременных вызовов зависимости,
+// чтобы медленная зависимость не забрала все горутины и соединения сервиса
+type Bulkhead struct {
+	cfg BulkheadConfig
+	sem chan struct{}
+}
+
+func NewBulkhead(cfg BulkheadConfig) *Bulkhead {
+	initResilienceMetrics()
+	if cfg.Name == "" {
+		cfg.Name = "default"
+	}
+	if cfg.MaxConcurrent <= 0 {
+		cfg.MaxConcurrent = 10
+	}
+	return &Bulkhead{
+		cfg: cfg,
+		sem: make(chan struct{}, cfg.MaxConcurrent),
+	}
+}
+
+// Acquire занимает слот. Возвращаемую функцию нужно вызвать после завершения вызова.
+func (b *Bulkhead) Acquire(ctx context.Context) (func(), error) {
+	select {
+	case b.sem <- struct{}{}:
+		return b.acquired(), nil
+	default:
+	}
+
+	if b.cfg.MaxWait <= 0 {
+		return nil, b.reject(ctx)
+	}
+
+	timer := time.NewTimer(b.cfg.MaxWait)
+	defer timer.Stop()
+	select {
+	case b.sem <- struct{}{}:
+		return b.acquired(), nil
+	case <-timer.C:
+		return nil, b.reject(ctx)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Execute выполняет fn в свободном слоте
+func (b *Bulkhead) Execute(ctx context.Context, fn func(ctx context.Context) error) error {
+	release, err := b.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return fn(ctx)
+}
+
+func (b *Bulkhead) acquired() func() {
+	metrics.bulkheadInflight.WithLabelValues(b.cfg.Name).Inc()
+	return func() {
+		metrics.bulkheadInflight.WithLabelValues(b.cfg.Name).Dec()
+		<-b.sem
+	}
+}
+
+func (b *Bulkhead) reject(ctx context.Context) error {
+	metrics.bulkheadRejected.WithLabelValues(b.cfg.Name).Inc()
+	log.Debug(ctx, "bulkhead rejected call",
+		log.String("bulkhead", b.cfg.Name),
+		log.Int("max_concurrent", b.cfg.MaxConcurrent),
+	)
+	return ErrBulkheadFull
+}
@@ -0,0 +1,69 @@
+package resilience
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// IsGRPCFailure считает отказом зависимости только коды, говорящие о ее недоступности или перегрузке.
+// Бизнес-ошибки (NotFound, InvalidArgument и т.п.) breaker не размыкают.
+func IsGRPCFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Internal, codes.Unknown, codes.DataLoss:
+		return true
+	}
+	return false
+}
+
+// UnaryClientInterceptor пропускает вызовы через bulkhead и circuit breaker. Любой из них может быть nil.
+// Для gRPC breaker стоит создавать с IsFailure: IsGRPCFailure.
+func UnaryClientInterceptor(cb *CircuitBreaker, bh *Bulkhead) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if bh != nil {
+			release, err := bh.Acquire(ctx)
+			if err != nil {
+				return status.Error(codes.ResourceExhausted, err.Error())
+			}
+			defer release()
+		}
+		if cb == nil {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		done, err := cb.Allow()
+		if err != nil {
+			return status.Error(codes.Unavailable, err.Error())
+		}
+		err = invoker(ctx, method, req, reply, cc, opts...)
+		done(err)
+		return err
+	}
+}
+
+// StreamClientInterceptor проверяет breaker и bulkhead при открытии стрима.
+// Результатом для breaker считается ошибка открытия, слот bulkhead занимается только на время открытия.
+func StreamClientInterceptor(cb *CircuitBreaker, bh *Bulkhead) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if bh != nil {
+			release, err := bh.Acquire(ctx)
+			if err != nil {
+				return nil, status.Error(codes.ResourceExhausted, err.Error())
+			}
+			defer release()
+		}
+		if cb == nil {
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+
+		done, err := cb.Allow()
+		if err != nil {
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		done(err)
+		return cs, err
+	}
+}
@@ -0,0 +1,100 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+type HedgeConfig struct {
+	Name string `yaml:"name"`
+	// Delay - через сколько после предыдущей попытки запускается следующая, если ответа еще нет.
+	// Обычно выбирается около p95 латентности зависимости.
+	Delay time.Duration `yaml:"delay"`
+	// MaxHedges - число дополнительных попыток сверх первой
+	MaxHedges int `yaml:"max_hedges" env-default:"1"`
+}
+
+type hedgeResult[T any] struct {
+	value  T
+	err    error
+	hedged bool
+}
+
+// Hedge запускает fn и, если ответа нет за Delay, параллельно запускает еще до MaxHedges попыток.
+// Возвращается первый успешный результат, остальные попытки отменяются через ctx.
+// Подходит только для идемпотентных вызовов.
+func Hedge[T any](ctx context.Context, cfg HedgeConfig, fn func(ctx context.Context) (T, error)) (T, error) {
+	initResilienceMetrics()
+	if cfg.Name == "" {
+		cfg.Name = "default"
+	}
+	if cfg.MaxHedges < 0 {
+		cfg.MaxHedges = 0
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	total := cfg.MaxHedges + 1
+	results := make(chan hedgeResult[T], total)
+	launch := func(hedged bool) {
+		go func() {
+			v, err := fn(ctx)
+			results <- hedgeResult[T]{value: v, err: err, hedged: hedged}
+		}()
+	}
+
+	launch(false)
+	launched, received := 1, 0
+
+	var (
+		errs  []error
+		timer *time.Timer
+		tick  <-chan time.Time
+	)
+	if launched < total {
+		timer = time.NewTimer(cfg.Delay)
+		defer timer.Stop()
+		tick = timer.C
+	}
+
+	next := func() {
+		launch(true)
+		launched++
+		metrics.hedges.WithLabelValues(cfg.Name, "launched").Inc()
+		if launched < total {
+			timer.Reset(cfg.Delay)
+		} else {
+			tick = nil
+		}
+	}
+
+	for {
+		select {
+		case <-tick:
+			next()
+		case r := <-results:
+			received++
+			if r.err == nil {
+				if r.hedged {
+					metrics.hedges.WithLabelValues(cfg.Name, "won").Inc()
+				}
+				return r.value, nil
+			}
+			errs = append(errs, r.err)
+			// Ошибка не повод ждать Delay - следующая попытка запускается сразу
+			if launched < total && ctx.Err() == nil {
+				next()
+				continue
+			}
+			if received == launched {
+				var zero T
+				return zero, errors.Join(errs...)
+			}
+		case <-ctx.Done():
+			var zero T
+			return zero, errors.Join(append(errs, ctx.Err())...)
+		}
+	}
+}
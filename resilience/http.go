@@ -0,0 +1,53 @@
+package resilience
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrHTTPServer - результат 5xx ответа для breaker. Сам ответ при этом возвращается вызывающему.
+var ErrHTTPServer = errors.New("resilience: http server error")
+
+type roundTripper struct {
+	base http.RoundTripper
+	cb   *CircuitBreaker
+	bh   *Bulkhead
+}
+
+// RoundTripper пропускает запросы через bulkhead и circuit breaker. Любой из них может быть nil.
+// Ответы 5xx считаются отказом: в IsFailure breaker'а приходит ошибка, обернутая в ErrHTTPServer.
+func RoundTripper(base http.RoundTripper, cb *CircuitBreaker, bh *Bulkhead) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &roundTripper{base: base, cb: cb, bh: bh}
+}
+
+func (t *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.bh != nil {
+		release, err := t.bh.Acquire(req.Context())
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+	if t.cb == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	done, err := t.cb.Allow()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	switch {
+	case err != nil:
+		done(err)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		done(fmt.Errorf("%w: %s", ErrHTTPServer, resp.Status))
+	default:
+		done(nil)
+	}
+	return resp, err
+}
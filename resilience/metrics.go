@@ -0,0 +1,36 @@
+package resilience
+
+import (
+	"sync"
+
+	coreMetrics "github.com/Rasikrr/core/metrics"
+)
+
+type Metrics struct {
+	breakerState       coreMetrics.GaugeVec   // {name} 0 - closed, 1 - half-open, 2 - open
+	breakerTransitions coreMetrics.CounterVec // {name, from, to}
+	breakerCalls       coreMetrics.CounterVec // {name, outcome=success|failure|slow|rejected}
+	bulkheadInflight   coreMetrics.GaugeVec   // {name}
+	bulkheadRejected   coreMetrics.CounterVec // {name}
+	retries            coreMetrics.CounterVec // {name}
+	hedges             coreMetrics.CounterVec // {name, outcome=launched|won}
+}
+
+var (
+	metrics *Metrics
+	once    sync.Once
+)
+
+func initResilienceMetrics() {
+	once.Do(func() {
+		metrics = &Metrics{
+			breakerState:       coreMetrics.NewGaugeVec("resilience", "breaker_state", "Circuit breaker state (0 - closed, 1 - half-open, 2 - open)", []string{"name"}, nil),
+			breakerTransitions: coreMetrics.NewCounterVec("resilience", "breaker_transitions_total", "Circuit breaker state transitions", []string{"name", "from", "to"}, nil),
+			breakerCalls:       coreMetrics.NewCounterVec("resilience", "breaker_calls_total", "Calls through circuit breaker", []string{"name", "outcome"}, nil),
+			bulkheadInflight:   coreMetrics.NewGaugeVec("resilience", "bulkhead_inflight", "Concurrent calls inside bulkhead", []string{"name"}, nil),
+			bulkheadRejected:   coreMetrics.NewCounterVec("resilience", "bulkhead_rejected_total", "Calls rejected by bulkhead", []string{"name"}, nil),
+			retries:            coreMetrics.NewCounterVec("resilience", "retries_total", "Retried calls", []string{"name"}, nil),
+			hedges:             coreMetrics.NewCounterVec("resilience", "hedges_total", "Hedged calls", []string{"name", "outcome"}, nil),
+		}
+	})
+}
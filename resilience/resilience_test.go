@@ -0,0 +1,134 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errDependency = errors.New("dependency failed")
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	now := time.Now()
+	cb := NewCircuitBreaker(BreakerConfig{
+		Name:             "test",
+		WindowSize:       4,
+		MinCalls:         4,
+		FailureRate:      0.5,
+		OpenTimeout:      time.Second,
+		HalfOpenMaxCalls: 2,
+	})
+	cb.now = func() time.Time { return now }
+
+	fail := func(context.Context) error { return errDependency }
+	ok := func(context.Context) error { return nil }
+	ctx := context.Background()
+
+	require.NoError(t, cb.Execute(ctx, ok))
+	require.NoError(t, cb.Execute(ctx, ok))
+	require.ErrorIs(t, cb.Execute(ctx, fail), errDependency)
+	require.Equal(t, StateClosed, cb.State())
+	require.ErrorIs(t, cb.Execute(ctx, fail), errDependency)
+	require.Equal(t, StateOpen, cb.State())
+	require.ErrorIs(t, cb.Execute(ctx, ok), ErrCircuitOpen)
+
+	// После OpenTimeout пропускаются пробные вызовы, ошибка снова размыкает цепь
+	now = now.Add(time.Second)
+	require.Equal(t, StateHalfOpen, cb.State())
+	require.ErrorIs(t, cb.Execute(ctx, fail), errDependency)
+	require.Equal(t, StateOpen, cb.State())
+
+	now = now.Add(time.Second)
+	require.NoError(t, cb.Execute(ctx, ok))
+	require.NoError(t, cb.Execute(ctx, ok))
+	require.Equal(t, StateClosed, cb.State())
+}
+
+func TestCircuitBreakerPanicInHalfOpen(t *testing.T) {
+	now := time.Now()
+	cb := NewCircuitBreaker(BreakerConfig{
+		Name:             "panic",
+		WindowSize:       1,
+		MinCalls:         1,
+		OpenTimeout:      time.Second,
+		HalfOpenMaxCalls: 1,
+	})
+	cb.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.ErrorIs(t, cb.Execute(ctx, func(context.Context) error { return errDependency }), errDependency)
+	now = now.Add(time.Second)
+	require.Equal(t, StateHalfOpen, cb.State())
+
+	require.Panics(t, func() {
+		_ = cb.Execute(ctx, func(context.Context) error { panic("boom") })
+	})
+	require.Equal(t, StateOpen, cb.State(), "panic counts as a failed probe")
+
+	// Слот пробного вызова освобожден: после таймаута breaker снова пропускает вызовы
+	now = now.Add(time.Second)
+	require.NoError(t, cb.Execute(ctx, func(context.Context) error { return nil }))
+	require.Equal(t, StateClosed, cb.State())
+}
+
+func TestCircuitBreakerSlowCalls(t *testing.T) {
+	now := time.Now()
+	cb := NewCircuitBreaker(BreakerConfig{
+		Name:             "slow",
+		WindowSize:       2,
+		MinCalls:         2,
+		SlowCallDuration: 100 * time.Millisecond,
+		SlowCallRate:     1,
+	})
+	cb.now = func() time.Time { return now }
+
+	slow := func(context.Context) error {
+		now = now.Add(time.Second)
+		return nil
+	}
+	require.NoError(t, cb.Execute(context.Background(), slow))
+	require.NoError(t, cb.Execute(context.Background(), slow))
+	require.Equal(t, StateOpen, cb.State())
+}
+
+func TestBulkhead(t *testing.T) {
+	bh := NewBulkhead(BulkheadConfig{Name: "test", MaxConcurrent: 1})
+
+	release, err := bh.Acquire(context.Background())
+	require.NoError(t, err)
+	require.ErrorIs(t, bh.Execute(context.Background(), func(context.Context) error { return nil }), ErrBulkheadFull)
+
+	release()
+	require.NoError(t, bh.Execute(context.Background(), func(context.Context) error { return nil }))
+}
+
+func TestRetry(t *testing.T) {
+	var calls atomic.Int32
+	err := Retry(context.Background(), RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond}, func(context.Context) error {
+		if calls.Add(1) < 3 {
+			return errDependency
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 3, calls.Load())
+}
+
+func TestHedge(t *testing.T) {
+	var calls atomic.Int32
+	v, err := Hedge(context.Background(), HedgeConfig{Delay: 10 * time.Millisecond, MaxHedges: 1}, func(ctx context.Context) (string, error) {
+		if calls.Add(1) == 1 {
+			// Первая попытка зависает и должна быть отменена после ответа второй
+			<-ctx.Done()
+			return "", ctx.Err()
+		}
+		return "hedged", nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "hedged", v)
+	require.EqualValues(t, 2, calls.Load())
+}
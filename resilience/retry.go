@@ -0,0 +1,93 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+type RetryConfig struct {
+	Name string `yaml:"name"`
+	// MaxAttempts - общее число попыток, включая первую
+	MaxAttempts    int           `yaml:"max_attempts" env-default:"3"`
+	InitialBackoff time.Duration `yaml:"initial_backoff" env-default:"100ms"`
+	MaxBackoff     time.Duration `yaml:"max_backoff" env-default:"5s"`
+	Multiplier     float64       `yaml:"multiplier" env-default:"2"`
+	// Jitter - доля задержки (0..1], на которую она случайно уменьшается. 1 - full jitter.
+	Jitter float64 `yaml:"jitter" env-default:"1"`
+
+	// RetryIf решает, повторять ли вызов после ошибки. По умолчанию повторяется все,
+	// кроме отмены контекста и ошибок breaker/bulkhead.
+	RetryIf func(err error) bool `yaml:"-"`
+}
+
+func (c RetryConfig) withDefaults() RetryConfig {
+	if c.Name == "" {
+		c.Name = "default"
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 3
+	}
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = 100 * time.Millisecond
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = 5 * time.Second
+	}
+	if c.Multiplier < 1 {
+		c.Multiplier = 2
+	}
+	if c.Jitter <= 0 || c.Jitter > 1 {
+		c.Jitter = 1
+	}
+	if c.RetryIf == nil {
+		c.RetryIf = defaultRetryIf
+	}
+	return c
+}
+
+func defaultRetryIf(err error) bool {
+	return !errors.Is(err, context.Canceled) &&
+		!errors.Is(err, context.DeadlineExceeded) &&
+		!errors.Is(err, ErrCircuitOpen) &&
+		!errors.Is(err, ErrBulkheadFull)
+}
+
+// Retry выполняет fn до MaxAttempts раз с экспоненциальной задержкой и jitter.
+// Возвращается ошибка последней попытки.
+func Retry(ctx context.Context, cfg RetryConfig, fn func(ctx context.Context) error) error {
+	initResilienceMetrics()
+	cfg = cfg.withDefaults()
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(ctx); err == nil || attempt >= cfg.MaxAttempts || !cfg.RetryIf(err) {
+			return err
+		}
+
+		timer := time.NewTimer(cfg.Backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+		metrics.retries.WithLabelValues(cfg.Name).Inc()
+	}
+}
+
+// Backoff возвращает задержку перед попыткой attempt+1
+func (c RetryConfig) Backoff(attempt int) time.Duration {
+	c = c.withDefaults()
+	d := float64(c.InitialBackoff)
+	for range attempt - 1 {
+		d *= c.Multiplier
+		if d >= float64(c.MaxBackoff) {
+			break
+		}
+	}
+	d = min(d, float64(c.MaxBackoff))
+	d -= d * c.Jitter * rand.Float64()
+	return time.Duration(d)
+}
@@ -3,10 +3,10 @@ package s3
 import (
 	"bytes"
 	"context"
-	"errors"
 	"fmt"
 	"io"
-	"strings"
+	"iter"
+	"slices"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -17,22 +17,19 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
-// Config содержит параметры конфигурации для S3 клиента
-type Config struct {
-	Region          string
-	Endpoint        string // Опционально, для совместимых с S3 сервисов (MinIO, LocalStack, Railway)
-	AccessKeyID     string
-	SecretAccessKey string
-	BucketName      string
-}
-
 // Client предоставляет методы для работы с AWS S3
 type Client struct {
 	s3Client      *s3.Client
 	presignClient *s3.PresignClient
 	uploader      *manager.Uploader
 	downloader    *manager.Downloader
+	multipart     multipartAPI
 	bucketName    string
+	partSize      int64
+	concurrency   int
+	checksum      types.ChecksumAlgorithm
+	// buckets - именованные бакеты из Config.Buckets
+	buckets map[string]string
 }
 
 // NewClient создает новый S3 клиент
@@ -49,6 +46,18 @@ func NewClient(ctx context.Context, cfg Config) (*Client, error) {
 	if cfg.BucketName == "" {
 		return nil, ErrS3EmptyBucket
 	}
+	for _, bucket := range cfg.Buckets {
+		if bucket == "" {
+			return nil, ErrS3EmptyBucket
+		}
+	}
+
+	if err := cfg.validateTransfer(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrS3InvalidInput, err)
+	}
+	cfg = cfg.withDefaults()
+
+	initS3Metrics()
 
 	// Настройка AWS конфигурации
 	awsCfg, err := config.LoadDefaultConfig(ctx,
@@ -84,13 +93,13 @@ func NewClient(ctx context.Context, cfg Config) (*Client, error) {
 
 	// Создание uploader и downloader для эффективной работы с большими файлами
 	uploader := manager.NewUploader(s3Client, func(u *manager.Uploader) {
-		u.PartSize = 10 * 1024 * 1024 // 10MB частями
-		u.Concurrency = 5             // 5 параллельных загрузок
+		u.PartSize = cfg.PartSize
+		u.Concurrency = cfg.Concurrency
 	})
 
 	downloader := manager.NewDownloader(s3Client, func(d *manager.Downloader) {
-		d.PartSize = 10 * 1024 * 1024 // 10MB частями
-		d.Concurrency = 5             // 5 параллельных скачиваний
+		d.PartSize = cfg.PartSize
+		d.Concurrency = cfg.Concurrency
 	})
 
 	return &Client{
@@ -98,12 +107,58 @@ func NewClient(ctx context.Context, cfg Config) (*Client, error) {
 		presignClient: presignClient,
 		uploader:      uploader,
 		downloader:    downloader,
+		multipart:     s3Client,
 		bucketName:    cfg.BucketName,
+		partSize:      cfg.PartSize,
+		concurrency:   cfg.Concurrency,
+		checksum:      cfg.checksumAlgorithm(),
+		buckets:       cfg.Buckets,
 	}, nil
 }
 
+// Bucket возвращает клиент для именованного бакета из Config.Buckets.
+// Клиент разделяет соединения и настройки с исходным.
+func (c *Client) Bucket(name string) (*Client, error) {
+	bucket, ok := c.buckets[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrS3UnknownBucket, name)
+	}
+	cp := *c
+	cp.bucketName = bucket
+	return &cp, nil
+}
+
+// BucketName возвращает имя бакета, с которым работает клиент
+func (c *Client) BucketName() string {
+	return c.bucketName
+}
+
+// HealthCheck проверяет доступность основного и всех именованных бакетов
+func (c *Client) HealthCheck(ctx context.Context) (err error) {
+	ctx, op := c.startOperation(ctx, "HealthCheck", "")
+	defer func() { op.end(err, 0) }()
+
+	for _, bucket := range c.distinctBuckets() {
+		if _, err = c.s3Client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(bucket)}); err != nil {
+			return fmt.Errorf("%w: bucket %q: %w", ErrS3Unavailable, bucket, err)
+		}
+	}
+	return nil
+}
+
+// distinctBuckets возвращает основной и именованные бакеты без повторов
+func (c *Client) distinctBuckets() []string {
+	buckets := []string{c.bucketName}
+	for _, bucket := range mapValues(c.buckets) {
+		if !slices.Contains(buckets, bucket) {
+			buckets = append(buckets, bucket)
+		}
+	}
+	return buckets
+}
+
 // Upload загружает файл в S3
-func (c *Client) Upload(ctx context.Context, key string, data []byte, contentType string) (string, error) {
+func (c *Client) Upload(ctx context.Context, key string, data []byte, contentType string, opts ...UploadOption) (_ string, err error) {
 	if key == "" {
 		return "", ErrS3EmptyKey
 	}
@@ -111,6 +166,9 @@ func (c *Client) Upload(ctx context.Context, key string, data []byte, contentTyp
 		return "", ErrS3EmptyData
 	}
 
+	ctx, op := c.startOperation(ctx, "Upload", key)
+	defer func() { op.end(err, int64(len(data))) }()
+
 	input := &s3.PutObjectInput{
 		Bucket: aws.String(c.bucketName),
 		Key:    aws.String(key),
@@ -120,6 +178,8 @@ func (c *Client) Upload(ctx context.Context, key string, data []byte, contentTyp
 	if contentType != "" {
 		input.ContentType = aws.String(contentType)
 	}
+	newUploadOptions(opts).apply(input)
+	input.ChecksumAlgorithm = c.checksum
 
 	result, err := c.uploader.Upload(ctx, input)
 	if err != nil {
@@ -134,7 +194,7 @@ func (c *Client) Upload(ctx context.Context, key string, data []byte, contentTyp
 }
 
 // UploadStream загружает файл из io.Reader в S3
-func (c *Client) UploadStream(ctx context.Context, key string, reader io.Reader, contentType string) (string, error) {
+func (c *Client) UploadStream(ctx context.Context, key string, reader io.Reader, contentType string, opts ...UploadOption) (_ string, err error) {
 	if key == "" {
 		return "", ErrS3EmptyKey
 	}
@@ -142,15 +202,21 @@ func (c *Client) UploadStream(ctx context.Context, key string, reader io.Reader,
 		return "", ErrS3EmptyData
 	}
 
+	ctx, op := c.startOperation(ctx, "UploadStream", key)
+	body, size := meterReader(reader)
+	defer func() { op.end(err, size()) }()
+
 	input := &s3.PutObjectInput{
 		Bucket: aws.String(c.bucketName),
 		Key:    aws.String(key),
-		Body:   reader,
+		Body:   body,
 	}
 
 	if contentType != "" {
 		input.ContentType = aws.String(contentType)
 	}
+	newUploadOptions(opts).apply(input)
+	input.ChecksumAlgorithm = c.checksum
 
 	result, err := c.uploader.Upload(ctx, input)
 	if err != nil {
@@ -165,51 +231,68 @@ func (c *Client) UploadStream(ctx context.Context, key string, reader io.Reader,
 }
 
 // Download скачивает файл из S3
-func (c *Client) Download(ctx context.Context, key string) ([]byte, error) {
+func (c *Client) Download(ctx context.Context, key string) (_ []byte, err error) {
 	if key == "" {
 		return nil, ErrS3EmptyKey
 	}
 
+	ctx, op := c.startOperation(ctx, "Download", key)
+	var n int64
+	defer func() { op.end(err, n) }()
+
 	buffer := manager.NewWriteAtBuffer([]byte{})
 
-	_, err := c.downloader.Download(ctx, buffer, &s3.GetObjectInput{
-		Bucket: aws.String(c.bucketName),
-		Key:    aws.String(key),
-	})
+	n, err = c.downloader.Download(ctx, buffer, c.getObjectInput(key))
 	if err != nil {
-		return nil, fmt.Errorf("%w: %w", ErrS3DownloadFailed, err)
+		return nil, downloadError(err)
 	}
 
 	return buffer.Bytes(), nil
 }
 
+// getObjectInput включает проверку контрольной суммы ответа, если задан Config.Checksum.
+// SDK проверяет только ответы с полным объектом и полной (не составной) суммой.
+func (c *Client) getObjectInput(key string) *s3.GetObjectInput {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(c.bucketName),
+		Key:    aws.String(key),
+	}
+	if c.checksum != "" {
+		input.ChecksumMode = types.ChecksumModeEnabled
+	}
+	return input
+}
+
 // DownloadStream скачивает файл из S3 в io.WriterAt
-func (c *Client) DownloadStream(ctx context.Context, key string, writer io.WriterAt) (int64, error) {
+func (c *Client) DownloadStream(ctx context.Context, key string, writer io.WriterAt) (n int64, err error) {
 	if key == "" {
 		return 0, ErrS3EmptyKey
 	}
 	if writer == nil {
-		return 0, errors.New("s3: writer cannot be nil")
+		return 0, errNilWriter
 	}
 
-	n, err := c.downloader.Download(ctx, writer, &s3.GetObjectInput{
-		Bucket: aws.String(c.bucketName),
-		Key:    aws.String(key),
-	})
+	ctx, op := c.startOperation(ctx, "DownloadStream", key)
+	defer func() { op.end(err, n) }()
+
+	n, err = c.downloader.Download(ctx, writer, c.getObjectInput(key))
 	if err != nil {
-		return 0, fmt.Errorf("%w: %w", ErrS3DownloadFailed, err)
+		return 0, downloadError(err)
 	}
 
 	return n, nil
 }
 
 // Delete удаляет файл из S3
-func (c *Client) Delete(ctx context.Context, key string) error {
+func (c *Client) Delete(ctx context.Context, key string) (err error) {
 	if key == "" {
 		return ErrS3EmptyKey
 	}
 
-	_, err := c.s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+	ctx, op := c.startOperation(ctx, "Delete", key)
+	defer func() { op.end(err, 0) }()
+
+	_, err = c.s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(c.bucketName),
 		Key:    aws.String(key),
 	})
@@ -221,23 +304,21 @@ func (c *Client) Delete(ctx context.Context, key string) error {
 }
 
 // DeleteMultiple удаляет несколько файлов из S3
-func (c *Client) DeleteMultiple(ctx context.Context, keys []string) error {
-	if len(keys) == 0 {
-		return errors.New("s3: keys cannot be empty")
+func (c *Client) DeleteMultiple(ctx context.Context, keys []string) (err error) {
+	keys, err = validateKeys(keys)
+	if err != nil {
+		return err
 	}
 
-	var objectIDs []types.ObjectIdentifier
+	objectIDs := make([]types.ObjectIdentifier, 0, len(keys))
 	for _, key := range keys {
-		if key != "" {
-			objectIDs = append(objectIDs, types.ObjectIdentifier{Key: aws.String(key)})
-		}
+		objectIDs = append(objectIDs, types.ObjectIdentifier{Key: aws.String(key)})
 	}
 
-	if len(objectIDs) == 0 {
-		return errors.New("s3: no valid keys provided")
-	}
+	ctx, op := c.startOperation(ctx, "DeleteMultiple", "")
+	defer func() { op.end(err, 0) }()
 
-	_, err := c.s3Client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+	_, err = c.s3Client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
 		Bucket: aws.String(c.bucketName),
 		Delete: &types.Delete{
 			Objects: objectIDs,
@@ -253,63 +334,54 @@ func (c *Client) DeleteMultiple(ctx context.Context, keys []string) error {
 
 // List возвращает список файлов в S3 бакете с заданным префиксом
 func (c *Client) List(ctx context.Context, prefix string) ([]string, error) {
-	input := &s3.ListObjectsV2Input{
-		Bucket: aws.String(c.bucketName),
-	}
-
-	if prefix != "" {
-		input.Prefix = aws.String(prefix)
-	}
-
-	var keys []string
-	paginator := s3.NewListObjectsV2Paginator(c.s3Client, input)
-
-	for paginator.HasMorePages() {
-		output, err := paginator.NextPage(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("%w: %w", ErrS3ListFailed, err)
-		}
-
-		for _, obj := range output.Contents {
-			if obj.Key != nil {
-				keys = append(keys, *obj.Key)
-			}
-		}
+	objects, err := collect(c.Objects(ctx, prefix))
+	if err != nil {
+		return nil, err
 	}
-
-	return keys, nil
+	return keysOf(objects), nil
 }
 
-// ListWithDetails возвращает список файлов с подробной информацией
+// ListWithDetails возвращает список файлов с подробной информацией.
+// Для больших бакетов используйте Objects, чтобы не держать весь список в памяти.
 func (c *Client) ListWithDetails(ctx context.Context, prefix string) ([]ObjectInfo, error) {
-	input := &s3.ListObjectsV2Input{
-		Bucket: aws.String(c.bucketName),
-	}
-
-	if prefix != "" {
-		input.Prefix = aws.String(prefix)
-	}
-
-	var objects []ObjectInfo
-	paginator := s3.NewListObjectsV2Paginator(c.s3Client, input)
+	return collect(c.Objects(ctx, prefix))
+}
 
-	for paginator.HasMorePages() {
-		output, err := paginator.NextPage(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("%w: %w", ErrS3ListFailed, err)
+// Objects постранично обходит объекты с префиксом prefix в лексикографическом порядке.
+// Следующая страница запрашивается только когда потребитель дочитал текущую;
+// после ошибки итерация завершается.
+func (c *Client) Objects(ctx context.Context, prefix string) iter.Seq2[ObjectInfo, error] {
+	return func(yield func(ObjectInfo, error) bool) {
+		input := &s3.ListObjectsV2Input{
+			Bucket: aws.String(c.bucketName),
+		}
+		if prefix != "" {
+			input.Prefix = aws.String(prefix)
 		}
 
-		for _, obj := range output.Contents {
-			objects = append(objects, ObjectInfo{
-				Key:          aws.ToString(obj.Key),
-				Size:         aws.ToInt64(obj.Size),
-				LastModified: aws.ToTime(obj.LastModified),
-				ETag:         aws.ToString(obj.ETag),
-			})
+		paginator := s3.NewListObjectsV2Paginator(c.s3Client, input)
+		for paginator.HasMorePages() {
+			pageCtx, op := c.startOperation(ctx, "ListObjects", prefix)
+			output, err := paginator.NextPage(pageCtx)
+			op.end(err, 0)
+			if err != nil {
+				yield(ObjectInfo{}, fmt.Errorf("%w: %w", ErrS3ListFailed, err))
+				return
+			}
+
+			for _, obj := range output.Contents {
+				info := ObjectInfo{
+					Key:          aws.ToString(obj.Key),
+					Size:         aws.ToInt64(obj.Size),
+					LastModified: aws.ToTime(obj.LastModified),
+					ETag:         aws.ToString(obj.ETag),
+				}
+				if !yield(info, nil) {
+					return
+				}
+			}
 		}
 	}
-
-	return objects, nil
 }
 
 // Exists проверяет существование файла в S3
@@ -318,14 +390,14 @@ func (c *Client) Exists(ctx context.Context, key string) (bool, error) {
 		return false, ErrS3EmptyKey
 	}
 
+	ctx, op := c.startOperation(ctx, "Exists", key)
 	_, err := c.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
 		Bucket: aws.String(c.bucketName),
 		Key:    aws.String(key),
 	})
+	op.end(err, 0)
 	if err != nil {
-		// Проверяем, является ли ошибка "NotFound" или "NoSuchKey"
-		errMsg := err.Error()
-		if strings.Contains(errMsg, "NotFound") || strings.Contains(errMsg, "NoSuchKey") {
+		if isNotFound(err) {
 			return false, nil
 		}
 		return false, fmt.Errorf("s3: failed to check object existence: %w", err)
@@ -347,14 +419,17 @@ func (c *Client) GetURL(key string) string {
 // key - ключ (путь) файла в S3
 // contentType - тип содержимого (например, "image/jpeg", "application/pdf")
 // expiresIn - время жизни ссылки (рекомендуется 15 минут для загрузки)
-func (c *Client) GeneratePresignedUploadURL(ctx context.Context, key string, contentType string, expiresIn time.Duration) (string, error) {
+func (c *Client) GeneratePresignedUploadURL(ctx context.Context, key string, contentType string, expiresIn time.Duration) (_ string, err error) {
 	if key == "" {
 		return "", ErrS3EmptyKey
 	}
 	if expiresIn <= 0 {
-		return "", errors.New("s3: expiration time must be positive")
+		return "", errInvalidExpiry
 	}
 
+	ctx, op := c.startOperation(ctx, "PresignUpload", key)
+	defer func() { op.end(err, 0) }()
+
 	input := &s3.PutObjectInput{
 		Bucket: aws.String(c.bucketName),
 		Key:    aws.String(key),
@@ -377,14 +452,17 @@ func (c *Client) GeneratePresignedUploadURL(ctx context.Context, key string, con
 // GeneratePresignedDownloadURL генерирует подписанный URL для скачивания файла
 // key - ключ (путь) файла в S3
 // expiresIn - время жизни ссылки (рекомендуется от 15 минут до 7 дней)
-func (c *Client) GeneratePresignedDownloadURL(ctx context.Context, key string, expiresIn time.Duration) (string, error) {
+func (c *Client) GeneratePresignedDownloadURL(ctx context.Context, key string, expiresIn time.Duration) (_ string, err error) {
 	if key == "" {
 		return "", ErrS3EmptyKey
 	}
 	if expiresIn <= 0 {
-		return "", errors.New("s3: expiration time must be positive")
+		return "", errInvalidExpiry
 	}
 
+	ctx, op := c.startOperation(ctx, "PresignDownload", key)
+	defer func() { op.end(err, 0) }()
+
 	input := &s3.GetObjectInput{
 		Bucket: aws.String(c.bucketName),
 		Key:    aws.String(key),
@@ -51,3 +51,18 @@ func TestGetPresignedGetURL(t *testing.T) {
 	require.NoError(t, err)
 	t.Log(url)
 }
+
+func TestNewClientRejectsInvalidTransferConfig(t *testing.T) {
+	base := Config{Region: "us-east-1", AccessKeyID: "key", SecretAccessKey: "secret", BucketName: "bucket"}
+
+	for name, mutate := range map[string]func(*Config){
+		"part size":   func(c *Config) { c.PartSize = 1024 },
+		"concurrency": func(c *Config) { c.Concurrency = -1 },
+		"checksum":    func(c *Config) { c.Checksum = "md5" },
+	} {
+		cfg := base
+		mutate(&cfg)
+		_, err := NewClient(context.Background(), cfg)
+		require.ErrorIs(t, err, ErrS3InvalidInput, name)
+	}
+}
@@ -0,0 +1,134 @@
+package s3
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+var (
+	errConfigRequired = errors.New("s3 config error")
+)
+
+const (
+	defaultPartSize     = 10 * 1024 * 1024
+	defaultConcurrency  = 5
+	defaultStaleTimeout = 5 * time.Minute
+	// minPartSize - минимальный размер части multipart загрузки в S3, кроме последней
+	minPartSize = 5 * 1024 * 1024
+
+	ChecksumCRC32C = "crc32c"
+	ChecksumSHA256 = "sha256"
+)
+
+// Config содержит параметры конфигурации для S3 клиента.
+// Ключи доступа задаются только через переменные окружения.
+type Config struct {
+	Region          string `yaml:"region" env:"S3_REGION"`
+	Endpoint        string `yaml:"endpoint" env:"S3_ENDPOINT"` // Опционально, для совместимых с S3 сервисов (MinIO, LocalStack, Railway)
+	AccessKeyID     string `yaml:"-" env:"S3_ACCESS_KEY_ID"`
+	SecretAccessKey string `yaml:"-" env:"S3_SECRET_ACCESS_KEY"`
+	BucketName      string `yaml:"bucket" env:"S3_BUCKET"`
+	// Buckets - дополнительные бакеты: логическое имя -> имя бакета. Доступны через Client.Bucket
+	Buckets  map[string]string `yaml:"buckets"`
+	Required bool              `yaml:"required"`
+
+	// PartSize - размер части multipart загрузки и скачивания в байтах, не меньше 5 МБ. По умолчанию 10 МБ.
+	PartSize int64 `yaml:"part_size"`
+	// Concurrency - число параллельно передаваемых частей, по умолчанию 5
+	Concurrency int `yaml:"concurrency"`
+	// Checksum - алгоритм контрольной суммы (crc32c, sha256). S3 проверяет ее при загрузке,
+	// SDK - при скачивании. Пустое значение - настройки SDK по умолчанию.
+	Checksum     string             `yaml:"checksum"`
+	StaleUploads StaleUploadsConfig `yaml:"stale_uploads"`
+}
+
+// StaleUploadsConfig настраивает cron джобу, прерывающую незавершенные multipart загрузки.
+// Пока загрузка не прервана, S3 хранит и тарифицирует ее части.
+type StaleUploadsConfig struct {
+	// Schedule - cron расписание. Пустое значение отключает джобу.
+	Schedule string `yaml:"schedule"`
+	// MaxAge - возраст, после которого незавершенная загрузка считается брошенной
+	MaxAge time.Duration `yaml:"max_age"`
+	// Timeout ограничивает один запуск джобы, по умолчанию 5 минут
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+func (c Config) Validate() error {
+	if !c.Required {
+		return nil
+	}
+	if c.Region == "" {
+		return fmt.Errorf("region is empty: %w", errConfigRequired)
+	}
+	if c.AccessKeyID == "" {
+		return fmt.Errorf("S3_ACCESS_KEY_ID is empty: %w", errConfigRequired)
+	}
+	if c.SecretAccessKey == "" {
+		return fmt.Errorf("S3_SECRET_ACCESS_KEY is empty: %w", errConfigRequired)
+	}
+	if c.BucketName == "" {
+		return fmt.Errorf("bucket is empty: %w", errConfigRequired)
+	}
+	for name, bucket := range c.Buckets {
+		if bucket == "" {
+			return fmt.Errorf("buckets.%s is empty: %w", name, errConfigRequired)
+		}
+	}
+	if err := c.validateTransfer(); err != nil {
+		return fmt.Errorf("%v: %w", err, errConfigRequired)
+	}
+	if c.StaleUploads.Schedule != "" && c.StaleUploads.MaxAge <= 0 {
+		return fmt.Errorf("stale_uploads.max_age is empty: %w", errConfigRequired)
+	}
+	return nil
+}
+
+// validateTransfer проверяет параметры передачи. Их проверяет и NewClient: некорректные значения
+// ломают загрузку (отрицательный Concurrency) или молча отключают проверку контрольной суммы.
+func (c Config) validateTransfer() error {
+	if c.PartSize != 0 && c.PartSize < minPartSize {
+		return fmt.Errorf("part_size must be at least %d bytes", minPartSize)
+	}
+	if c.Concurrency < 0 {
+		return errors.New("concurrency is negative")
+	}
+	switch strings.ToLower(c.Checksum) {
+	case "", ChecksumCRC32C, ChecksumSHA256:
+	default:
+		return fmt.Errorf("checksum %q is not supported", c.Checksum)
+	}
+	return nil
+}
+
+func (c Config) withDefaults() Config {
+	if c.PartSize == 0 {
+		c.PartSize = defaultPartSize
+	}
+	if c.Concurrency == 0 {
+		c.Concurrency = defaultConcurrency
+	}
+	return c
+}
+
+func (c StaleUploadsConfig) withDefaults() StaleUploadsConfig {
+	if c.Timeout == 0 {
+		c.Timeout = defaultStaleTimeout
+	}
+	return c
+}
+
+// checksumAlgorithm переводит Checksum в значение SDK
+func (c Config) checksumAlgorithm() types.ChecksumAlgorithm {
+	switch strings.ToLower(c.Checksum) {
+	case ChecksumCRC32C:
+		return types.ChecksumAlgorithmCrc32c
+	case ChecksumSHA256:
+		return types.ChecksumAlgorithmSha256
+	default:
+		return ""
+	}
+}
@@ -1,6 +1,12 @@
 package s3
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
 
 var (
 	// Configuration errors
@@ -9,19 +15,73 @@ var (
 	ErrS3EmptyAccessKey = errors.New("s3: empty access key")
 	ErrS3EmptySecretKey = errors.New("s3: empty secret key")
 	ErrS3ConfigFailed   = errors.New("s3: config failed")
+	ErrS3UnknownBucket  = errors.New("s3: unknown bucket")
+	ErrS3Unavailable    = errors.New("s3: unavailable")
 
 	// Operation errors
 	ErrS3EmptyKey      = errors.New("s3: empty key")
 	ErrS3EmptyData     = errors.New("s3: empty data")
 	ErrS3EmptyLocation = errors.New("s3: empty location")
+	ErrS3NotFound      = errors.New("s3: object not found")
 
 	// Upload/Download errors
 	ErrS3UploadFailed   = errors.New("s3: upload failed")
 	ErrS3DownloadFailed = errors.New("s3: download failed")
 	ErrS3DeleteFailed   = errors.New("s3: delete failed")
 	ErrS3ListFailed     = errors.New("s3: list failed")
+	ErrS3HeadFailed     = errors.New("s3: head failed")
+	ErrS3CopyFailed     = errors.New("s3: copy failed")
+	ErrS3TaggingFailed  = errors.New("s3: tagging failed")
+
+	// ErrS3VerificationFailed - загруженный объект не соответствует UploadExpectation
+	ErrS3VerificationFailed = errors.New("s3: upload verification failed")
 
 	// Input validation errors
 	ErrS3InvalidInput  = errors.New("s3: invalid input")
 	ErrS3InternalError = errors.New("s3: internal error")
+
+	errEmptyKeys     = fmt.Errorf("%w: keys cannot be empty", ErrS3InvalidInput)
+	errNoValidKeys   = fmt.Errorf("%w: no valid keys provided", ErrS3InvalidInput)
+	errNilWriter     = fmt.Errorf("%w: writer cannot be nil", ErrS3InvalidInput)
+	errInvalidExpiry = fmt.Errorf("%w: expiration time must be positive", ErrS3InvalidInput)
+	errInvalidRange  = fmt.Errorf("%w: range not satisfiable", ErrS3InvalidInput)
+	errReaderClosed  = errors.New("s3: reader is closed")
 )
+
+// wrapError оборачивает ошибку операции в base, добавляя ErrS3NotFound для отсутствующего объекта
+func wrapError(base, err error) error {
+	if isNotFound(err) {
+		return fmt.Errorf("%w: %w: %w", base, ErrS3NotFound, err)
+	}
+	return fmt.Errorf("%w: %w", base, err)
+}
+
+// downloadError оборачивает ошибку скачивания, добавляя ErrS3NotFound для отсутствующего объекта
+func downloadError(err error) error {
+	return wrapError(ErrS3DownloadFailed, err)
+}
+
+// isNotFound сообщает, что объект или бакет не существует
+func isNotFound(err error) bool {
+	var (
+		notFound     *types.NotFound
+		noSuchKey    *types.NoSuchKey
+		noSuchBucket *types.NoSuchBucket
+	)
+	if errors.As(err, &notFound) || errors.As(err, &noSuchKey) || errors.As(err, &noSuchBucket) {
+		return true
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "NotFound", "NoSuchKey", "NoSuchBucket":
+			return true
+		}
+	}
+	return false
+}
+
+func isInvalidRange(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == "InvalidRange"
+}
@@ -0,0 +1,749 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5" //nolint:gosec // ETag в S3 - MD5 содержимого
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"iter"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	localTmpDir  = ".tmp"
+	localMetaDir = ".meta"
+
+	localExpiresParam     = "X-Expires"
+	localSignatureParam   = "X-Signature"
+	localContentTypeParam = "X-Content-Type"
+)
+
+// LocalConfig настраивает LocalStorage
+type LocalConfig struct {
+	// Root - каталог, в котором хранятся объекты. Ключ "a/b.txt" соответствует файлу Root/a/b.txt
+	Root string
+	// BaseURL - адрес, по которому смонтирован LocalStorage.Handler, например http://localhost:8080/files.
+	// Используется в возвращаемых URL и обязателен для presigned URL.
+	BaseURL string
+	// SigningKey - ключ подписи presigned URL. Если пустой, генерируется при создании хранилища.
+	SigningKey []byte
+}
+
+// LocalStorage хранит объекты в файловой системе. Предназначен для локальной разработки:
+// presigned URL обслуживаются LocalStorage.Handler. Заголовки, метаданные и теги объекта
+// хранятся рядом в Root/.meta/<key>.json.
+type LocalStorage struct {
+	root       string
+	baseURL    string
+	signingKey []byte
+}
+
+// NewLocalStorage создает каталог Root, если его нет
+func NewLocalStorage(cfg LocalConfig) (*LocalStorage, error) {
+	if cfg.Root == "" {
+		return nil, fmt.Errorf("%w: empty root", ErrS3InvalidInput)
+	}
+	root, err := filepath.Abs(cfg.Root)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrS3ConfigFailed, err)
+	}
+	for _, dir := range []string{localTmpDir, localMetaDir} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrS3ConfigFailed, err)
+		}
+	}
+
+	key := cfg.SigningKey
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrS3ConfigFailed, err)
+		}
+	}
+
+	return &LocalStorage{
+		root:       root,
+		baseURL:    strings.TrimSuffix(cfg.BaseURL, "/"),
+		signingKey: key,
+	}, nil
+}
+
+// Upload записывает data в файл
+func (l *LocalStorage) Upload(ctx context.Context, key string, data []byte, contentType string, opts ...UploadOption) (string, error) {
+	if key == "" {
+		return "", ErrS3EmptyKey
+	}
+	if len(data) == 0 {
+		return "", ErrS3EmptyData
+	}
+	return l.UploadStream(ctx, key, bytes.NewReader(data), contentType, opts...)
+}
+
+// UploadStream записывает reader во временный файл и атомарно переименовывает его
+func (l *LocalStorage) UploadStream(ctx context.Context, key string, reader io.Reader, contentType string, opts ...UploadOption) (string, error) {
+	if key == "" {
+		return "", ErrS3EmptyKey
+	}
+	if reader == nil {
+		return "", ErrS3EmptyData
+	}
+	path, err := l.path(key)
+	if err != nil {
+		return "", err
+	}
+
+	o := newUploadOptions(opts)
+	meta := localMeta{
+		ContentType:        contentType,
+		CacheControl:       o.CacheControl,
+		ContentDisposition: o.ContentDisposition,
+		Metadata:           o.Metadata,
+		Tags:               o.Tags,
+	}
+	if err := l.write(ctx, key, path, reader, meta); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrS3UploadFailed, err)
+	}
+	return l.url(key), nil
+}
+
+// Download читает файл целиком
+func (l *LocalStorage) Download(ctx context.Context, key string) ([]byte, error) {
+	if key == "" {
+		return nil, ErrS3EmptyKey
+	}
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrS3DownloadFailed, err)
+	}
+
+	f, err := openObject(key, path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrS3DownloadFailed, err)
+	}
+	return data, nil
+}
+
+// DownloadStream копирует файл в writer с нулевого смещения
+func (l *LocalStorage) DownloadStream(ctx context.Context, key string, writer io.WriterAt) (int64, error) {
+	if key == "" {
+		return 0, ErrS3EmptyKey
+	}
+	if writer == nil {
+		return 0, errNilWriter
+	}
+	path, err := l.path(key)
+	if err != nil {
+		return 0, err
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrS3DownloadFailed, err)
+	}
+
+	f, err := openObject(key, path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	n, err := io.Copy(io.NewOffsetWriter(writer, 0), f)
+	if err != nil {
+		return n, fmt.Errorf("%w: %w", ErrS3DownloadFailed, err)
+	}
+	return n, nil
+}
+
+// DownloadRange читает length байт файла начиная с offset. length <= 0 - до конца файла.
+func (l *LocalStorage) DownloadRange(ctx context.Context, key string, offset, length int64) ([]byte, error) {
+	f, err := l.open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrS3DownloadFailed, err)
+	}
+	start, end, err := rangeBounds(info.Size(), offset, length)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, end-start)
+	if _, err := f.ReadAt(data, start); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrS3DownloadFailed, err)
+	}
+	return data, nil
+}
+
+// OpenReader открывает файл объекта
+func (l *LocalStorage) OpenReader(ctx context.Context, key string) (io.ReadSeekCloser, error) {
+	f, err := l.open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (l *LocalStorage) open(ctx context.Context, key string) (*os.File, error) {
+	if key == "" {
+		return nil, ErrS3EmptyKey
+	}
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrS3DownloadFailed, err)
+	}
+	return openObject(key, path)
+}
+
+// Head возвращает информацию о файле и его атрибуты
+func (l *LocalStorage) Head(_ context.Context, key string) (ObjectInfo, error) {
+	if key == "" {
+		return ObjectInfo{}, ErrS3EmptyKey
+	}
+	path, err := l.path(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		if err == nil || errors.Is(err, fs.ErrNotExist) {
+			return ObjectInfo{}, fmt.Errorf("%w: %w: %s", ErrS3HeadFailed, ErrS3NotFound, key)
+		}
+		return ObjectInfo{}, fmt.Errorf("%w: %w", ErrS3HeadFailed, err)
+	}
+	etag, err := fileETag(path)
+	if err != nil {
+		return ObjectInfo{}, fmt.Errorf("%w: %w", ErrS3HeadFailed, err)
+	}
+	meta, err := l.readMeta(key)
+	if err != nil {
+		return ObjectInfo{}, fmt.Errorf("%w: %w", ErrS3HeadFailed, err)
+	}
+
+	return ObjectInfo{
+		Key:                key,
+		Size:               info.Size(),
+		LastModified:       info.ModTime().UTC(),
+		ETag:               etag,
+		ContentType:        meta.ContentType,
+		CacheControl:       meta.CacheControl,
+		ContentDisposition: meta.ContentDisposition,
+		Metadata:           meta.Metadata,
+	}, nil
+}
+
+// Copy копирует файл вместе с атрибутами
+func (l *LocalStorage) Copy(ctx context.Context, srcKey, dstKey string) error {
+	if srcKey == "" || dstKey == "" {
+		return ErrS3EmptyKey
+	}
+	srcPath, err := l.path(srcKey)
+	if err != nil {
+		return err
+	}
+	dstPath, err := l.path(dstKey)
+	if err != nil {
+		return err
+	}
+	if srcKey == dstKey {
+		_, err := l.Head(ctx, srcKey)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrS3CopyFailed, err)
+		}
+		return nil
+	}
+
+	src, err := openObject(srcKey, srcPath)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrS3CopyFailed, err)
+	}
+	defer src.Close()
+	meta, err := l.readMeta(srcKey)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrS3CopyFailed, err)
+	}
+	if err := l.write(ctx, dstKey, dstPath, src, meta); err != nil {
+		return fmt.Errorf("%w: %w", ErrS3CopyFailed, err)
+	}
+	return nil
+}
+
+// Move переименовывает файл вместе с атрибутами
+func (l *LocalStorage) Move(ctx context.Context, srcKey, dstKey string) error {
+	if err := l.Copy(ctx, srcKey, dstKey); err != nil {
+		return err
+	}
+	if srcKey == dstKey {
+		return nil
+	}
+	return l.Delete(ctx, srcKey)
+}
+
+// GetTags возвращает теги объекта
+func (l *LocalStorage) GetTags(ctx context.Context, key string) (map[string]string, error) {
+	meta, err := l.existingMeta(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if meta.Tags == nil {
+		return map[string]string{}, nil
+	}
+	return meta.Tags, nil
+}
+
+// SetTags заменяет все теги объекта на tags
+func (l *LocalStorage) SetTags(ctx context.Context, key string, tags map[string]string) error {
+	meta, err := l.existingMeta(ctx, key)
+	if err != nil {
+		return err
+	}
+	meta.Tags = maps.Clone(tags)
+	if err := l.writeMeta(key, meta); err != nil {
+		return fmt.Errorf("%w: %w", ErrS3TaggingFailed, err)
+	}
+	return nil
+}
+
+// existingMeta читает атрибуты существующего объекта для операций с тегами
+func (l *LocalStorage) existingMeta(ctx context.Context, key string) (localMeta, error) {
+	if key == "" {
+		return localMeta{}, ErrS3EmptyKey
+	}
+	ok, err := l.Exists(ctx, key)
+	if err != nil {
+		return localMeta{}, fmt.Errorf("%w: %w", ErrS3TaggingFailed, err)
+	}
+	if !ok {
+		return localMeta{}, fmt.Errorf("%w: %w: %s", ErrS3TaggingFailed, ErrS3NotFound, key)
+	}
+	meta, err := l.readMeta(key)
+	if err != nil {
+		return localMeta{}, fmt.Errorf("%w: %w", ErrS3TaggingFailed, err)
+	}
+	return meta, nil
+}
+
+// Delete удаляет файл. Отсутствующий файл не считается ошибкой.
+func (l *LocalStorage) Delete(_ context.Context, key string) error {
+	if key == "" {
+		return ErrS3EmptyKey
+	}
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	// Каталог - не объект: os.Remove удалил бы пустой каталог, а S3 в этом случае ничего не делает
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %w", ErrS3DeleteFailed, err)
+	}
+	if err := os.Remove(l.metaPath(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %w", ErrS3DeleteFailed, err)
+	}
+	return nil
+}
+
+// DeleteMultiple удаляет несколько файлов, пустые ключи пропускаются
+func (l *LocalStorage) DeleteMultiple(ctx context.Context, keys []string) error {
+	keys, err := validateKeys(keys)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := l.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List возвращает ключи с префиксом prefix в лексикографическом порядке
+func (l *LocalStorage) List(ctx context.Context, prefix string) ([]string, error) {
+	objects, err := collect(l.Objects(ctx, prefix))
+	if err != nil {
+		return nil, err
+	}
+	return keysOf(objects), nil
+}
+
+// ListWithDetails возвращает файлы с префиксом prefix в лексикографическом порядке
+func (l *LocalStorage) ListWithDetails(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	return collect(l.Objects(ctx, prefix))
+}
+
+// Objects обходит Root и возвращает файлы с префиксом prefix в лексикографическом порядке.
+// Порядок filepath.WalkDir отличается от S3 ("a/b" идет раньше "a.txt"), поэтому ключи
+// сначала собираются и сортируются, а ETag считается по мере итерации.
+func (l *LocalStorage) Objects(ctx context.Context, prefix string) iter.Seq2[ObjectInfo, error] {
+	return func(yield func(ObjectInfo, error) bool) {
+		objects, err := l.walk(ctx, prefix)
+		if err != nil {
+			yield(ObjectInfo{}, fmt.Errorf("%w: %w", ErrS3ListFailed, err))
+			return
+		}
+		for _, obj := range objects {
+			etag, err := fileETag(filepath.Join(l.root, filepath.FromSlash(obj.Key)))
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					// Удален во время обхода
+					continue
+				}
+				yield(ObjectInfo{}, fmt.Errorf("%w: %w", ErrS3ListFailed, err))
+				return
+			}
+			obj.ETag = etag
+			if !yield(obj, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (l *LocalStorage) walk(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	err := filepath.WalkDir(l.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path == filepath.Join(l.root, localTmpDir) || path == filepath.Join(l.root, localMetaDir) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(l.root, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, ObjectInfo{
+			Key:          key,
+			Size:         info.Size(),
+			LastModified: info.ModTime().UTC(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(objects, func(a, b ObjectInfo) int { return strings.Compare(a.Key, b.Key) })
+	return objects, nil
+}
+
+// Exists проверяет наличие файла
+func (l *LocalStorage) Exists(_ context.Context, key string) (bool, error) {
+	if key == "" {
+		return false, ErrS3EmptyKey
+	}
+	path, err := l.path(key)
+	if err != nil {
+		return false, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("s3: failed to check object existence: %w", err)
+	}
+	return !info.IsDir(), nil
+}
+
+// GeneratePresignedUploadURL возвращает подписанный URL для PUT запроса к LocalStorage.Handler
+func (l *LocalStorage) GeneratePresignedUploadURL(_ context.Context, key string, contentType string, expiresIn time.Duration) (string, error) {
+	return l.presign(http.MethodPut, key, contentType, expiresIn)
+}
+
+// GeneratePresignedDownloadURL возвращает подписанный URL для GET запроса к LocalStorage.Handler
+func (l *LocalStorage) GeneratePresignedDownloadURL(_ context.Context, key string, expiresIn time.Duration) (string, error) {
+	return l.presign(http.MethodGet, key, "", expiresIn)
+}
+
+// Handler обслуживает presigned URL: GET отдает файл, PUT загружает тело запроса,
+// POST принимает форму из GeneratePresignedPost и проверяет ее policy.
+// Путь запроса без ведущего "/" считается ключом, поэтому при монтировании под префиксом
+// его нужно снять, например http.StripPrefix("/files", storage.Handler()).
+func (l *LocalStorage) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			l.servePost(w, r)
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodPut {
+			w.Header().Set("Allow", "GET, PUT, POST")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		key := strings.TrimPrefix(r.URL.Path, "/")
+		q := r.URL.Query()
+		contentType := q.Get(localContentTypeParam)
+		if !l.verify(r.Method, key, contentType, q.Get(localExpiresParam), q.Get(localSignatureParam)) {
+			http.Error(w, "invalid or expired signature", http.StatusForbidden)
+			return
+		}
+		path, err := l.path(key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if r.Method == http.MethodPut {
+			if contentType != "" && r.Header.Get("Content-Type") != contentType {
+				http.Error(w, "content type does not match signature", http.StatusForbidden)
+				return
+			}
+			meta := localMeta{ContentType: r.Header.Get("Content-Type")}
+			if err := l.write(r.Context(), key, path, r.Body, meta); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				http.NotFound(w, r)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil || info.IsDir() {
+			http.NotFound(w, r)
+			return
+		}
+		meta, err := l.readMeta(key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		h := w.Header()
+		if meta.ContentType != "" {
+			h.Set("Content-Type", meta.ContentType)
+		}
+		if meta.CacheControl != "" {
+			h.Set("Cache-Control", meta.CacheControl)
+		}
+		if meta.ContentDisposition != "" {
+			h.Set("Content-Disposition", meta.ContentDisposition)
+		}
+		http.ServeContent(w, r, info.Name(), info.ModTime(), f)
+	})
+}
+
+// path переводит ключ в путь внутри Root, запрещая выход за его пределы
+func (l *LocalStorage) path(key string) (string, error) {
+	rel := filepath.FromSlash(key)
+	if !filepath.IsLocal(rel) || strings.HasSuffix(key, "/") {
+		return "", fmt.Errorf("%w: key %q", ErrS3InvalidInput, key)
+	}
+	if first, _, _ := strings.Cut(key, "/"); first == localTmpDir || first == localMetaDir {
+		return "", fmt.Errorf("%w: key %q is reserved", ErrS3InvalidInput, key)
+	}
+	return filepath.Join(l.root, rel), nil
+}
+
+// write записывает файл через временный файл, чтобы читатели не видели частично записанный объект
+func (l *LocalStorage) write(ctx context.Context, key, path string, reader io.Reader, meta localMeta) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Join(l.root, localTmpDir), "upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, reader); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := l.writeMeta(key, meta); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// localMeta - атрибуты объекта, которые S3 хранит вместе с ним
+type localMeta struct {
+	ContentType        string            `json:"content_type,omitempty"`
+	CacheControl       string            `json:"cache_control,omitempty"`
+	ContentDisposition string            `json:"content_disposition,omitempty"`
+	Metadata           map[string]string `json:"metadata,omitempty"`
+	Tags               map[string]string `json:"tags,omitempty"`
+}
+
+func (l *LocalStorage) metaPath(key string) string {
+	return filepath.Join(l.root, localMetaDir, filepath.FromSlash(key)+".json")
+}
+
+// readMeta читает атрибуты объекта. Файл, положенный в Root вручную, атрибутов не имеет.
+func (l *LocalStorage) readMeta(key string) (localMeta, error) {
+	var meta localMeta
+	data, err := os.ReadFile(l.metaPath(key))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return meta, nil
+		}
+		return meta, err
+	}
+	return meta, json.Unmarshal(data, &meta)
+}
+
+func (l *LocalStorage) writeMeta(key string, meta localMeta) error {
+	path := l.metaPath(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Join(l.root, localTmpDir), "meta-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (l *LocalStorage) url(key string) string {
+	if l.baseURL == "" {
+		return (&url.URL{Scheme: "file", Path: filepath.ToSlash(filepath.Join(l.root, filepath.FromSlash(key)))}).String()
+	}
+	return l.baseURL + "/" + (&url.URL{Path: key}).EscapedPath()
+}
+
+func (l *LocalStorage) presign(method, key, contentType string, expiresIn time.Duration) (string, error) {
+	if key == "" {
+		return "", ErrS3EmptyKey
+	}
+	if expiresIn <= 0 {
+		return "", errInvalidExpiry
+	}
+	if _, err := l.path(key); err != nil {
+		return "", err
+	}
+	if l.baseURL == "" {
+		return "", fmt.Errorf("%w: local storage base url is not configured", ErrS3InvalidInput)
+	}
+
+	expires := strconv.FormatInt(time.Now().Add(expiresIn).Unix(), 10)
+	q := url.Values{
+		localExpiresParam:   {expires},
+		localSignatureParam: {l.sign(method, key, contentType, expires)},
+	}
+	if contentType != "" {
+		q.Set(localContentTypeParam, contentType)
+	}
+	return l.url(key) + "?" + q.Encode(), nil
+}
+
+func (l *LocalStorage) sign(method, key, contentType, expires string) string {
+	mac := hmac.New(sha256.New, l.signingKey)
+	mac.Write([]byte(method + "\n" + key + "\n" + contentType + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (l *LocalStorage) verify(method, key, contentType, expires, signature string) bool {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(l.sign(method, key, contentType, expires)))
+}
+
+// openObject открывает файл объекта. Отсутствующий файл и каталог дают ErrS3NotFound.
+func openObject(key, path string) (*os.File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %w: %s", ErrS3DownloadFailed, ErrS3NotFound, key)
+		}
+		return nil, fmt.Errorf("%w: %w", ErrS3DownloadFailed, err)
+	}
+	info, err := f.Stat()
+	if err == nil && info.IsDir() {
+		_ = f.Close()
+		return nil, fmt.Errorf("%w: %w: %s", ErrS3DownloadFailed, ErrS3NotFound, key)
+	}
+	return f, nil
+}
+
+func fileETag(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := md5.New() //nolint:gosec
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)) + `"`, nil
+}
@@ -0,0 +1,216 @@
+package s3
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const localPolicyField = "policy"
+
+var errPolicyViolation = errors.New("s3: post policy violation")
+
+// localPolicy - policy документ в формате S3: expiration и список conditions
+type localPolicy struct {
+	Expiration time.Time `json:"expiration"`
+	Conditions []any     `json:"conditions"`
+}
+
+// GeneratePresignedPost подписывает форму для POST запроса к LocalStorage.Handler.
+// Handler проверяет условия так же, как S3: ключ, Content-Type и размер файла.
+func (l *LocalStorage) GeneratePresignedPost(_ context.Context, policy PostPolicy) (PresignedPost, error) {
+	if err := policy.validate(); err != nil {
+		return PresignedPost{}, err
+	}
+	policy = policy.withDefaults()
+	if l.baseURL == "" {
+		return PresignedPost{}, fmt.Errorf("%w: local storage base url is not configured", ErrS3InvalidInput)
+	}
+
+	expires := time.Now().Add(policy.Expires).UTC().Truncate(time.Second)
+	conditions := policy.conditions()
+	if policy.Key != "" {
+		conditions = append(conditions, map[string]string{"key": policy.Key})
+	}
+	doc, err := json.Marshal(localPolicy{Expiration: expires, Conditions: conditions})
+	if err != nil {
+		return PresignedPost{}, fmt.Errorf("s3: failed to generate presigned post: %w", err)
+	}
+	encoded := base64.StdEncoding.EncodeToString(doc)
+
+	fields := map[string]string{
+		"key":               policy.formKey(),
+		localPolicyField:    encoded,
+		localSignatureParam: l.signPolicy(encoded),
+	}
+	if policy.ContentType != "" {
+		fields["Content-Type"] = policy.ContentType
+	}
+	return PresignedPost{URL: l.baseURL + "/", Fields: fields, Expires: expires}, nil
+}
+
+// servePost принимает multipart форму: поля до файла, затем поле "file"
+func (l *LocalStorage) servePost(w http.ResponseWriter, r *http.Request) {
+	mr, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	fields := make(map[string]string)
+	for {
+		part, err := mr.NextPart()
+		if err != nil {
+			http.Error(w, "file field is missing", http.StatusBadRequest)
+			return
+		}
+		name := part.FormName()
+		if name != "file" {
+			value, err := io.ReadAll(io.LimitReader(part, 1<<20))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			fields[strings.ToLower(name)] = string(value)
+			continue
+		}
+
+		policy, err := l.checkPolicy(fields, part.FileName())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		key := fields["key"]
+		path, err := l.path(key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		body := io.Reader(part)
+		if minSize, maxSize, ok := lengthRange(policy.Conditions); ok {
+			body = &rangeReader{r: part, min: minSize, max: maxSize}
+		}
+		meta := localMeta{ContentType: fields["content-type"]}
+		if err := l.write(r.Context(), key, path, body, meta); err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, errPolicyViolation) {
+				status = http.StatusBadRequest
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+}
+
+// checkPolicy проверяет подпись, срок действия и условия на поля формы
+func (l *LocalStorage) checkPolicy(fields map[string]string, filename string) (localPolicy, error) {
+	var policy localPolicy
+	encoded := fields[localPolicyField]
+	if !hmac.Equal([]byte(fields[strings.ToLower(localSignatureParam)]), []byte(l.signPolicy(encoded))) {
+		return policy, fmt.Errorf("%w: invalid signature", errPolicyViolation)
+	}
+	doc, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return policy, fmt.Errorf("%w: %w", errPolicyViolation, err)
+	}
+	if err := json.Unmarshal(doc, &policy); err != nil {
+		return policy, fmt.Errorf("%w: %w", errPolicyViolation, err)
+	}
+	if time.Now().After(policy.Expiration) {
+		return policy, fmt.Errorf("%w: policy expired", errPolicyViolation)
+	}
+
+	fields["key"] = resolveFormKey(fields["key"], filename)
+	for _, cond := range policy.Conditions {
+		if err := checkCondition(cond, fields); err != nil {
+			return policy, err
+		}
+	}
+	return policy, nil
+}
+
+func checkCondition(cond any, fields map[string]string) error {
+	switch c := cond.(type) {
+	case map[string]any:
+		for name, want := range c {
+			if fields[strings.ToLower(name)] != fmt.Sprint(want) {
+				return fmt.Errorf("%w: %s must be %v", errPolicyViolation, name, want)
+			}
+		}
+	case []any:
+		if len(c) != 3 {
+			return fmt.Errorf("%w: malformed condition", errPolicyViolation)
+		}
+		op, _ := c[0].(string)
+		if op == "content-length-range" {
+			// Проверяется при чтении файла
+			return nil
+		}
+		name, _ := c[1].(string)
+		name = strings.ToLower(strings.TrimPrefix(name, "$"))
+		want := fmt.Sprint(c[2])
+		switch op {
+		case "eq":
+			if fields[name] != want {
+				return fmt.Errorf("%w: %s must be %s", errPolicyViolation, name, want)
+			}
+		case "starts-with":
+			if !strings.HasPrefix(fields[name], want) {
+				return fmt.Errorf("%w: %s must start with %s", errPolicyViolation, name, want)
+			}
+		default:
+			return fmt.Errorf("%w: unknown condition %q", errPolicyViolation, op)
+		}
+	}
+	return nil
+}
+
+func lengthRange(conditions []any) (int64, int64, bool) {
+	for _, cond := range conditions {
+		c, ok := cond.([]any)
+		if !ok || len(c) != 3 || c[0] != "content-length-range" {
+			continue
+		}
+		minSize, _ := c[1].(float64)
+		maxSize, _ := c[2].(float64)
+		return int64(minSize), int64(maxSize), true
+	}
+	return 0, 0, false
+}
+
+func (l *LocalStorage) signPolicy(policy string) string {
+	mac := hmac.New(sha256.New, l.signingKey)
+	mac.Write([]byte(policy))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// rangeReader обрывает чтение с ошибкой, если размер выходит за content-length-range
+type rangeReader struct {
+	r        io.Reader
+	n        int64
+	min, max int64
+}
+
+func (r *rangeReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	if r.n > r.max {
+		return n, fmt.Errorf("%w: file is larger than %d bytes", errPolicyViolation, r.max)
+	}
+	if errors.Is(err, io.EOF) && r.n < r.min {
+		return n, fmt.Errorf("%w: file is smaller than %d bytes", errPolicyViolation, r.min)
+	}
+	return n, err
+}
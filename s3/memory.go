@@ -0,0 +1,410 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5" //nolint:gosec // ETag в S3 - MD5 содержимого
+	"encoding/hex"
+	"fmt"
+	"io"
+	"iter"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const memoryScheme = "memory"
+
+type memoryObject struct {
+	data []byte
+	info ObjectInfo
+	tags map[string]string
+}
+
+// MemoryStorage хранит объекты в памяти процесса. Предназначен для тестов.
+type MemoryStorage struct {
+	mu      sync.RWMutex
+	bucket  string
+	objects map[string]memoryObject
+}
+
+// NewMemoryStorage создает пустое хранилище. bucket используется только в возвращаемых URL.
+func NewMemoryStorage(bucket string) *MemoryStorage {
+	return &MemoryStorage{
+		bucket:  bucket,
+		objects: make(map[string]memoryObject),
+	}
+}
+
+// Upload сохраняет копию data
+func (m *MemoryStorage) Upload(ctx context.Context, key string, data []byte, contentType string, opts ...UploadOption) (string, error) {
+	if key == "" {
+		return "", ErrS3EmptyKey
+	}
+	if len(data) == 0 {
+		return "", ErrS3EmptyData
+	}
+	if err := ctx.Err(); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrS3UploadFailed, err)
+	}
+
+	m.put(key, bytes.Clone(data), contentType, newUploadOptions(opts))
+	return m.url(key), nil
+}
+
+// UploadStream читает reader целиком и сохраняет содержимое
+func (m *MemoryStorage) UploadStream(ctx context.Context, key string, reader io.Reader, contentType string, opts ...UploadOption) (string, error) {
+	if key == "" {
+		return "", ErrS3EmptyKey
+	}
+	if reader == nil {
+		return "", ErrS3EmptyData
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrS3UploadFailed, err)
+	}
+	if err := ctx.Err(); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrS3UploadFailed, err)
+	}
+
+	m.put(key, data, contentType, newUploadOptions(opts))
+	return m.url(key), nil
+}
+
+// Download возвращает копию содержимого объекта
+func (m *MemoryStorage) Download(ctx context.Context, key string) ([]byte, error) {
+	if key == "" {
+		return nil, ErrS3EmptyKey
+	}
+
+	obj, err := m.get(ctx, key, ErrS3DownloadFailed)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.Clone(obj.data), nil
+}
+
+// DownloadStream записывает содержимое объекта в writer с нулевого смещения
+func (m *MemoryStorage) DownloadStream(ctx context.Context, key string, writer io.WriterAt) (int64, error) {
+	if key == "" {
+		return 0, ErrS3EmptyKey
+	}
+	if writer == nil {
+		return 0, errNilWriter
+	}
+
+	obj, err := m.get(ctx, key, ErrS3DownloadFailed)
+	if err != nil {
+		return 0, err
+	}
+	n, err := writer.WriteAt(obj.data, 0)
+	if err != nil {
+		return int64(n), fmt.Errorf("%w: %w", ErrS3DownloadFailed, err)
+	}
+	return int64(n), nil
+}
+
+// DownloadRange возвращает length байт объекта начиная с offset. length <= 0 - до конца объекта.
+func (m *MemoryStorage) DownloadRange(ctx context.Context, key string, offset, length int64) ([]byte, error) {
+	if key == "" {
+		return nil, ErrS3EmptyKey
+	}
+
+	obj, err := m.get(ctx, key, ErrS3DownloadFailed)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := rangeBounds(int64(len(obj.data)), offset, length)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.Clone(obj.data[start:end]), nil
+}
+
+// OpenReader возвращает reader по снимку содержимого объекта
+func (m *MemoryStorage) OpenReader(ctx context.Context, key string) (io.ReadSeekCloser, error) {
+	if key == "" {
+		return nil, ErrS3EmptyKey
+	}
+
+	obj, err := m.get(ctx, key, ErrS3DownloadFailed)
+	if err != nil {
+		return nil, err
+	}
+	return nopSeekCloser{bytes.NewReader(obj.data)}, nil
+}
+
+// Head возвращает информацию об объекте
+func (m *MemoryStorage) Head(ctx context.Context, key string) (ObjectInfo, error) {
+	if key == "" {
+		return ObjectInfo{}, ErrS3EmptyKey
+	}
+
+	obj, err := m.get(ctx, key, ErrS3HeadFailed)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	info := obj.info
+	info.Metadata = maps.Clone(info.Metadata)
+	return info, nil
+}
+
+// Copy копирует объект вместе с метаданными и тегами
+func (m *MemoryStorage) Copy(ctx context.Context, srcKey, dstKey string) error {
+	if srcKey == "" || dstKey == "" {
+		return ErrS3EmptyKey
+	}
+
+	obj, err := m.get(ctx, srcKey, ErrS3CopyFailed)
+	if err != nil {
+		return err
+	}
+	obj.info.Key = dstKey
+	obj.info.LastModified = time.Now().UTC()
+
+	m.mu.Lock()
+	m.objects[dstKey] = obj
+	m.mu.Unlock()
+	return nil
+}
+
+// Move копирует объект и удаляет исходный
+func (m *MemoryStorage) Move(ctx context.Context, srcKey, dstKey string) error {
+	if err := m.Copy(ctx, srcKey, dstKey); err != nil {
+		return err
+	}
+	if srcKey == dstKey {
+		return nil
+	}
+	return m.Delete(ctx, srcKey)
+}
+
+// GetTags возвращает теги объекта
+func (m *MemoryStorage) GetTags(ctx context.Context, key string) (map[string]string, error) {
+	if key == "" {
+		return nil, ErrS3EmptyKey
+	}
+
+	obj, err := m.get(ctx, key, ErrS3TaggingFailed)
+	if err != nil {
+		return nil, err
+	}
+	tags := maps.Clone(obj.tags)
+	if tags == nil {
+		tags = map[string]string{}
+	}
+	return tags, nil
+}
+
+// SetTags заменяет все теги объекта на tags
+func (m *MemoryStorage) SetTags(_ context.Context, key string, tags map[string]string) error {
+	if key == "" {
+		return ErrS3EmptyKey
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	obj, ok := m.objects[key]
+	if !ok {
+		return fmt.Errorf("%w: %w: %s", ErrS3TaggingFailed, ErrS3NotFound, key)
+	}
+	obj.tags = maps.Clone(tags)
+	m.objects[key] = obj
+	return nil
+}
+
+// Delete удаляет объект. Отсутствующий объект не считается ошибкой.
+func (m *MemoryStorage) Delete(_ context.Context, key string) error {
+	if key == "" {
+		return ErrS3EmptyKey
+	}
+
+	m.mu.Lock()
+	delete(m.objects, key)
+	m.mu.Unlock()
+	return nil
+}
+
+// DeleteMultiple удаляет несколько объектов, пустые ключи пропускаются
+func (m *MemoryStorage) DeleteMultiple(_ context.Context, keys []string) error {
+	keys, err := validateKeys(keys)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	for _, key := range keys {
+		delete(m.objects, key)
+	}
+	m.mu.Unlock()
+	return nil
+}
+
+// List возвращает ключи с префиксом prefix в лексикографическом порядке
+func (m *MemoryStorage) List(ctx context.Context, prefix string) ([]string, error) {
+	objects, err := collect(m.Objects(ctx, prefix))
+	if err != nil {
+		return nil, err
+	}
+	return keysOf(objects), nil
+}
+
+// ListWithDetails возвращает объекты с префиксом prefix в лексикографическом порядке
+func (m *MemoryStorage) ListWithDetails(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	return collect(m.Objects(ctx, prefix))
+}
+
+// Objects обходит снимок объектов с префиксом prefix в лексикографическом порядке
+func (m *MemoryStorage) Objects(ctx context.Context, prefix string) iter.Seq2[ObjectInfo, error] {
+	return func(yield func(ObjectInfo, error) bool) {
+		if err := ctx.Err(); err != nil {
+			yield(ObjectInfo{}, fmt.Errorf("%w: %w", ErrS3ListFailed, err))
+			return
+		}
+
+		m.mu.RLock()
+		var objects []ObjectInfo
+		for key, obj := range m.objects {
+			if strings.HasPrefix(key, prefix) {
+				objects = append(objects, listInfo(obj.info))
+			}
+		}
+		m.mu.RUnlock()
+
+		slices.SortFunc(objects, func(a, b ObjectInfo) int { return strings.Compare(a.Key, b.Key) })
+		for _, obj := range objects {
+			if !yield(obj, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Exists проверяет наличие объекта
+func (m *MemoryStorage) Exists(_ context.Context, key string) (bool, error) {
+	if key == "" {
+		return false, ErrS3EmptyKey
+	}
+
+	m.mu.RLock()
+	_, ok := m.objects[key]
+	m.mu.RUnlock()
+	return ok, nil
+}
+
+// GeneratePresignedUploadURL возвращает URL вида memory://bucket/key с методом и сроком действия в query.
+// URL не предназначен для реальных запросов, только для проверок в тестах.
+func (m *MemoryStorage) GeneratePresignedUploadURL(_ context.Context, key string, contentType string, expiresIn time.Duration) (string, error) {
+	if key == "" {
+		return "", ErrS3EmptyKey
+	}
+	if expiresIn <= 0 {
+		return "", errInvalidExpiry
+	}
+
+	q := presignQuery("PUT", expiresIn)
+	if contentType != "" {
+		q.Set("content-type", contentType)
+	}
+	return m.url(key) + "?" + q.Encode(), nil
+}
+
+// GeneratePresignedDownloadURL возвращает URL вида memory://bucket/key с методом и сроком действия в query
+func (m *MemoryStorage) GeneratePresignedDownloadURL(_ context.Context, key string, expiresIn time.Duration) (string, error) {
+	if key == "" {
+		return "", ErrS3EmptyKey
+	}
+	if expiresIn <= 0 {
+		return "", errInvalidExpiry
+	}
+
+	return m.url(key) + "?" + presignQuery("GET", expiresIn).Encode(), nil
+}
+
+// GeneratePresignedPost возвращает поля формы без подписи. Условия policy не проверяются.
+func (m *MemoryStorage) GeneratePresignedPost(_ context.Context, policy PostPolicy) (PresignedPost, error) {
+	if err := policy.validate(); err != nil {
+		return PresignedPost{}, err
+	}
+	policy = policy.withDefaults()
+
+	fields := map[string]string{"key": policy.formKey()}
+	if policy.ContentType != "" {
+		fields["Content-Type"] = policy.ContentType
+	}
+	return PresignedPost{
+		URL:     (&url.URL{Scheme: memoryScheme, Host: m.bucket, Path: "/"}).String(),
+		Fields:  fields,
+		Expires: time.Now().Add(policy.Expires),
+	}, nil
+}
+
+func (m *MemoryStorage) put(key string, data []byte, contentType string, opts UploadOptions) {
+	sum := md5.Sum(data) //nolint:gosec
+	obj := memoryObject{
+		data: data,
+		info: ObjectInfo{
+			Key:                key,
+			Size:               int64(len(data)),
+			LastModified:       time.Now().UTC(),
+			ETag:               `"` + hex.EncodeToString(sum[:]) + `"`,
+			ContentType:        contentType,
+			CacheControl:       opts.CacheControl,
+			ContentDisposition: opts.ContentDisposition,
+			Metadata:           maps.Clone(opts.Metadata),
+		},
+		tags: maps.Clone(opts.Tags),
+	}
+
+	m.mu.Lock()
+	m.objects[key] = obj
+	m.mu.Unlock()
+}
+
+// get возвращает объект; отсутствие объекта оборачивается в base и ErrS3NotFound
+func (m *MemoryStorage) get(ctx context.Context, key string, base error) (memoryObject, error) {
+	if err := ctx.Err(); err != nil {
+		return memoryObject{}, fmt.Errorf("%w: %w", base, err)
+	}
+
+	m.mu.RLock()
+	obj, ok := m.objects[key]
+	m.mu.RUnlock()
+	if !ok {
+		return memoryObject{}, fmt.Errorf("%w: %w: %s", base, ErrS3NotFound, key)
+	}
+	return obj, nil
+}
+
+func (m *MemoryStorage) url(key string) string {
+	return (&url.URL{Scheme: memoryScheme, Host: m.bucket, Path: "/" + key}).String()
+}
+
+func presignQuery(method string, expiresIn time.Duration) url.Values {
+	return url.Values{
+		"method":  {method},
+		"expires": {strconv.FormatInt(time.Now().Add(expiresIn).Unix(), 10)},
+	}
+}
+
+// listInfo оставляет поля, которые возвращает листинг S3
+func listInfo(info ObjectInfo) ObjectInfo {
+	return ObjectInfo{
+		Key:          info.Key,
+		Size:         info.Size,
+		LastModified: info.LastModified,
+		ETag:         info.ETag,
+	}
+}
+
+type nopSeekCloser struct {
+	io.ReadSeeker
+}
+
+func (nopSeekCloser) Close() error { return nil }
@@ -0,0 +1,30 @@
+package s3
+
+import (
+	"sync"
+
+	coreMetrics "github.com/Rasikrr/core/metrics"
+)
+
+type Metrics struct {
+	reqTotal   coreMetrics.CounterVec   // {operation, bucket, status}
+	latencySec coreMetrics.HistogramVec // {operation, bucket, status}
+	bytesTotal coreMetrics.CounterVec   // {operation, bucket}
+}
+
+var (
+	metrics *Metrics
+	once    sync.Once
+)
+
+func initS3Metrics() {
+	once.Do(func() {
+		dur := []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+		metrics = &Metrics{
+			reqTotal:   coreMetrics.NewCounterVec("s3", "requests_total", "S3 operations", []string{"operation", "bucket", "status"}, nil),
+			latencySec: coreMetrics.NewHistogramVec("s3", "request_seconds", "S3 operation latency", dur, []string{"operation", "bucket", "status"}, nil),
+			bytesTotal: coreMetrics.NewCounterVec("s3", "bytes_total", "Bytes uploaded and downloaded", []string{"operation", "bucket"}, nil),
+		}
+	})
+}
@@ -2,10 +2,16 @@ package s3
 
 import "time"
 
-// ObjectInfo содержит информацию об объекте в S3
+// ObjectInfo содержит информацию об объекте в S3.
+// ContentType, CacheControl, ContentDisposition и Metadata заполняются только Head, листинг их не возвращает.
 type ObjectInfo struct {
 	Key          string
 	Size         int64
 	LastModified time.Time
 	ETag         string
+
+	ContentType        string
+	CacheControl       string
+	ContentDisposition string
+	Metadata           map[string]string
 }
@@ -0,0 +1,192 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// Head возвращает размер, ETag, заголовки и пользовательские метаданные объекта
+func (c *Client) Head(ctx context.Context, key string) (_ ObjectInfo, err error) {
+	if key == "" {
+		return ObjectInfo{}, ErrS3EmptyKey
+	}
+
+	ctx, op := c.startOperation(ctx, "Head", key)
+	defer func() { op.end(err, 0) }()
+
+	out, err := c.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(c.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return ObjectInfo{}, wrapError(ErrS3HeadFailed, err)
+	}
+
+	return ObjectInfo{
+		Key:                key,
+		Size:               aws.ToInt64(out.ContentLength),
+		LastModified:       aws.ToTime(out.LastModified),
+		ETag:               aws.ToString(out.ETag),
+		ContentType:        aws.ToString(out.ContentType),
+		CacheControl:       aws.ToString(out.CacheControl),
+		ContentDisposition: aws.ToString(out.ContentDisposition),
+		Metadata:           out.Metadata,
+	}, nil
+}
+
+// Copy копирует объект внутри бакета на стороне S3 вместе с метаданными и тегами.
+// Объекты больше 5 ГБ одним запросом не копируются.
+func (c *Client) Copy(ctx context.Context, srcKey, dstKey string) (err error) {
+	if srcKey == "" || dstKey == "" {
+		return ErrS3EmptyKey
+	}
+
+	ctx, op := c.startOperation(ctx, "Copy", dstKey)
+	defer func() { op.end(err, 0) }()
+
+	_, err = c.s3Client.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String(c.bucketName),
+		Key:        aws.String(dstKey),
+		CopySource: aws.String((&url.URL{Path: c.bucketName + "/" + srcKey}).EscapedPath()),
+	})
+	if err != nil {
+		return wrapError(ErrS3CopyFailed, err)
+	}
+	return nil
+}
+
+// Move копирует объект и удаляет исходный. Операция не атомарна:
+// при ошибке удаления в бакете остаются обе копии. Перемещение в тот же ключ ничего не делает:
+// S3 отклоняет CopyObject в себя без изменения метаданных.
+func (c *Client) Move(ctx context.Context, srcKey, dstKey string) error {
+	if srcKey == "" || dstKey == "" {
+		return ErrS3EmptyKey
+	}
+	if srcKey == dstKey {
+		return nil
+	}
+	if err := c.Copy(ctx, srcKey, dstKey); err != nil {
+		return err
+	}
+	return c.Delete(ctx, srcKey)
+}
+
+// GetTags возвращает теги объекта
+func (c *Client) GetTags(ctx context.Context, key string) (_ map[string]string, err error) {
+	if key == "" {
+		return nil, ErrS3EmptyKey
+	}
+
+	ctx, op := c.startOperation(ctx, "GetTags", key)
+	defer func() { op.end(err, 0) }()
+
+	out, err := c.s3Client.GetObjectTagging(ctx, &s3.GetObjectTaggingInput{
+		Bucket: aws.String(c.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, wrapError(ErrS3TaggingFailed, err)
+	}
+
+	tags := make(map[string]string, len(out.TagSet))
+	for _, tag := range out.TagSet {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return tags, nil
+}
+
+// SetTags заменяет все теги объекта на tags
+func (c *Client) SetTags(ctx context.Context, key string, tags map[string]string) (err error) {
+	if key == "" {
+		return ErrS3EmptyKey
+	}
+
+	ctx, op := c.startOperation(ctx, "SetTags", key)
+	defer func() { op.end(err, 0) }()
+
+	tagSet := make([]types.Tag, 0, len(tags))
+	for k, v := range tags {
+		tagSet = append(tagSet, types.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	_, err = c.s3Client.PutObjectTagging(ctx, &s3.PutObjectTaggingInput{
+		Bucket:  aws.String(c.bucketName),
+		Key:     aws.String(key),
+		Tagging: &types.Tagging{TagSet: tagSet},
+	})
+	if err != nil {
+		return wrapError(ErrS3TaggingFailed, err)
+	}
+	return nil
+}
+
+// DownloadRange скачивает length байт объекта начиная с offset. length <= 0 - до конца объекта.
+func (c *Client) DownloadRange(ctx context.Context, key string, offset, length int64) (_ []byte, err error) {
+	if key == "" {
+		return nil, ErrS3EmptyKey
+	}
+	if offset < 0 {
+		return nil, errInvalidRange
+	}
+
+	ctx, op := c.startOperation(ctx, "DownloadRange", key)
+	var data []byte
+	defer func() { op.end(err, int64(len(data))) }()
+
+	body, err := c.getRange(ctx, key, offset, length)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err = io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrS3DownloadFailed, err)
+	}
+	return data, nil
+}
+
+// OpenReader открывает объект для последовательного чтения с произвольным смещением.
+// Данные запрашиваются лениво ranged GET с текущей позиции, поэтому Seek без чтения не обращается к S3.
+// Подходит для http.ServeContent. Reader не безопасен для конкурентного использования.
+func (c *Client) OpenReader(ctx context.Context, key string) (io.ReadSeekCloser, error) {
+	info, err := c.Head(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrS3DownloadFailed, err)
+	}
+	return &objectReader{
+		ctx:  ctx,
+		size: info.Size,
+		open: func(ctx context.Context, offset int64) (io.ReadCloser, error) {
+			ctx, op := c.startOperation(ctx, "OpenReader", key)
+			body, err := c.getRange(ctx, key, offset, 0)
+			op.end(err, 0)
+			return body, err
+		},
+	}, nil
+}
+
+func (c *Client) getRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	rng := "bytes=" + strconv.FormatInt(offset, 10) + "-"
+	if length > 0 {
+		rng += strconv.FormatInt(offset+length-1, 10)
+	}
+	out, err := c.s3Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(c.bucketName),
+		Key:    aws.String(key),
+		Range:  aws.String(rng),
+	})
+	if err != nil {
+		if isInvalidRange(err) {
+			return nil, fmt.Errorf("%w: %w", errInvalidRange, err)
+		}
+		return nil, downloadError(err)
+	}
+	return out.Body, nil
+}
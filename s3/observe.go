@@ -0,0 +1,84 @@
+package s3
+
+import (
+	"context"
+	"time"
+
+	"github.com/Rasikrr/core/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/Rasikrr/core/s3"
+
+const (
+	statusOK       = "ok"
+	statusNotFound = "not_found"
+	statusError    = "error"
+)
+
+// operation - span и метрики одного вызова Client
+type operation struct {
+	name   string
+	bucket string
+	start  time.Time
+	span   trace.Span
+}
+
+func (c *Client) startOperation(ctx context.Context, name, key string) (context.Context, *operation) {
+	op := &operation{
+		name:   name,
+		bucket: c.bucketName,
+		start:  time.Now(),
+	}
+	if !tracing.Enabled() {
+		op.span = trace.SpanFromContext(ctx)
+		return ctx, op
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.String("rpc.system", "aws-api"),
+		attribute.String("rpc.service", "S3"),
+		attribute.String("rpc.method", name),
+		attribute.String("aws.s3.bucket", c.bucketName),
+	}
+	if key != "" {
+		attrs = append(attrs, attribute.String("aws.s3.key", key))
+	}
+	ctx, op.span = tracing.GetTracer(tracerName).Start(ctx,
+		"S3."+name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	return ctx, op
+}
+
+// end завершает операцию. bytes - объем переданных данных, учитывается только при успехе.
+func (op *operation) end(err error, bytes int64) {
+	status := statusOK
+	switch {
+	case err == nil:
+	case isNotFound(err):
+		status = statusNotFound
+	default:
+		status = statusError
+	}
+
+	if tracing.Enabled() {
+		if bytes > 0 {
+			op.span.SetAttributes(attribute.Int64("aws.s3.bytes", bytes))
+		}
+		if status == statusError {
+			op.span.RecordError(err)
+			op.span.SetStatus(codes.Error, err.Error())
+		}
+		op.span.End()
+	}
+
+	metrics.reqTotal.WithLabelValues(op.name, op.bucket, status).Inc()
+	metrics.latencySec.WithLabelValues(op.name, op.bucket, status).Observe(time.Since(op.start).Seconds())
+	if bytes > 0 && err == nil {
+		metrics.bytesTotal.WithLabelValues(op.name, op.bucket).Add(float64(bytes))
+	}
+}
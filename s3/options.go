@@ -0,0 +1,111 @@
+package s3
+
+import (
+	"maps"
+	"mime"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// UploadOptions - дополнительные атрибуты загружаемого объекта
+type UploadOptions struct {
+	// Metadata - пользовательские метаданные (x-amz-meta-*). Ключи приводятся к нижнему регистру, как в S3.
+	Metadata           map[string]string
+	CacheControl       string
+	ContentDisposition string
+	Tags               map[string]string
+}
+
+type UploadOption func(*UploadOptions)
+
+// WithMetadata добавляет пользовательские метаданные объекта
+func WithMetadata(metadata map[string]string) UploadOption {
+	return func(o *UploadOptions) {
+		if o.Metadata == nil {
+			o.Metadata = make(map[string]string, len(metadata))
+		}
+		for k, v := range metadata {
+			o.Metadata[strings.ToLower(k)] = v
+		}
+	}
+}
+
+// WithCacheControl задает заголовок Cache-Control, с которым объект будет отдаваться
+func WithCacheControl(cacheControl string) UploadOption {
+	return func(o *UploadOptions) {
+		o.CacheControl = cacheControl
+	}
+}
+
+// WithContentDisposition задает заголовок Content-Disposition, например AttachmentDisposition("report.pdf")
+func WithContentDisposition(disposition string) UploadOption {
+	return func(o *UploadOptions) {
+		o.ContentDisposition = disposition
+	}
+}
+
+// WithTags задает теги объекта
+func WithTags(tags map[string]string) UploadOption {
+	return func(o *UploadOptions) {
+		if o.Tags == nil {
+			o.Tags = make(map[string]string, len(tags))
+		}
+		maps.Copy(o.Tags, tags)
+	}
+}
+
+// AttachmentDisposition возвращает Content-Disposition, при котором браузер скачивает файл под именем filename
+func AttachmentDisposition(filename string) string {
+	return mime.FormatMediaType("attachment", map[string]string{"filename": filename})
+}
+
+func newUploadOptions(opts []UploadOption) UploadOptions {
+	var o UploadOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func (o UploadOptions) apply(input *s3.PutObjectInput) {
+	if len(o.Metadata) > 0 {
+		input.Metadata = o.Metadata
+	}
+	if o.CacheControl != "" {
+		input.CacheControl = aws.String(o.CacheControl)
+	}
+	if o.ContentDisposition != "" {
+		input.ContentDisposition = aws.String(o.ContentDisposition)
+	}
+	if len(o.Tags) > 0 {
+		input.Tagging = aws.String(encodeTags(o.Tags))
+	}
+}
+
+// encodeTags кодирует теги в формат заголовка x-amz-tagging
+func encodeTags(tags map[string]string) string {
+	values := make(url.Values, len(tags))
+	for k, v := range tags {
+		values.Set(k, v)
+	}
+	return values.Encode()
+}
+
+// applyMultipart - то же, что apply, для CreateMultipartUpload
+func (o UploadOptions) applyMultipart(input *s3.CreateMultipartUploadInput) {
+	if len(o.Metadata) > 0 {
+		input.Metadata = o.Metadata
+	}
+	if o.CacheControl != "" {
+		input.CacheControl = aws.String(o.CacheControl)
+	}
+	if o.ContentDisposition != "" {
+		input.ContentDisposition = aws.String(o.ContentDisposition)
+	}
+	if len(o.Tags) > 0 {
+		input.Tagging = aws.String(encodeTags(o.Tags))
+	}
+}
@@ -0,0 +1,127 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+const (
+	// FilenameVariable в ключе заменяется S3 на имя файла из формы
+	FilenameVariable = "${filename}"
+
+	defaultPostExpires = 15 * time.Minute
+)
+
+// PostPolicy описывает ограничения presigned POST формы. S3 отклоняет загрузку, нарушающую любое из них.
+type PostPolicy struct {
+	// Key - точный ключ объекта. Взаимоисключается с KeyPrefix.
+	Key string
+	// KeyPrefix разрешает любой ключ с этим префиксом. По умолчанию форма получает ключ KeyPrefix + "${filename}".
+	KeyPrefix string
+	// ContentType - точный Content-Type, поле формы заполняется автоматически
+	ContentType string
+	// ContentTypePrefix разрешает Content-Type с префиксом, например "image/". Поле формы заполняет клиент.
+	ContentTypePrefix string
+	// MinSize и MaxSize ограничивают размер файла в байтах (content-length-range). MaxSize обязателен.
+	MinSize int64
+	MaxSize int64
+	// Expires - время жизни формы, по умолчанию 15 минут
+	Expires time.Duration
+}
+
+// PresignedPost - адрес и поля multipart формы. Поля отправляются как есть, файл - последним полем "file".
+type PresignedPost struct {
+	URL     string
+	Fields  map[string]string
+	Expires time.Time
+}
+
+func (p PostPolicy) validate() error {
+	switch {
+	case p.Key == "" && p.KeyPrefix == "":
+		return ErrS3EmptyKey
+	case p.Key != "" && p.KeyPrefix != "":
+		return fmt.Errorf("%w: key and key prefix are mutually exclusive", ErrS3InvalidInput)
+	case p.ContentType != "" && p.ContentTypePrefix != "":
+		return fmt.Errorf("%w: content type and content type prefix are mutually exclusive", ErrS3InvalidInput)
+	case p.MaxSize <= 0:
+		return fmt.Errorf("%w: max size must be positive", ErrS3InvalidInput)
+	case p.MinSize < 0 || p.MinSize > p.MaxSize:
+		return fmt.Errorf("%w: invalid size range", ErrS3InvalidInput)
+	case p.Expires < 0:
+		return errInvalidExpiry
+	}
+	return nil
+}
+
+func (p PostPolicy) withDefaults() PostPolicy {
+	if p.Expires == 0 {
+		p.Expires = defaultPostExpires
+	}
+	return p
+}
+
+// formKey - значение поля key формы
+func (p PostPolicy) formKey() string {
+	if p.Key != "" {
+		return p.Key
+	}
+	return p.KeyPrefix + FilenameVariable
+}
+
+// conditions возвращает условия policy документа в формате S3
+func (p PostPolicy) conditions() []any {
+	conditions := []any{
+		[]any{"content-length-range", p.MinSize, p.MaxSize},
+	}
+	if p.KeyPrefix != "" {
+		conditions = append(conditions, []any{"starts-with", "$key", p.KeyPrefix})
+	}
+	if p.ContentType != "" {
+		conditions = append(conditions, map[string]string{"Content-Type": p.ContentType})
+	}
+	if p.ContentTypePrefix != "" {
+		conditions = append(conditions, []any{"starts-with", "$Content-Type", p.ContentTypePrefix})
+	}
+	return conditions
+}
+
+// GeneratePresignedPost подписывает POST форму для загрузки файла напрямую из браузера
+// с ограничениями на ключ, Content-Type и размер
+func (c *Client) GeneratePresignedPost(ctx context.Context, policy PostPolicy) (_ PresignedPost, err error) {
+	if err := policy.validate(); err != nil {
+		return PresignedPost{}, err
+	}
+	policy = policy.withDefaults()
+
+	ctx, op := c.startOperation(ctx, "PresignPost", policy.formKey())
+	defer func() { op.end(err, 0) }()
+
+	expires := time.Now().Add(policy.Expires)
+	req, err := c.presignClient.PresignPostObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(c.bucketName),
+		Key:    aws.String(policy.formKey()),
+	}, func(o *s3.PresignPostOptions) {
+		o.Expires = policy.Expires
+		o.Conditions = policy.conditions()
+	})
+	if err != nil {
+		return PresignedPost{}, fmt.Errorf("s3: failed to generate presigned post: %w", err)
+	}
+
+	fields := req.Values
+	if policy.ContentType != "" {
+		fields["Content-Type"] = policy.ContentType
+	}
+	return PresignedPost{URL: req.URL, Fields: fields, Expires: expires}, nil
+}
+
+// resolveFormKey подставляет имя файла в ключ так же, как S3
+func resolveFormKey(key, filename string) string {
+	return strings.ReplaceAll(key, FilenameVariable, filename)
+}
@@ -0,0 +1,49 @@
+package s3
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratePresignedPost(t *testing.T) {
+	ctx := context.Background()
+	client, err := NewClient(ctx, Config{
+		Region:          "us-east-1",
+		Endpoint:        "http://localhost:9000",
+		AccessKeyID:     "key",
+		SecretAccessKey: "secret",
+		BucketName:      "uploads",
+	})
+	require.NoError(t, err)
+
+	post, err := client.GeneratePresignedPost(ctx, PostPolicy{
+		KeyPrefix:         "avatars/42/",
+		ContentTypePrefix: "image/",
+		MaxSize:           1 << 20,
+		Expires:           time.Minute,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "http://localhost:9000/uploads", post.URL)
+	require.Equal(t, "avatars/42/${filename}", post.Fields["key"])
+	require.NotEmpty(t, post.Fields["X-Amz-Signature"])
+
+	raw, err := base64.StdEncoding.DecodeString(post.Fields["policy"])
+	require.NoError(t, err)
+	var policy struct {
+		Conditions []any `json:"conditions"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &policy))
+	require.Contains(t, policy.Conditions, []any{"content-length-range", float64(0), float64(1 << 20)})
+	require.Contains(t, policy.Conditions, []any{"starts-with", "$key", "avatars/42/"})
+	require.Contains(t, policy.Conditions, []any{"starts-with", "$Content-Type", "image/"})
+
+	_, err = client.GeneratePresignedPost(ctx, PostPolicy{Key: "a", KeyPrefix: "b/", MaxSize: 1})
+	require.ErrorIs(t, err, ErrS3InvalidInput)
+	_, err = client.GeneratePresignedPost(ctx, PostPolicy{Key: "a"})
+	require.ErrorIs(t, err, ErrS3InvalidInput)
+}
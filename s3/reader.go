@@ -0,0 +1,81 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// objectReader реализует io.ReadSeekCloser поверх ranged запросов: тело открывается
+// при первом Read после Seek и читается потоком до следующего Seek.
+type objectReader struct {
+	ctx    context.Context
+	size   int64
+	offset int64
+	body   io.ReadCloser
+	open   func(ctx context.Context, offset int64) (io.ReadCloser, error)
+	closed bool
+}
+
+func (r *objectReader) Read(p []byte) (int, error) {
+	if r.closed {
+		return 0, errReaderClosed
+	}
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+	if r.body == nil {
+		body, err := r.open(r.ctx, r.offset)
+		if err != nil {
+			return 0, err
+		}
+		r.body = body
+	}
+
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+	if errors.Is(err, io.EOF) && r.offset < r.size {
+		return n, fmt.Errorf("%w: %w", ErrS3DownloadFailed, io.ErrUnexpectedEOF)
+	}
+	return n, err
+}
+
+func (r *objectReader) Seek(offset int64, whence int) (int64, error) {
+	if r.closed {
+		return 0, errReaderClosed
+	}
+
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = r.offset + offset
+	case io.SeekEnd:
+		abs = r.size + offset
+	default:
+		return 0, fmt.Errorf("%w: invalid whence", ErrS3InvalidInput)
+	}
+	if abs < 0 {
+		return 0, fmt.Errorf("%w: negative position", ErrS3InvalidInput)
+	}
+
+	if abs != r.offset && r.body != nil {
+		_ = r.body.Close()
+		r.body = nil
+	}
+	r.offset = abs
+	return abs, nil
+}
+
+func (r *objectReader) Close() error {
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	if r.body != nil {
+		return r.body.Close()
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjectReaderServeContent(t *testing.T) {
+	content := []byte("0123456789")
+	var opened []int64
+	reader := &objectReader{
+		ctx:  context.Background(),
+		size: int64(len(content)),
+		open: func(_ context.Context, offset int64) (io.ReadCloser, error) {
+			opened = append(opened, offset)
+			return io.NopCloser(bytes.NewReader(content[offset:])), nil
+		},
+	}
+	defer reader.Close()
+
+	req := httptest.NewRequest(http.MethodGet, "/file", nil)
+	req.Header.Set("Range", "bytes=3-5")
+	rec := httptest.NewRecorder()
+	http.ServeContent(rec, req, "file.txt", time.Time{}, reader)
+
+	require.Equal(t, http.StatusPartialContent, rec.Code)
+	require.Equal(t, "345", rec.Body.String())
+	// Определение размера через Seek не открывает тело, читается только запрошенный диапазон
+	require.Equal(t, []int64{3}, opened)
+}
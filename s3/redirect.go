@@ -0,0 +1,93 @@
+package s3
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	coreHTTP "github.com/Rasikrr/core/http"
+	"github.com/go-chi/chi/v5"
+)
+
+const defaultRedirectExpires = 15 * time.Minute
+
+// RedirectOptions настраивает RedirectHandler
+type RedirectOptions struct {
+	// Expires - время жизни presigned URL, по умолчанию 15 минут
+	Expires time.Duration
+	// Key возвращает ключ объекта для запроса. По умолчанию - путь после префикса,
+	// под которым обработчик смонтирован через Server.Mount.
+	// Ошибка типа *coreHTTP.Error отдается клиенту как есть, например 403 при отсутствии доступа.
+	Key func(r *http.Request) (string, error)
+	// CheckExists проверяет объект через Head и отвечает 404, вместо того чтобы отправить клиента в S3 за ошибкой
+	CheckExists bool
+}
+
+// RedirectHandler отвечает 302 на presigned URL объекта, так что файл отдается напрямую из S3,
+// минуя приложение. Монтируется на http.Server:
+//
+//	app.HTTPServer().Mount("/files", s3.RedirectHandler(app.S3(), s3.RedirectOptions{}))
+func RedirectHandler(st Storage, opts RedirectOptions) http.HandlerFunc {
+	if opts.Expires <= 0 {
+		opts.Expires = defaultRedirectExpires
+	}
+	if opts.Key == nil {
+		opts.Key = routeKey
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			coreHTTP.SendError(ctx, w, coreHTTP.NewError(http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed))
+			return
+		}
+
+		key, err := opts.Key(r)
+		if err != nil {
+			coreHTTP.SendError(ctx, w, err)
+			return
+		}
+		if key == "" {
+			coreHTTP.SendError(ctx, w, coreHTTP.NewError(ErrS3EmptyKey.Error(), http.StatusBadRequest))
+			return
+		}
+
+		if opts.CheckExists {
+			if _, err := st.Head(ctx, key); err != nil {
+				coreHTTP.SendError(ctx, w, redirectError(err))
+				return
+			}
+		}
+
+		target, err := st.GeneratePresignedDownloadURL(ctx, key, opts.Expires)
+		if err != nil {
+			coreHTTP.SendError(ctx, w, redirectError(err))
+			return
+		}
+
+		// Редирект живет не дольше подписи: закэшированный протухший URL отдал бы 403
+		coreHTTP.SetCacheControl(w, coreHTTP.CacheControl{Private: true, MaxAge: opts.Expires / 2})
+		http.Redirect(w, r, target, http.StatusFound)
+	}
+}
+
+// routeKey возвращает путь после префикса монтирования: chi.Mount сохраняет его в RoutePath
+func routeKey(r *http.Request) (string, error) {
+	path := r.URL.Path
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePath != "" {
+		path = rctx.RoutePath
+	}
+	return strings.TrimPrefix(path, "/"), nil
+}
+
+func redirectError(err error) error {
+	switch {
+	case errors.Is(err, ErrS3NotFound):
+		return coreHTTP.NewError("object not found", http.StatusNotFound)
+	case errors.Is(err, ErrS3InvalidInput):
+		return coreHTTP.NewError(err.Error(), http.StatusBadRequest)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,307 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Rasikrr/core/log"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"golang.org/x/sync/errgroup"
+)
+
+// multipartAPI - вызовы S3, которыми управляется multipart загрузка
+type multipartAPI interface {
+	CreateMultipartUpload(ctx context.Context, params *s3.CreateMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error)
+	UploadPart(ctx context.Context, params *s3.UploadPartInput, optFns ...func(*s3.Options)) (*s3.UploadPartOutput, error)
+	ListParts(ctx context.Context, params *s3.ListPartsInput, optFns ...func(*s3.Options)) (*s3.ListPartsOutput, error)
+	CompleteMultipartUpload(ctx context.Context, params *s3.CompleteMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error)
+	AbortMultipartUpload(ctx context.Context, params *s3.AbortMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error)
+	ListMultipartUploads(ctx context.Context, params *s3.ListMultipartUploadsInput, optFns ...func(*s3.Options)) (*s3.ListMultipartUploadsOutput, error)
+}
+
+// ResumableUpload описывает загрузку, которую можно продолжить после ошибки или рестарта процесса
+type ResumableUpload struct {
+	// ID - стабильный идентификатор загрузки в приложении, например ID файла в БД.
+	// По нему прогресс ищется в UploadStore.
+	ID  string
+	Key string
+	// Source читается частями с произвольных смещений, поэтому после рестарта
+	// повторно передаются только недостающие части. Например, *os.File.
+	Source      io.ReaderAt
+	Size        int64
+	ContentType string
+	Options     []UploadOption
+}
+
+// UploadResumable загружает Source multipart загрузкой частями Config.PartSize с параллельностью Config.Concurrency.
+// Upload ID и ETag каждой принятой части сохраняются в store. Повторный вызов с тем же ID
+// сверяет сохраненный прогресс с S3 и догружает только недостающие части.
+// При ошибке загрузка не прерывается: вызовите UploadResumable повторно или AbortResumable.
+func (c *Client) UploadResumable(ctx context.Context, store UploadStore, upload ResumableUpload) (string, error) {
+	if upload.ID == "" {
+		return "", fmt.Errorf("%w: empty upload id", ErrS3InvalidInput)
+	}
+	if upload.Key == "" {
+		return "", ErrS3EmptyKey
+	}
+	if upload.Source == nil || upload.Size <= 0 {
+		return "", ErrS3EmptyData
+	}
+
+	state, err := c.resumeState(ctx, store, upload)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrS3UploadFailed, err)
+	}
+	if err := c.uploadParts(ctx, store, upload, &state); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrS3UploadFailed, err)
+	}
+
+	location, err := c.completeUpload(ctx, state)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrS3UploadFailed, err)
+	}
+	if err := store.Delete(ctx, upload.ID); err != nil {
+		log.Warn(ctx, "s3: failed to delete completed upload state", log.String("upload", upload.ID), log.Err(err))
+	}
+	return location, nil
+}
+
+// AbortResumable прерывает загрузку в S3 и удаляет ее прогресс
+func (c *Client) AbortResumable(ctx context.Context, store UploadStore, id string) error {
+	state, err := store.Load(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrUploadStateNotFound) {
+			return nil
+		}
+		return err
+	}
+	if err := c.abortUpload(ctx, state.Bucket, state.Key, state.UploadID); err != nil && !isNoSuchUpload(err) {
+		return err
+	}
+	return store.Delete(ctx, id)
+}
+
+// resumeState возвращает сохраненный прогресс, сверенный с S3, или начинает новую загрузку
+func (c *Client) resumeState(ctx context.Context, store UploadStore, upload ResumableUpload) (UploadState, error) {
+	state, err := store.Load(ctx, upload.ID)
+	switch {
+	case errors.Is(err, ErrUploadStateNotFound):
+		return c.createUpload(ctx, store, upload)
+	case err != nil:
+		return UploadState{}, err
+	}
+
+	if state.Bucket != c.bucketName || state.Key != upload.Key || state.Size != upload.Size ||
+		state.PartSize != c.partSize || state.Checksum != string(c.checksum) {
+		// ID переиспользован для другого объекта или изменилась конфигурация: старые части не подходят
+		log.Warn(ctx, "s3: upload state does not match upload, starting over", log.String("upload", upload.ID))
+		if err := c.abortUpload(ctx, state.Bucket, state.Key, state.UploadID); err != nil && !isNoSuchUpload(err) {
+			log.Warn(ctx, "s3: failed to abort outdated upload", log.String("upload", upload.ID), log.Err(err))
+		}
+		return c.createUpload(ctx, store, upload)
+	}
+
+	parts, err := c.listParts(ctx, state)
+	if err != nil {
+		if isNoSuchUpload(err) {
+			// Загрузка прервана в S3, например cron джобой очистки
+			return c.createUpload(ctx, store, upload)
+		}
+		return UploadState{}, err
+	}
+	// Источник истины - S3: часть из store, которой нет в S3 или у которой другой ETag, загружается заново
+	state.Parts = slices.DeleteFunc(state.Parts, func(p UploadedPart) bool {
+		etag, ok := parts[p.Number]
+		return !ok || etag != p.ETag
+	})
+	return state, nil
+}
+
+func (c *Client) createUpload(ctx context.Context, store UploadStore, upload ResumableUpload) (_ UploadState, err error) {
+	ctx, op := c.startOperation(ctx, "CreateMultipartUpload", upload.Key)
+	defer func() { op.end(err, 0) }()
+
+	input := &s3.CreateMultipartUploadInput{
+		Bucket:            aws.String(c.bucketName),
+		Key:               aws.String(upload.Key),
+		ChecksumAlgorithm: c.checksum,
+	}
+	if upload.ContentType != "" {
+		input.ContentType = aws.String(upload.ContentType)
+	}
+	newUploadOptions(upload.Options).applyMultipart(input)
+
+	out, err := c.multipart.CreateMultipartUpload(ctx, input)
+	if err != nil {
+		return UploadState{}, err
+	}
+
+	state := UploadState{
+		Bucket:    c.bucketName,
+		Key:       upload.Key,
+		UploadID:  aws.ToString(out.UploadId),
+		Size:      upload.Size,
+		PartSize:  c.partSize,
+		Checksum:  string(c.checksum),
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := store.Save(ctx, upload.ID, state); err != nil {
+		// Без сохраненного Upload ID загрузку не продолжить, части останутся сиротами до очистки
+		_ = c.abortUpload(ctx, state.Bucket, state.Key, state.UploadID)
+		return UploadState{}, err
+	}
+	return state, nil
+}
+
+// listParts возвращает ETag частей, уже принятых S3
+func (c *Client) listParts(ctx context.Context, state UploadState) (_ map[int32]string, err error) {
+	ctx, op := c.startOperation(ctx, "ListParts", state.Key)
+	defer func() { op.end(err, 0) }()
+
+	parts := make(map[int32]string)
+	input := &s3.ListPartsInput{
+		Bucket:   aws.String(state.Bucket),
+		Key:      aws.String(state.Key),
+		UploadId: aws.String(state.UploadID),
+	}
+	for {
+		out, err := c.multipart.ListParts(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range out.Parts {
+			parts[aws.ToInt32(p.PartNumber)] = aws.ToString(p.ETag)
+		}
+		if !aws.ToBool(out.IsTruncated) {
+			return parts, nil
+		}
+		input.PartNumberMarker = out.NextPartNumberMarker
+	}
+}
+
+// uploadParts параллельно загружает недостающие части и сохраняет прогресс после каждой
+func (c *Client) uploadParts(ctx context.Context, store UploadStore, upload ResumableUpload, state *UploadState) error {
+	done := make(map[int32]bool, len(state.Parts))
+	for _, p := range state.Parts {
+		done[p.Number] = true
+	}
+
+	// Горутины читают только неизменяемые поля, Parts меняется под mu
+	target := *state
+	target.Parts = nil
+
+	var mu sync.Mutex
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(c.concurrency)
+
+	partCount := int32((upload.Size + state.PartSize - 1) / state.PartSize)
+	for number := int32(1); number <= partCount; number++ {
+		if done[number] {
+			continue
+		}
+		g.Go(func() error {
+			offset := int64(number-1) * target.PartSize
+			length := min(target.PartSize, upload.Size-offset)
+			part, err := c.uploadPart(gctx, target, number, io.NewSectionReader(upload.Source, offset, length))
+			if err != nil {
+				return fmt.Errorf("part %d: %w", number, err)
+			}
+
+			// Сохранения сериализуются, чтобы в store не попал более старый снимок
+			mu.Lock()
+			defer mu.Unlock()
+			state.Parts = append(state.Parts, part)
+			return store.Save(gctx, upload.ID, *state)
+		})
+	}
+	return g.Wait()
+}
+
+func (c *Client) uploadPart(ctx context.Context, state UploadState, number int32, body *io.SectionReader) (_ UploadedPart, err error) {
+	ctx, op := c.startOperation(ctx, "UploadPart", state.Key)
+	defer func() { op.end(err, body.Size()) }()
+
+	// SDK считает контрольную сумму части, S3 отклоняет часть при несовпадении
+	out, err := c.multipart.UploadPart(ctx, &s3.UploadPartInput{
+		Bucket:            aws.String(state.Bucket),
+		Key:               aws.String(state.Key),
+		UploadId:          aws.String(state.UploadID),
+		PartNumber:        aws.Int32(number),
+		Body:              body,
+		ContentLength:     aws.Int64(body.Size()),
+		ChecksumAlgorithm: types.ChecksumAlgorithm(state.Checksum),
+	})
+	if err != nil {
+		return UploadedPart{}, err
+	}
+
+	part := UploadedPart{Number: number, ETag: aws.ToString(out.ETag)}
+	switch types.ChecksumAlgorithm(state.Checksum) {
+	case types.ChecksumAlgorithmCrc32c:
+		part.Checksum = aws.ToString(out.ChecksumCRC32C)
+	case types.ChecksumAlgorithmSha256:
+		part.Checksum = aws.ToString(out.ChecksumSHA256)
+	}
+	return part, nil
+}
+
+func (c *Client) completeUpload(ctx context.Context, state UploadState) (_ string, err error) {
+	ctx, op := c.startOperation(ctx, "CompleteMultipartUpload", state.Key)
+	defer func() { op.end(err, 0) }()
+
+	parts := slices.Clone(state.Parts)
+	slices.SortFunc(parts, func(a, b UploadedPart) int { return int(a.Number - b.Number) })
+
+	completed := make([]types.CompletedPart, 0, len(parts))
+	for _, p := range parts {
+		cp := types.CompletedPart{PartNumber: aws.Int32(p.Number), ETag: aws.String(p.ETag)}
+		// S3 сверяет суммы частей с принятыми и считает итоговую сумму объекта
+		switch types.ChecksumAlgorithm(state.Checksum) {
+		case types.ChecksumAlgorithmCrc32c:
+			cp.ChecksumCRC32C = aws.String(p.Checksum)
+		case types.ChecksumAlgorithmSha256:
+			cp.ChecksumSHA256 = aws.String(p.Checksum)
+		}
+		completed = append(completed, cp)
+	}
+
+	out, err := c.multipart.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(state.Bucket),
+		Key:             aws.String(state.Key),
+		UploadId:        aws.String(state.UploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: completed},
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(out.Location), nil
+}
+
+func (c *Client) abortUpload(ctx context.Context, bucket, key, uploadID string) (err error) {
+	ctx, op := c.startOperation(ctx, "AbortMultipartUpload", key)
+	defer func() { op.end(err, 0) }()
+
+	_, err = c.multipart.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	return err
+}
+
+func isNoSuchUpload(err error) bool {
+	var noSuchUpload *types.NoSuchUpload
+	if errors.As(err, &noSuchUpload) {
+		return true
+	}
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchUpload"
+}
@@ -0,0 +1,148 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMultipart хранит части в памяти и может отказать в загрузке выбранной части
+type fakeMultipart struct {
+	mu       sync.Mutex
+	parts    map[int32][]byte
+	uploaded []int32
+	failPart int32
+	complete *s3.CompleteMultipartUploadInput
+	uploads  []types.MultipartUpload
+	aborted  []string
+}
+
+func (f *fakeMultipart) CreateMultipartUpload(_ context.Context, in *s3.CreateMultipartUploadInput, _ ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error) {
+	f.parts = make(map[int32][]byte)
+	return &s3.CreateMultipartUploadOutput{UploadId: aws.String("upload-1"), Key: in.Key}, nil
+}
+
+func (f *fakeMultipart) UploadPart(_ context.Context, in *s3.UploadPartInput, _ ...func(*s3.Options)) (*s3.UploadPartOutput, error) {
+	number := aws.ToInt32(in.PartNumber)
+	if number == f.failPart {
+		return nil, errors.New("connection reset")
+	}
+	data, err := io.ReadAll(in.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.parts[number] = data
+	f.uploaded = append(f.uploaded, number)
+	return &s3.UploadPartOutput{
+		ETag:           aws.String(fmt.Sprintf(`"etag-%d"`, number)),
+		ChecksumCRC32C: aws.String(fmt.Sprintf("crc-%d", number)),
+	}, nil
+}
+
+func (f *fakeMultipart) ListParts(_ context.Context, _ *s3.ListPartsInput, _ ...func(*s3.Options)) (*s3.ListPartsOutput, error) {
+	out := &s3.ListPartsOutput{}
+	for number := range f.parts {
+		out.Parts = append(out.Parts, types.Part{
+			PartNumber: aws.Int32(number),
+			ETag:       aws.String(fmt.Sprintf(`"etag-%d"`, number)),
+		})
+	}
+	return out, nil
+}
+
+func (f *fakeMultipart) CompleteMultipartUpload(_ context.Context, in *s3.CompleteMultipartUploadInput, _ ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error) {
+	f.complete = in
+	return &s3.CompleteMultipartUploadOutput{Location: aws.String("https://s3/" + aws.ToString(in.Key))}, nil
+}
+
+func (f *fakeMultipart) AbortMultipartUpload(_ context.Context, in *s3.AbortMultipartUploadInput, _ ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error) {
+	f.aborted = append(f.aborted, aws.ToString(in.UploadId))
+	return &s3.AbortMultipartUploadOutput{}, nil
+}
+
+func (f *fakeMultipart) ListMultipartUploads(_ context.Context, _ *s3.ListMultipartUploadsInput, _ ...func(*s3.Options)) (*s3.ListMultipartUploadsOutput, error) {
+	return &s3.ListMultipartUploadsOutput{Uploads: f.uploads}, nil
+}
+
+func newMultipartClient(t *testing.T, api multipartAPI) *Client {
+	t.Helper()
+	client, err := NewClient(context.Background(), Config{
+		Region:          "us-east-1",
+		Endpoint:        "http://localhost:9000",
+		AccessKeyID:     "key",
+		SecretAccessKey: "secret",
+		BucketName:      "uploads",
+		PartSize:        minPartSize,
+		Concurrency:     2,
+		Checksum:        ChecksumCRC32C,
+	})
+	require.NoError(t, err)
+	client.multipart = api
+	return client
+}
+
+func TestUploadResumable(t *testing.T) {
+	ctx := context.Background()
+	api := &fakeMultipart{failPart: 3}
+	client := newMultipartClient(t, api)
+	store := NewMemoryUploadStore()
+
+	data := bytes.Repeat([]byte("0123456789"), (2*minPartSize+minPartSize/2)/10)
+	upload := ResumableUpload{
+		ID:     "file-42",
+		Key:    "videos/42.mp4",
+		Source: bytes.NewReader(data),
+		Size:   int64(len(data)),
+	}
+
+	_, err := client.UploadResumable(ctx, store, upload)
+	require.ErrorIs(t, err, ErrS3UploadFailed)
+
+	state, err := store.Load(ctx, "file-42")
+	require.NoError(t, err)
+	require.Equal(t, "upload-1", state.UploadID)
+	require.Len(t, state.Parts, 2)
+
+	api.failPart = 0
+	api.uploaded = nil
+	location, err := client.UploadResumable(ctx, store, upload)
+	require.NoError(t, err)
+	require.Equal(t, "https://s3/videos/42.mp4", location)
+	require.Equal(t, []int32{3}, api.uploaded, "only the missing part is uploaded again")
+
+	parts := api.complete.MultipartUpload.Parts
+	require.Len(t, parts, 3)
+	for i, p := range parts {
+		require.Equal(t, int32(i+1), aws.ToInt32(p.PartNumber))
+		require.Equal(t, fmt.Sprintf("crc-%d", i+1), aws.ToString(p.ChecksumCRC32C))
+	}
+	require.Equal(t, data, bytes.Join([][]byte{api.parts[1], api.parts[2], api.parts[3]}, nil))
+
+	_, err = store.Load(ctx, "file-42")
+	require.ErrorIs(t, err, ErrUploadStateNotFound)
+}
+
+func TestAbortStaleUploads(t *testing.T) {
+	api := &fakeMultipart{uploads: []types.MultipartUpload{
+		{Key: aws.String("old"), UploadId: aws.String("stale"), Initiated: aws.Time(time.Now().Add(-48 * time.Hour))},
+		{Key: aws.String("new"), UploadId: aws.String("active"), Initiated: aws.Time(time.Now())},
+	}}
+	client := newMultipartClient(t, api)
+
+	aborted, err := client.AbortStaleUploads(context.Background(), 24*time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, 1, aborted)
+	require.Equal(t, []string{"stale"}, api.aborted)
+}
@@ -0,0 +1,325 @@
+// Package s3test содержит общий набор тестов, который должна проходить каждая реализация s3.Storage.
+package s3test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Rasikrr/core/s3"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/stretchr/testify/require"
+)
+
+// RunStorageTests проверяет реализацию Storage. newStorage вызывается для каждого подтеста
+// и должен возвращать пустое хранилище (или хранилище, где не используются ключи с префиксом теста).
+func RunStorageTests(t *testing.T, newStorage func(t *testing.T) s3.Storage) {
+	t.Helper()
+
+	t.Run("UploadDownload", func(t *testing.T) {
+		ctx, st, prefix := setup(t, newStorage)
+		key := prefix + "dir/hello.txt"
+
+		location, err := st.Upload(ctx, key, []byte("hello"), "text/plain")
+		require.NoError(t, err)
+		require.NotEmpty(t, location)
+
+		data, err := st.Download(ctx, key)
+		require.NoError(t, err)
+		require.Equal(t, "hello", string(data))
+
+		// Повторная загрузка перезаписывает объект
+		_, err = st.Upload(ctx, key, []byte("bye"), "text/plain")
+		require.NoError(t, err)
+		data, err = st.Download(ctx, key)
+		require.NoError(t, err)
+		require.Equal(t, "bye", string(data))
+	})
+
+	t.Run("Streams", func(t *testing.T) {
+		ctx, st, prefix := setup(t, newStorage)
+		key := prefix + "stream.bin"
+		payload := bytes.Repeat([]byte("0123456789"), 1024)
+
+		// NopCloser скрывает io.Seeker: проверяется загрузка потока неизвестной длины
+		_, err := st.UploadStream(ctx, key, io.NopCloser(bytes.NewReader(payload)), "application/octet-stream")
+		require.NoError(t, err)
+
+		buf := manager.NewWriteAtBuffer(nil)
+		n, err := st.DownloadStream(ctx, key, buf)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(payload)), n)
+		require.Equal(t, payload, buf.Bytes())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		ctx, st, prefix := setup(t, newStorage)
+		key := prefix + "missing"
+
+		_, err := st.Download(ctx, key)
+		require.ErrorIs(t, err, s3.ErrS3DownloadFailed)
+		require.ErrorIs(t, err, s3.ErrS3NotFound)
+
+		_, err = st.DownloadStream(ctx, key, manager.NewWriteAtBuffer(nil))
+		require.ErrorIs(t, err, s3.ErrS3NotFound)
+
+		ok, err := st.Exists(ctx, key)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		// Удаление отсутствующего объекта, как и в S3, не является ошибкой
+		require.NoError(t, st.Delete(ctx, key))
+	})
+
+	t.Run("InvalidInput", func(t *testing.T) {
+		ctx, st, prefix := setup(t, newStorage)
+
+		_, err := st.Upload(ctx, "", []byte("x"), "")
+		require.ErrorIs(t, err, s3.ErrS3EmptyKey)
+		_, err = st.Upload(ctx, prefix+"empty", nil, "")
+		require.ErrorIs(t, err, s3.ErrS3EmptyData)
+		_, err = st.UploadStream(ctx, "", strings.NewReader("x"), "")
+		require.ErrorIs(t, err, s3.ErrS3EmptyKey)
+		_, err = st.UploadStream(ctx, prefix+"empty", nil, "")
+		require.ErrorIs(t, err, s3.ErrS3EmptyData)
+		_, err = st.Download(ctx, "")
+		require.ErrorIs(t, err, s3.ErrS3EmptyKey)
+		_, err = st.DownloadStream(ctx, "", manager.NewWriteAtBuffer(nil))
+		require.ErrorIs(t, err, s3.ErrS3EmptyKey)
+		_, err = st.DownloadStream(ctx, prefix+"x", nil)
+		require.ErrorIs(t, err, s3.ErrS3InvalidInput)
+		require.ErrorIs(t, st.Delete(ctx, ""), s3.ErrS3EmptyKey)
+		require.ErrorIs(t, st.DeleteMultiple(ctx, nil), s3.ErrS3InvalidInput)
+		require.ErrorIs(t, st.DeleteMultiple(ctx, []string{"", ""}), s3.ErrS3InvalidInput)
+		_, err = st.Exists(ctx, "")
+		require.ErrorIs(t, err, s3.ErrS3EmptyKey)
+		_, err = st.GeneratePresignedUploadURL(ctx, "", "", time.Minute)
+		require.ErrorIs(t, err, s3.ErrS3EmptyKey)
+		_, err = st.GeneratePresignedUploadURL(ctx, prefix+"x", "", 0)
+		require.ErrorIs(t, err, s3.ErrS3InvalidInput)
+		_, err = st.GeneratePresignedDownloadURL(ctx, "", time.Minute)
+		require.ErrorIs(t, err, s3.ErrS3EmptyKey)
+		_, err = st.GeneratePresignedDownloadURL(ctx, prefix+"x", -time.Second)
+		require.ErrorIs(t, err, s3.ErrS3InvalidInput)
+	})
+
+	t.Run("ListAndDelete", func(t *testing.T) {
+		ctx, st, prefix := setup(t, newStorage)
+		keys := []string{prefix + "b/2.txt", prefix + "a/1.txt", prefix + "b/1.txt"}
+		for _, key := range keys {
+			_, err := st.Upload(ctx, key, []byte(key), "text/plain")
+			require.NoError(t, err)
+		}
+
+		listed, err := st.List(ctx, prefix)
+		require.NoError(t, err)
+		require.Equal(t, []string{prefix + "a/1.txt", prefix + "b/1.txt", prefix + "b/2.txt"}, listed)
+
+		details, err := st.ListWithDetails(ctx, prefix+"b/")
+		require.NoError(t, err)
+		require.Len(t, details, 2)
+		require.Equal(t, prefix+"b/1.txt", details[0].Key)
+		require.Equal(t, int64(len(prefix+"b/1.txt")), details[0].Size)
+		require.NotEmpty(t, details[0].ETag)
+		require.False(t, details[0].LastModified.IsZero())
+
+		ok, err := st.Exists(ctx, prefix+"a/1.txt")
+		require.NoError(t, err)
+		require.True(t, ok)
+		// Префикс ключа - не объект
+		ok, err = st.Exists(ctx, prefix+"a")
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		require.NoError(t, st.Delete(ctx, prefix+"a/1.txt"))
+		require.NoError(t, st.DeleteMultiple(ctx, []string{prefix + "b/1.txt", "", prefix + "b/2.txt"}))
+
+		listed, err = st.List(ctx, prefix)
+		require.NoError(t, err)
+		require.Empty(t, listed)
+	})
+
+	t.Run("HeadAndMetadata", func(t *testing.T) {
+		ctx, st, prefix := setup(t, newStorage)
+		key := prefix + "report.pdf"
+
+		_, err := st.Upload(ctx, key, []byte("%PDF"), "application/pdf",
+			s3.WithMetadata(map[string]string{"Owner": "42"}),
+			s3.WithCacheControl("private, max-age=60"),
+			s3.WithContentDisposition(s3.AttachmentDisposition("report.pdf")),
+		)
+		require.NoError(t, err)
+
+		info, err := st.Head(ctx, key)
+		require.NoError(t, err)
+		require.Equal(t, key, info.Key)
+		require.Equal(t, int64(4), info.Size)
+		require.NotEmpty(t, info.ETag)
+		require.Equal(t, "application/pdf", info.ContentType)
+		require.Equal(t, "private, max-age=60", info.CacheControl)
+		require.Equal(t, `attachment; filename=report.pdf`, info.ContentDisposition)
+		// Ключи метаданных, как и в S3, приводятся к нижнему регистру
+		require.Equal(t, map[string]string{"owner": "42"}, info.Metadata)
+
+		_, err = st.Head(ctx, prefix+"missing")
+		require.ErrorIs(t, err, s3.ErrS3NotFound)
+	})
+
+	t.Run("Tags", func(t *testing.T) {
+		ctx, st, prefix := setup(t, newStorage)
+		key := prefix + "tagged.txt"
+
+		_, err := st.Upload(ctx, key, []byte("x"), "text/plain", s3.WithTags(map[string]string{"env": "test", "kind": "a b"}))
+		require.NoError(t, err)
+		tags, err := st.GetTags(ctx, key)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"env": "test", "kind": "a b"}, tags)
+
+		require.NoError(t, st.SetTags(ctx, key, map[string]string{"state": "archived"}))
+		tags, err = st.GetTags(ctx, key)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"state": "archived"}, tags)
+
+		_, err = st.GetTags(ctx, prefix+"missing")
+		require.ErrorIs(t, err, s3.ErrS3NotFound)
+		require.ErrorIs(t, st.SetTags(ctx, prefix+"missing", nil), s3.ErrS3NotFound)
+	})
+
+	t.Run("CopyAndMove", func(t *testing.T) {
+		ctx, st, prefix := setup(t, newStorage)
+		src := prefix + "src/file.txt"
+
+		_, err := st.Upload(ctx, src, []byte("payload"), "text/plain", s3.WithMetadata(map[string]string{"k": "v"}))
+		require.NoError(t, err)
+
+		require.NoError(t, st.Copy(ctx, src, prefix+"copy/file.txt"))
+		info, err := st.Head(ctx, prefix+"copy/file.txt")
+		require.NoError(t, err)
+		require.Equal(t, "text/plain", info.ContentType)
+		require.Equal(t, map[string]string{"k": "v"}, info.Metadata)
+
+		require.NoError(t, st.Move(ctx, src, prefix+"moved/file.txt"))
+		ok, err := st.Exists(ctx, src)
+		require.NoError(t, err)
+		require.False(t, ok)
+		data, err := st.Download(ctx, prefix+"moved/file.txt")
+		require.NoError(t, err)
+		require.Equal(t, "payload", string(data))
+		require.NoError(t, st.Move(ctx, prefix+"moved/file.txt", prefix+"moved/file.txt"), "move onto itself is a no-op")
+		data, err = st.Download(ctx, prefix+"moved/file.txt")
+		require.NoError(t, err)
+		require.Equal(t, "payload", string(data))
+
+		require.ErrorIs(t, st.Copy(ctx, prefix+"missing", prefix+"dst"), s3.ErrS3NotFound)
+		require.ErrorIs(t, st.Copy(ctx, "", prefix+"dst"), s3.ErrS3EmptyKey)
+	})
+
+	t.Run("Ranges", func(t *testing.T) {
+		ctx, st, prefix := setup(t, newStorage)
+		key := prefix + "range.txt"
+		_, err := st.Upload(ctx, key, []byte("0123456789"), "text/plain")
+		require.NoError(t, err)
+
+		data, err := st.DownloadRange(ctx, key, 2, 3)
+		require.NoError(t, err)
+		require.Equal(t, "234", string(data))
+		data, err = st.DownloadRange(ctx, key, 7, 0)
+		require.NoError(t, err)
+		require.Equal(t, "789", string(data))
+		data, err = st.DownloadRange(ctx, key, 8, 100)
+		require.NoError(t, err)
+		require.Equal(t, "89", string(data))
+
+		_, err = st.DownloadRange(ctx, key, 10, 1)
+		require.ErrorIs(t, err, s3.ErrS3InvalidInput)
+		_, err = st.DownloadRange(ctx, prefix+"missing", 0, 1)
+		require.ErrorIs(t, err, s3.ErrS3NotFound)
+
+		r, err := st.OpenReader(ctx, key)
+		require.NoError(t, err)
+		defer r.Close()
+		size, err := r.Seek(0, io.SeekEnd)
+		require.NoError(t, err)
+		require.Equal(t, int64(10), size)
+		_, err = r.Seek(4, io.SeekStart)
+		require.NoError(t, err)
+		buf := make([]byte, 2)
+		_, err = io.ReadFull(r, buf)
+		require.NoError(t, err)
+		require.Equal(t, "45", string(buf))
+		rest, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, "6789", string(rest))
+
+		_, err = st.OpenReader(ctx, prefix+"missing")
+		require.ErrorIs(t, err, s3.ErrS3NotFound)
+	})
+
+	t.Run("Objects", func(t *testing.T) {
+		ctx, st, prefix := setup(t, newStorage)
+		for _, key := range []string{"c", "a", "b"} {
+			_, err := st.Upload(ctx, prefix+key, []byte(key), "text/plain")
+			require.NoError(t, err)
+		}
+
+		var keys []string
+		for obj, err := range st.Objects(ctx, prefix) {
+			require.NoError(t, err)
+			keys = append(keys, strings.TrimPrefix(obj.Key, prefix))
+			if len(keys) == 2 {
+				break
+			}
+		}
+		require.Equal(t, []string{"a", "b"}, keys)
+	})
+
+	t.Run("Presign", func(t *testing.T) {
+		ctx, st, prefix := setup(t, newStorage)
+		key := prefix + "presigned.pdf"
+
+		raw, err := st.GeneratePresignedUploadURL(ctx, key, "application/pdf", time.Minute)
+		require.NoError(t, err)
+		requireURL(t, raw)
+
+		raw, err = st.GeneratePresignedDownloadURL(ctx, key, time.Minute)
+		require.NoError(t, err)
+		requireURL(t, raw)
+
+		post, err := st.GeneratePresignedPost(ctx, s3.PostPolicy{Key: key, ContentType: "application/pdf", MaxSize: 1 << 20})
+		require.NoError(t, err)
+		require.NotEmpty(t, post.URL)
+		require.Equal(t, key, post.Fields["key"])
+		require.Equal(t, "application/pdf", post.Fields["Content-Type"])
+		require.True(t, post.Expires.After(time.Now()))
+
+		_, err = st.GeneratePresignedPost(ctx, s3.PostPolicy{Key: key})
+		require.ErrorIs(t, err, s3.ErrS3InvalidInput)
+	})
+}
+
+func setup(t *testing.T, newStorage func(t *testing.T) s3.Storage) (context.Context, s3.Storage, string) {
+	t.Helper()
+	st := newStorage(t)
+	// Уникальный префикс позволяет гонять набор против общего бакета
+	prefix := fmt.Sprintf("s3test/%s/%d/", strings.ReplaceAll(t.Name(), "/", "_"), time.Now().UnixNano())
+	t.Cleanup(func() {
+		keys, err := st.List(context.Background(), prefix)
+		if err == nil && len(keys) > 0 {
+			_ = st.DeleteMultiple(context.Background(), keys)
+		}
+	})
+	return context.Background(), st, prefix
+}
+
+func requireURL(t *testing.T, raw string) {
+	t.Helper()
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+	require.NotEmpty(t, u.Scheme)
+	require.NotEmpty(t, u.RawQuery, "presigned URL must carry its signature or expiry")
+}
@@ -0,0 +1,84 @@
+package s3test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Rasikrr/core/s3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStorage(t *testing.T) {
+	RunStorageTests(t, func(*testing.T) s3.Storage {
+		return s3.NewMemoryStorage("test")
+	})
+}
+
+func TestLocalStorage(t *testing.T) {
+	RunStorageTests(t, func(t *testing.T) s3.Storage {
+		st, err := s3.NewLocalStorage(s3.LocalConfig{Root: t.TempDir(), BaseURL: "http://localhost/files"})
+		require.NoError(t, err)
+		return st
+	})
+}
+
+// TestClient гоняет набор против настоящего S3 или MinIO, если заданы переменные окружения
+func TestClient(t *testing.T) {
+	cfg := s3.Config{
+		Region:          os.Getenv("AWS_REGION"),
+		Endpoint:        os.Getenv("AWS_S3_ENDPOINT"),
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		BucketName:      os.Getenv("AWS_S3_BUCKET_NAME"),
+	}
+	if cfg.BucketName == "" {
+		t.Skip("AWS_S3_BUCKET_NAME is not set")
+	}
+	RunStorageTests(t, func(t *testing.T) s3.Storage {
+		client, err := s3.NewClient(context.Background(), cfg)
+		require.NoError(t, err)
+		return client
+	})
+}
+
+func TestLocalStoragePresignedHandler(t *testing.T) {
+	ctx := context.Background()
+	ts := httptest.NewServer(nil)
+	defer ts.Close()
+
+	st, err := s3.NewLocalStorage(s3.LocalConfig{Root: t.TempDir(), BaseURL: ts.URL + "/files", SigningKey: []byte("secret")})
+	require.NoError(t, err)
+	ts.Config.Handler = http.StripPrefix("/files", st.Handler())
+
+	uploadURL, err := st.GeneratePresignedUploadURL(ctx, "docs/a.txt", "text/plain", time.Minute)
+	require.NoError(t, err)
+
+	put := func(url, contentType string) int {
+		req, err := http.NewRequest(http.MethodPut, url, strings.NewReader("content"))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", contentType)
+		res, err := ts.Client().Do(req)
+		require.NoError(t, err)
+		res.Body.Close()
+		return res.StatusCode
+	}
+	require.Equal(t, http.StatusForbidden, put(uploadURL, "text/html"))
+	require.Equal(t, http.StatusForbidden, put(strings.Replace(uploadURL, "a.txt", "b.txt", 1), "text/plain"))
+	require.Equal(t, http.StatusOK, put(uploadURL, "text/plain"))
+
+	data, err := st.Download(ctx, "docs/a.txt")
+	require.NoError(t, err)
+	require.Equal(t, "content", string(data))
+
+	downloadURL, err := st.GeneratePresignedDownloadURL(ctx, "docs/a.txt", time.Minute)
+	require.NoError(t, err)
+	res, err := ts.Client().Get(downloadURL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+}
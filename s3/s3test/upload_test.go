@@ -0,0 +1,135 @@
+package s3test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	coreHTTP "github.com/Rasikrr/core/http"
+	"github.com/Rasikrr/core/s3"
+	"github.com/stretchr/testify/require"
+)
+
+// newLocalS3 запускает LocalStorage за HTTP сервером - локальную замену S3 для presigned запросов
+func newLocalS3(t *testing.T) (*s3.LocalStorage, *httptest.Server) {
+	t.Helper()
+	ts := httptest.NewServer(nil)
+	t.Cleanup(ts.Close)
+
+	st, err := s3.NewLocalStorage(s3.LocalConfig{Root: t.TempDir(), BaseURL: ts.URL + "/files"})
+	require.NoError(t, err)
+	ts.Config.Handler = http.StripPrefix("/files", st.Handler())
+	return st, ts
+}
+
+func postForm(t *testing.T, client *http.Client, post s3.PresignedPost, contentType, filename string, content []byte) int {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for name, value := range post.Fields {
+		require.NoError(t, mw.WriteField(name, value))
+	}
+	if contentType != "" {
+		require.NoError(t, mw.WriteField("Content-Type", contentType))
+	}
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", `form-data; name="file"; filename="`+filename+`"`)
+	part, err := mw.CreatePart(h)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+
+	res, err := client.Post(post.URL, mw.FormDataContentType(), &body)
+	require.NoError(t, err)
+	res.Body.Close()
+	return res.StatusCode
+}
+
+func TestPresignedPostAndVerify(t *testing.T) {
+	ctx := context.Background()
+	st, ts := newLocalS3(t)
+
+	post, err := st.GeneratePresignedPost(ctx, s3.PostPolicy{
+		KeyPrefix:         "avatars/42/",
+		ContentTypePrefix: "image/",
+		MaxSize:           16,
+		Expires:           time.Minute,
+	})
+	require.NoError(t, err)
+
+	image := []byte("\x89PNG small image")
+	require.Equal(t, http.StatusForbidden, postForm(t, ts.Client(), post, "text/html", "a.png", image))
+	require.Equal(t, http.StatusBadRequest, postForm(t, ts.Client(), post, "image/png", "big.png", bytes.Repeat([]byte("x"), 17)))
+	tampered := post
+	tampered.Fields = map[string]string{}
+	for k, v := range post.Fields {
+		tampered.Fields[k] = v
+	}
+	tampered.Fields["key"] = "other/${filename}"
+	require.Equal(t, http.StatusForbidden, postForm(t, ts.Client(), tampered, "image/png", "a.png", image))
+
+	require.Equal(t, http.StatusNoContent, postForm(t, ts.Client(), post, "image/png", "a.png", image))
+	exists, err := st.Exists(ctx, "avatars/42/big.png")
+	require.NoError(t, err)
+	require.False(t, exists, "rejected upload must not leave an object")
+
+	sum := sha256.Sum256(image)
+	info, err := s3.VerifyUpload(ctx, st, "avatars/42/a.png", s3.UploadExpectation{
+		MaxSize:      16,
+		ContentTypes: []string{"image/"},
+		SHA256:       hex.EncodeToString(sum[:]),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "image/png", info.ContentType)
+
+	_, err = s3.VerifyUpload(ctx, st, "avatars/42/a.png", s3.UploadExpectation{
+		ContentTypes:     []string{"application/pdf"},
+		DeleteOnMismatch: true,
+	})
+	require.ErrorIs(t, err, s3.ErrS3VerificationFailed)
+	_, err = s3.VerifyUpload(ctx, st, "avatars/42/a.png", s3.UploadExpectation{})
+	require.ErrorIs(t, err, s3.ErrS3NotFound)
+}
+
+func TestRedirectHandler(t *testing.T) {
+	ctx := context.Background()
+	st, _ := newLocalS3(t)
+	_, err := st.Upload(ctx, "docs/report.pdf", []byte("%PDF"), "application/pdf")
+	require.NoError(t, err)
+
+	srv := coreHTTP.NewServer(ctx, coreHTTP.Config{Name: "test"})
+	srv.Mount("/download", s3.RedirectHandler(st, s3.RedirectOptions{CheckExists: true}))
+	app := httptest.NewServer(srv.Handler())
+	defer app.Close()
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	res, err := client.Get(app.URL + "/download/docs/report.pdf")
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusFound, res.StatusCode)
+	location := res.Header.Get("Location")
+	require.True(t, strings.Contains(location, "/files/docs/report.pdf?"), location)
+
+	// Редирект ведет на рабочую ссылку
+	res, err = http.Get(location)
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, "application/pdf", res.Header.Get("Content-Type"))
+
+	res, err = client.Get(app.URL + "/download/docs/missing.pdf")
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusNotFound, res.StatusCode)
+}
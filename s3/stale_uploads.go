@@ -0,0 +1,101 @@
+package s3
+
+import (
+	"context"
+	"time"
+
+	"github.com/Rasikrr/core/log"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+const staleUploadsJobName = "s3_stale_uploads_cleanup"
+
+// AbortStaleUploads прерывает незавершенные multipart загрузки старше olderThan во всех бакетах клиента.
+// Части прерванных загрузок хранятся в S3 и оплачиваются, пока загрузка не завершена или не прервана.
+// Возвращает количество прерванных загрузок.
+func (c *Client) AbortStaleUploads(ctx context.Context, olderThan time.Duration) (int, error) {
+	threshold := time.Now().Add(-olderThan)
+	aborted := 0
+	for _, bucket := range c.distinctBuckets() {
+		// Копия клиента, чтобы операции попадали в метрики под своим бакетом
+		bc := *c
+		bc.bucketName = bucket
+		n, err := bc.abortStale(ctx, threshold)
+		aborted += n
+		if err != nil {
+			return aborted, err
+		}
+	}
+	return aborted, nil
+}
+
+func (c *Client) abortStale(ctx context.Context, threshold time.Time) (int, error) {
+	aborted := 0
+	input := &s3.ListMultipartUploadsInput{Bucket: aws.String(c.bucketName)}
+	for {
+		out, err := c.listMultipartUploads(ctx, input)
+		if err != nil {
+			return aborted, err
+		}
+		for _, u := range out.Uploads {
+			if u.Initiated == nil || u.Initiated.After(threshold) {
+				continue
+			}
+			err := c.abortUpload(ctx, c.bucketName, aws.ToString(u.Key), aws.ToString(u.UploadId))
+			if err != nil && !isNoSuchUpload(err) {
+				return aborted, err
+			}
+			aborted++
+		}
+		if !aws.ToBool(out.IsTruncated) {
+			return aborted, nil
+		}
+		input.KeyMarker = out.NextKeyMarker
+		input.UploadIdMarker = out.NextUploadIdMarker
+	}
+}
+
+func (c *Client) listMultipartUploads(ctx context.Context, input *s3.ListMultipartUploadsInput) (_ *s3.ListMultipartUploadsOutput, err error) {
+	ctx, op := c.startOperation(ctx, "ListMultipartUploads", "")
+	defer func() { op.end(err, 0) }()
+
+	return c.multipart.ListMultipartUploads(ctx, input)
+}
+
+// StaleUploadsJob - cron джоба, прерывающая зависшие multipart загрузки
+type StaleUploadsJob struct {
+	client *Client
+	cfg    StaleUploadsConfig
+}
+
+func NewStaleUploadsJob(client *Client, cfg StaleUploadsConfig) *StaleUploadsJob {
+	return &StaleUploadsJob{
+		client: client,
+		cfg:    cfg.withDefaults(),
+	}
+}
+
+func (j *StaleUploadsJob) Name() string {
+	return staleUploadsJobName
+}
+
+func (j *StaleUploadsJob) Schedule() string {
+	return j.cfg.Schedule
+}
+
+func (j *StaleUploadsJob) Run() {
+	j.RunContext(context.Background())
+}
+
+func (j *StaleUploadsJob) RunContext(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, j.cfg.Timeout)
+	defer cancel()
+
+	aborted, err := j.client.AbortStaleUploads(ctx, j.cfg.MaxAge)
+	if err != nil {
+		log.Error(ctx, "s3: failed to abort stale uploads", log.Int("aborted", aborted), log.Err(err))
+		return
+	}
+	log.Info(ctx, "s3: stale uploads aborted", log.Int("aborted", aborted))
+}
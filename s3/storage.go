@@ -0,0 +1,90 @@
+package s3
+
+import (
+	"context"
+	"io"
+	"iter"
+	"time"
+)
+
+// Storage - объектное хранилище. Реализации: Client (S3), LocalStorage (файловая система для разработки)
+// и MemoryStorage (тесты). Все реализации возвращают одинаковые ошибки:
+// ErrS3EmptyKey, ErrS3EmptyData, ErrS3InvalidInput для некорректных аргументов
+// и ErrS3NotFound при обращении к отсутствующему объекту (вместе с ErrS3DownloadFailed, ErrS3HeadFailed и т.д.).
+// Удаление отсутствующего объекта, как и в S3, не является ошибкой.
+type Storage interface {
+	Upload(ctx context.Context, key string, data []byte, contentType string, opts ...UploadOption) (string, error)
+	UploadStream(ctx context.Context, key string, reader io.Reader, contentType string, opts ...UploadOption) (string, error)
+	Download(ctx context.Context, key string) ([]byte, error)
+	DownloadStream(ctx context.Context, key string, writer io.WriterAt) (int64, error)
+	DownloadRange(ctx context.Context, key string, offset, length int64) ([]byte, error)
+	OpenReader(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	Head(ctx context.Context, key string) (ObjectInfo, error)
+	Copy(ctx context.Context, srcKey, dstKey string) error
+	Move(ctx context.Context, srcKey, dstKey string) error
+	GetTags(ctx context.Context, key string) (map[string]string, error)
+	SetTags(ctx context.Context, key string, tags map[string]string) error
+	Delete(ctx context.Context, key string) error
+	DeleteMultiple(ctx context.Context, keys []string) error
+	List(ctx context.Context, prefix string) ([]string, error)
+	ListWithDetails(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	Objects(ctx context.Context, prefix string) iter.Seq2[ObjectInfo, error]
+	Exists(ctx context.Context, key string) (bool, error)
+	GeneratePresignedUploadURL(ctx context.Context, key string, contentType string, expiresIn time.Duration) (string, error)
+	GeneratePresignedDownloadURL(ctx context.Context, key string, expiresIn time.Duration) (string, error)
+	GeneratePresignedPost(ctx context.Context, policy PostPolicy) (PresignedPost, error)
+}
+
+var (
+	_ Storage = (*Client)(nil)
+	_ Storage = (*LocalStorage)(nil)
+	_ Storage = (*MemoryStorage)(nil)
+)
+
+func validateKeys(keys []string) ([]string, error) {
+	if len(keys) == 0 {
+		return nil, errEmptyKeys
+	}
+	valid := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key != "" {
+			valid = append(valid, key)
+		}
+	}
+	if len(valid) == 0 {
+		return nil, errNoValidKeys
+	}
+	return valid, nil
+}
+
+// rangeBounds проверяет диапазон DownloadRange для объекта размера size и возвращает [start, end)
+func rangeBounds(size, offset, length int64) (int64, int64, error) {
+	if offset < 0 || offset >= size {
+		return 0, 0, errInvalidRange
+	}
+	end := size
+	if length > 0 && offset+length < size {
+		end = offset + length
+	}
+	return offset, end, nil
+}
+
+// collect читает итератор Objects до конца
+func collect(objects iter.Seq2[ObjectInfo, error]) ([]ObjectInfo, error) {
+	var result []ObjectInfo
+	for obj, err := range objects {
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, obj)
+	}
+	return result, nil
+}
+
+func keysOf(objects []ObjectInfo) []string {
+	var keys []string
+	for _, obj := range objects {
+		keys = append(keys, obj.Key)
+	}
+	return keys
+}
@@ -0,0 +1,125 @@
+package s3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	coreRedis "github.com/Rasikrr/core/cache/redis"
+)
+
+// ErrUploadStateNotFound возвращается UploadStore, если прогресса загрузки с таким ID нет
+var ErrUploadStateNotFound = errors.New("s3: upload state not found")
+
+// UploadState - прогресс multipart загрузки, достаточный для ее продолжения после рестарта процесса
+type UploadState struct {
+	Bucket    string         `json:"bucket"`
+	Key       string         `json:"key"`
+	UploadID  string         `json:"upload_id"`
+	Size      int64          `json:"size"`
+	PartSize  int64          `json:"part_size"`
+	Checksum  string         `json:"checksum,omitempty"`
+	Parts     []UploadedPart `json:"parts"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// UploadedPart - часть, принятая S3
+type UploadedPart struct {
+	Number   int32  `json:"number"`
+	ETag     string `json:"etag"`
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// UploadStore хранит прогресс возобновляемых загрузок
+type UploadStore interface {
+	// Load возвращает ErrUploadStateNotFound, если состояния нет
+	Load(ctx context.Context, id string) (UploadState, error)
+	Save(ctx context.Context, id string, state UploadState) error
+	Delete(ctx context.Context, id string) error
+}
+
+// MemoryUploadStore хранит прогресс в памяти процесса: загрузка продолжается после ошибки, но не после рестарта
+type MemoryUploadStore struct {
+	mu     sync.Mutex
+	states map[string]UploadState
+}
+
+func NewMemoryUploadStore() *MemoryUploadStore {
+	return &MemoryUploadStore{states: make(map[string]UploadState)}
+}
+
+func (s *MemoryUploadStore) Load(_ context.Context, id string) (UploadState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.states[id]
+	if !ok {
+		return UploadState{}, ErrUploadStateNotFound
+	}
+	state.Parts = append([]UploadedPart(nil), state.Parts...)
+	return state, nil
+}
+
+func (s *MemoryUploadStore) Save(_ context.Context, id string, state UploadState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state.Parts = append([]UploadedPart(nil), state.Parts...)
+	s.states[id] = state
+	return nil
+}
+
+func (s *MemoryUploadStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.states, id)
+	return nil
+}
+
+const redisUploadKeyPrefix = "s3:upload:"
+
+// RedisUploadStore хранит прогресс в Redis в JSON. TTL продлевается при каждом сохранении
+// и не должен быть меньше StaleUploadsConfig.MaxAge: после истечения TTL загрузка начнется заново,
+// а уже принятые части останутся в S3 до очистки.
+type RedisUploadStore struct {
+	client *coreRedis.Client
+	ttl    time.Duration
+}
+
+func NewRedisUploadStore(client *coreRedis.Client, ttl time.Duration) *RedisUploadStore {
+	return &RedisUploadStore{client: client, ttl: ttl}
+}
+
+func (s *RedisUploadStore) Load(ctx context.Context, id string) (UploadState, error) {
+	data, err := s.client.GetBytes(ctx, redisUploadKeyPrefix+id)
+	if err != nil {
+		if errors.Is(err, coreRedis.Nil) {
+			return UploadState{}, ErrUploadStateNotFound
+		}
+		return UploadState{}, fmt.Errorf("s3: load upload state: %w", err)
+	}
+	var state UploadState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return UploadState{}, fmt.Errorf("s3: decode upload state: %w", err)
+	}
+	return state, nil
+}
+
+func (s *RedisUploadStore) Save(ctx context.Context, id string, state UploadState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("s3: encode upload state: %w", err)
+	}
+	if err := s.client.SetWithExpiration(ctx, redisUploadKeyPrefix+id, data, s.ttl); err != nil {
+		return fmt.Errorf("s3: save upload state: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisUploadStore) Delete(ctx context.Context, id string) error {
+	if err := s.client.Delete(ctx, redisUploadKeyPrefix+id); err != nil {
+		return fmt.Errorf("s3: delete upload state: %w", err)
+	}
+	return nil
+}
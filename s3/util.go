@@ -0,0 +1,39 @@
+package s3
+
+import "io"
+
+// countingReader считает прочитанные байты для метрик потоковой загрузки
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// meterReader возвращает тело для загрузки и функцию, сообщающую число переданных байт.
+// Seekable reader не оборачивается: uploader читает такие тела частями без копирования.
+func meterReader(r io.Reader) (io.Reader, func() int64) {
+	if s, ok := r.(io.Seeker); ok {
+		cur, err := s.Seek(0, io.SeekCurrent)
+		if err == nil {
+			end, err := s.Seek(0, io.SeekEnd)
+			if _, serr := s.Seek(cur, io.SeekStart); err == nil && serr == nil {
+				return r, func() int64 { return end - cur }
+			}
+		}
+	}
+	cr := &countingReader{r: r}
+	return cr, func() int64 { return cr.n }
+}
+
+func mapValues(m map[string]string) []string {
+	values := make([]string, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+	return values
+}
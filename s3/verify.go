@@ -0,0 +1,102 @@
+package s3
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// UploadExpectation описывает, каким должен быть объект, загруженный клиентом по presigned URL или форме
+type UploadExpectation struct {
+	// Size - точный размер в байтах. MinSize и MaxSize задают диапазон. 0 - без ограничения.
+	Size    int64
+	MinSize int64
+	MaxSize int64
+	// ContentTypes - допустимые Content-Type. Значение с "/" на конце, например "image/", задает префикс.
+	ContentTypes []string
+	// MD5 - ожидаемый hex MD5, сверяется с ETag без скачивания. У multipart загрузок ETag не является MD5.
+	MD5 string
+	// SHA256 - ожидаемый hex SHA-256. Для проверки объект скачивается целиком.
+	SHA256 string
+	// DeleteOnMismatch удаляет объект, не прошедший проверку, чтобы он не остался в бакете без записи в домене
+	DeleteOnMismatch bool
+}
+
+// VerifyUpload подтверждает, что загрузка состоялась и объект соответствует ожиданиям.
+// Отсутствующий объект возвращает ErrS3NotFound, несоответствие - ErrS3VerificationFailed.
+func VerifyUpload(ctx context.Context, st Storage, key string, exp UploadExpectation) (ObjectInfo, error) {
+	info, err := st.Head(ctx, key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	verr := exp.check(ctx, st, info)
+	if verr == nil {
+		return info, nil
+	}
+	if exp.DeleteOnMismatch && errors.Is(verr, ErrS3VerificationFailed) {
+		if err := st.Delete(ctx, key); err != nil {
+			return info, errors.Join(verr, err)
+		}
+	}
+	return info, verr
+}
+
+func (e UploadExpectation) check(ctx context.Context, st Storage, info ObjectInfo) error {
+	switch {
+	case e.Size > 0 && info.Size != e.Size:
+		return fmt.Errorf("%w: size %d, expected %d", ErrS3VerificationFailed, info.Size, e.Size)
+	case e.MinSize > 0 && info.Size < e.MinSize:
+		return fmt.Errorf("%w: size %d is less than %d", ErrS3VerificationFailed, info.Size, e.MinSize)
+	case e.MaxSize > 0 && info.Size > e.MaxSize:
+		return fmt.Errorf("%w: size %d exceeds %d", ErrS3VerificationFailed, info.Size, e.MaxSize)
+	}
+	if len(e.ContentTypes) > 0 && !contentTypeAllowed(info.ContentType, e.ContentTypes) {
+		return fmt.Errorf("%w: content type %q is not allowed", ErrS3VerificationFailed, info.ContentType)
+	}
+	if e.MD5 != "" && !strings.EqualFold(strings.Trim(info.ETag, `"`), e.MD5) {
+		return fmt.Errorf("%w: etag %s does not match md5 %s", ErrS3VerificationFailed, info.ETag, e.MD5)
+	}
+	if e.SHA256 != "" {
+		sum, err := sha256Of(ctx, st, info.Key)
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(sum, e.SHA256) {
+			return fmt.Errorf("%w: sha256 %s, expected %s", ErrS3VerificationFailed, sum, e.SHA256)
+		}
+	}
+	return nil
+}
+
+func contentTypeAllowed(contentType string, allowed []string) bool {
+	// Параметры вроде "; charset=utf-8" не участвуют в сравнении
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	// Типы MIME не зависят от регистра, как для точных значений, так и для префиксов
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	for _, a := range allowed {
+		a = strings.ToLower(a)
+		if strings.HasSuffix(a, "/") && strings.HasPrefix(mediaType, a) || mediaType == a {
+			return true
+		}
+	}
+	return false
+}
+
+func sha256Of(ctx context.Context, st Storage, key string) (string, error) {
+	r, err := st.OpenReader(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrS3DownloadFailed, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
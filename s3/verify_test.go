@@ -0,0 +1,14 @@
+package s3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentTypeAllowedIgnoresCase(t *testing.T) {
+	require.True(t, contentTypeAllowed("Image/PNG", []string{"image/png"}))
+	require.True(t, contentTypeAllowed("Image/PNG", []string{"image/"}))
+	require.True(t, contentTypeAllowed("image/png; charset=binary", []string{"IMAGE/"}))
+	require.False(t, contentTypeAllowed("application/pdf", []string{"image/"}))
+}
@@ -0,0 +1,372 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Rasikrr/core/enum"
+	"github.com/Rasikrr/core/environment"
+	"github.com/Rasikrr/core/log"
+	"github.com/Rasikrr/core/version"
+)
+
+const (
+	defaultAlertDedupWindow = 10 * time.Minute
+	defaultAlertRateLimit   = 10
+	defaultAlertBatchPeriod = 5 * time.Second
+	defaultAlertBatchSize   = 10
+	alertSendTimeout        = 10 * time.Second
+	maxAlertAttrLength      = 300
+	traceIDAttr             = "trace_id"
+)
+
+// AlertConfig настраивает отправку логов в чат Telegram для сервисов без Sentry
+type AlertConfig struct {
+	Required bool  `yaml:"required"`
+	ChatID   int64 `yaml:"chat_id"`
+	// Level - минимальный уровень записей, которые уходят в чат, по умолчанию error
+	Level *enum.LogLevel `yaml:"level"`
+	// DedupWindow - одинаковые записи (уровень и сообщение) в пределах окна отправляются один раз,
+	// после окна приходит число повторов
+	DedupWindow time.Duration `yaml:"dedup_window" env-default:"10m"`
+	// RateLimit - не больше сообщений в чат в минуту. Лишние записи ждут в очереди или отбрасываются с подсчетом.
+	RateLimit int `yaml:"rate_limit" env-default:"10"`
+	// BatchInterval и BatchSize: записи копятся и уходят одним сообщением раз в интервал или по достижении размера
+	BatchInterval time.Duration `yaml:"batch_interval" env-default:"5s"`
+	BatchSize     int           `yaml:"batch_size" env-default:"10"`
+}
+
+func (c AlertConfig) validate() error {
+	if !c.Required {
+		return nil
+	}
+	if c.ChatID == 0 {
+		return fmt.Errorf("alerts.chat_id is empty: %w", errConfigRequired)
+	}
+	if c.DedupWindow < 0 || c.RateLimit < 0 || c.BatchInterval < 0 || c.BatchSize < 0 {
+		return fmt.Errorf("alerts limits must not be negative: %w", errConfigRequired)
+	}
+	return nil
+}
+
+func (c AlertConfig) withDefaults() AlertConfig {
+	// Нулевое значение enum.LogLevel - debug, поэтому уровень без значения задается указателем
+	if c.Level == nil {
+		level := enum.LogLevelError
+		c.Level = &level
+	}
+	if c.DedupWindow == 0 {
+		c.DedupWindow = defaultAlertDedupWindow
+	}
+	if c.RateLimit == 0 {
+		c.RateLimit = defaultAlertRateLimit
+	}
+	if c.BatchInterval == 0 {
+		c.BatchInterval = defaultAlertBatchPeriod
+	}
+	if c.BatchSize == 0 {
+		c.BatchSize = defaultAlertBatchSize
+	}
+	return c
+}
+
+type alertCtxKey struct{}
+
+// AlertHandler - slog.Handler, который отправляет записи от AlertConfig.Level в чат Telegram.
+// Подключается в log.Init рядом с Sentry handler через slogmulti.Fanout:
+//
+//	alerts := telegram.NewAlertHandler(client, cfg.Telegram.Alerts, cfg.AppName)
+//	log.Init(cfg.Logger, alerts)
+//	defer alerts.Close(ctx)
+//
+// Handle не блокирует: записи копятся в очереди и отправляются в фоне пачками.
+// Логи самой отправки в чат не попадают, чтобы ошибки Telegram не порождали новые алерты.
+type AlertHandler struct {
+	core  *alertCore
+	attrs []slog.Attr
+	group string
+}
+
+type alert struct {
+	time    time.Time
+	level   slog.Level
+	message string
+	attrs   []slog.Attr
+	repeats int
+}
+
+type dedupEntry struct {
+	first   alert
+	seen    time.Time
+	repeats int
+}
+
+type alertCore struct {
+	messenger Messenger
+	cfg       AlertConfig
+	app       string
+
+	records chan alert
+	mu      sync.Mutex
+	dedup   map[string]*dedupEntry
+	dropped atomic.Int64
+	sent    []time.Time
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	now       func() time.Time
+}
+
+func NewAlertHandler(messenger Messenger, cfg AlertConfig, appName string) *AlertHandler {
+	cfg = cfg.withDefaults()
+	core := &alertCore{
+		messenger: messenger,
+		cfg:       cfg,
+		app:       appName,
+		records:   make(chan alert, cfg.BatchSize*10),
+		dedup:     make(map[string]*dedupEntry),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+		now:       time.Now,
+	}
+	go core.run()
+	return &AlertHandler{core: core}
+}
+
+func (h *AlertHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.core.cfg.Level.ToSlogLevel().Level()
+}
+
+func (h *AlertHandler) Handle(ctx context.Context, record slog.Record) error {
+	if ctx.Value(alertCtxKey{}) != nil {
+		return nil
+	}
+
+	a := alert{
+		time:    record.Time,
+		level:   record.Level,
+		message: record.Message,
+		attrs:   make([]slog.Attr, 0, len(h.attrs)+record.NumAttrs()),
+	}
+	a.attrs = append(a.attrs, h.attrs...)
+	record.Attrs(func(attr slog.Attr) bool {
+		if h.group != "" {
+			attr.Key = h.group + "." + attr.Key
+		}
+		a.attrs = append(a.attrs, attr)
+		return true
+	})
+
+	if h.core.deduplicate(&a) {
+		return nil
+	}
+	h.core.enqueue(a)
+	return nil
+}
+
+func (h *AlertHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	prefixed := make([]slog.Attr, 0, len(h.attrs)+len(attrs))
+	prefixed = append(prefixed, h.attrs...)
+	for _, attr := range attrs {
+		if h.group != "" {
+			attr.Key = h.group + "." + attr.Key
+		}
+		prefixed = append(prefixed, attr)
+	}
+	return &AlertHandler{core: h.core, attrs: prefixed, group: h.group}
+}
+
+func (h *AlertHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	group := name
+	if h.group != "" {
+		group = h.group + "." + name
+	}
+	return &AlertHandler{core: h.core, attrs: h.attrs, group: group}
+}
+
+// Close отправляет накопленные записи и останавливает фоновую отправку
+func (h *AlertHandler) Close(ctx context.Context) error {
+	h.core.closeOnce.Do(func() { close(h.core.stop) })
+	select {
+	case <-h.core.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// deduplicate возвращает true, если такая же запись уже отправлена в пределах окна.
+// Для первой записи после окна в a.repeats попадает число подавленных повторов.
+func (c *alertCore) deduplicate(a *alert) bool {
+	key := a.level.String() + "|" + a.message
+	now := c.now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.dedup[key]
+	if ok && now.Sub(entry.seen) < c.cfg.DedupWindow {
+		entry.repeats++
+		return true
+	}
+	if ok {
+		a.repeats = entry.repeats
+	}
+	c.dedup[key] = &dedupEntry{first: *a, seen: now}
+	return false
+}
+
+// expiredRepeats удаляет истекшие записи окна и возвращает сводки по тем, что повторялись
+func (c *alertCore) expiredRepeats() []alert {
+	now := c.now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var summaries []alert
+	for key, entry := range c.dedup {
+		if now.Sub(entry.seen) < c.cfg.DedupWindow {
+			continue
+		}
+		delete(c.dedup, key)
+		if entry.repeats > 0 {
+			summary := entry.first
+			summary.repeats = entry.repeats
+			summaries = append(summaries, summary)
+		}
+	}
+	return summaries
+}
+
+func (c *alertCore) enqueue(a alert) {
+	select {
+	case c.records <- a:
+	default:
+		c.dropped.Add(1)
+	}
+}
+
+func (c *alertCore) run() {
+	defer close(c.done)
+	ticker := time.NewTicker(c.cfg.BatchInterval)
+	defer ticker.Stop()
+
+	var pending []alert
+	for {
+		select {
+		case a := <-c.records:
+			pending = append(pending, a)
+			if len(pending) >= c.cfg.BatchSize {
+				pending = c.flush(pending, false)
+			}
+		case <-ticker.C:
+			pending = append(pending, c.expiredRepeats()...)
+			pending = c.flush(pending, false)
+		case <-c.stop:
+		drain:
+			for {
+				select {
+				case a := <-c.records:
+					pending = append(pending, a)
+				default:
+					break drain
+				}
+			}
+			for len(pending) > 0 {
+				pending = c.flush(pending, true)
+			}
+			return
+		}
+	}
+}
+
+// flush отправляет одним сообщением до BatchSize записей и возвращает оставшиеся.
+// Если лимит сообщений исчерпан, записи ждут следующего тика, а лишние отбрасываются с подсчетом.
+func (c *alertCore) flush(pending []alert, force bool) []alert {
+	if len(pending) == 0 {
+		return pending
+	}
+	if !force && !c.allow() {
+		if limit := c.cfg.BatchSize * 10; len(pending) > limit {
+			c.dropped.Add(int64(len(pending) - limit))
+			pending = pending[len(pending)-limit:]
+		}
+		return pending
+	}
+
+	n := min(len(pending), c.cfg.BatchSize)
+	text := c.format(pending[:n], c.dropped.Swap(0))
+
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), alertCtxKey{}, true), alertSendTimeout)
+	defer cancel()
+	if _, err := c.messenger.SendMessage(ctx, c.cfg.ChatID, text, WithParseMode(ParseModeHTML), WithoutPreview()); err != nil {
+		log.Warn(ctx, "telegram: failed to send log alert", log.Int("alerts", n), log.Err(err))
+	}
+	return pending[n:]
+}
+
+// allow - скользящее окно в минуту для AlertConfig.RateLimit
+func (c *alertCore) allow() bool {
+	now := c.now()
+	i := 0
+	for i < len(c.sent) && now.Sub(c.sent[i]) >= time.Minute {
+		i++
+	}
+	c.sent = c.sent[i:]
+	if len(c.sent) >= c.cfg.RateLimit {
+		return false
+	}
+	c.sent = append(c.sent, now)
+	return true
+}
+
+func (c *alertCore) format(alerts []alert, dropped int64) string {
+	var b strings.Builder
+	b.WriteString("<b>" + EscapeHTML(c.app) + "</b>")
+	if v := version.GetVersion(); v != "" {
+		b.WriteString(" " + EscapeHTML(v))
+	}
+	b.WriteString(" · " + EscapeHTML(environment.GetEnv().String()) + "\n")
+
+	for _, a := range alerts {
+		b.WriteString("\n<b>" + levelName(a.level) + "</b> " + a.time.UTC().Format(time.DateTime) + "\n")
+		b.WriteString(EscapeHTML(a.message) + "\n")
+		for _, attr := range a.attrs {
+			value := truncate(attr.Value.String(), maxAlertAttrLength)
+			if attr.Key == traceIDAttr {
+				b.WriteString(traceIDAttr + ": <code>" + EscapeHTML(value) + "</code>\n")
+				continue
+			}
+			b.WriteString(EscapeHTML(attr.Key) + ": " + EscapeHTML(value) + "\n")
+		}
+		if a.repeats > 0 {
+			fmt.Fprintf(&b, "<i>повторилось еще %d раз за %s</i>\n", a.repeats, c.cfg.DedupWindow)
+		}
+	}
+	if dropped > 0 {
+		fmt.Fprintf(&b, "\n<i>пропущено %d записей из-за лимита</i>\n", dropped)
+	}
+	return b.String()
+}
+
+func levelName(level slog.Level) string {
+	if level >= log.LevelFatal {
+		return log.FatalString
+	}
+	return level.String()
+}
+
+func truncate(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	return string([]rune(s)[:limit]) + "…"
+}
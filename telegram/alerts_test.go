@@ -0,0 +1,112 @@
+package telegram
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Rasikrr/core/enum"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMessenger struct {
+	Messenger
+	mu    sync.Mutex
+	texts []string
+}
+
+func (m *fakeMessenger) SendMessage(_ context.Context, _ int64, text string, _ ...SendOption) (Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.texts = append(m.texts, text)
+	return Message{}, nil
+}
+
+func (m *fakeMessenger) sent() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.texts...)
+}
+
+func TestAlertHandler(t *testing.T) {
+	messenger := &fakeMessenger{}
+	level := enum.LogLevelError
+	h := NewAlertHandler(messenger, AlertConfig{
+		ChatID:        1,
+		Level:         &level,
+		BatchInterval: time.Hour,
+		BatchSize:     10,
+	}, "orders")
+	ctx := context.Background()
+
+	require.False(t, h.Enabled(ctx, slog.LevelWarn))
+	require.True(t, h.Enabled(ctx, slog.LevelError))
+
+	logger := slog.New(h).With("system", "postgres")
+	for range 3 {
+		logger.ErrorContext(ctx, "query failed", "table", "orders")
+	}
+	logger.ErrorContext(ctx, "<b>broken</b>")
+	logger.ErrorContext(context.WithValue(ctx, alertCtxKey{}, true), "send failed")
+
+	require.NoError(t, h.Close(ctx))
+	sent := messenger.sent()
+	require.Len(t, sent, 1, "records are batched into one message")
+	require.Contains(t, sent[0], "<b>orders</b>")
+	require.Contains(t, sent[0], "system: postgres")
+	require.Contains(t, sent[0], "table: orders")
+	require.Contains(t, sent[0], "&lt;b&gt;broken&lt;/b&gt;")
+	require.Equal(t, 1, strings.Count(sent[0], "query failed"), "repeats within the window are suppressed")
+	require.NotContains(t, sent[0], "send failed", "records of the alert delivery itself are skipped")
+}
+
+func TestAlertHandlerDefaultLevel(t *testing.T) {
+	h := NewAlertHandler(&fakeMessenger{}, AlertConfig{ChatID: 1}, "orders")
+	ctx := context.Background()
+	defer h.Close(ctx)
+
+	require.False(t, h.Enabled(ctx, slog.LevelInfo), "config built in code without level sends only errors")
+	require.False(t, h.Enabled(ctx, slog.LevelWarn))
+	require.True(t, h.Enabled(ctx, slog.LevelError))
+
+	debug := enum.LogLevelDebug
+	h = NewAlertHandler(&fakeMessenger{}, AlertConfig{ChatID: 1, Level: &debug}, "orders")
+	defer h.Close(ctx)
+	require.True(t, h.Enabled(ctx, slog.LevelDebug), "explicit debug is kept")
+}
+
+func TestAlertHandlerDedupSummary(t *testing.T) {
+	now := time.Unix(0, 0)
+	core := &alertCore{cfg: AlertConfig{DedupWindow: time.Minute}, dedup: make(map[string]*dedupEntry)}
+	core.now = func() time.Time { return now }
+
+	first := alert{level: slog.LevelError, message: "timeout"}
+	require.False(t, core.deduplicate(&first))
+	for range 2 {
+		repeat := first
+		require.True(t, core.deduplicate(&repeat))
+	}
+	require.Empty(t, core.expiredRepeats())
+
+	now = now.Add(time.Minute)
+	summaries := core.expiredRepeats()
+	require.Len(t, summaries, 1)
+	require.Equal(t, 2, summaries[0].repeats)
+	require.Empty(t, core.dedup)
+}
+
+func TestAlertHandlerRateLimit(t *testing.T) {
+	now := time.Unix(0, 0)
+	core := &alertCore{cfg: AlertConfig{RateLimit: 2}}
+	core.now = func() time.Time { return now }
+
+	require.True(t, core.allow())
+	require.True(t, core.allow())
+	require.False(t, core.allow())
+
+	now = now.Add(time.Minute)
+	require.True(t, core.allow())
+}
@@ -2,31 +2,57 @@ package telegram
 
 import (
 	"context"
+	"net/http"
+
 	"github.com/Rasikrr/core/log"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"golang.org/x/sync/semaphore"
 )
 
 type Bot interface {
+	Messenger
 	Start(ctx context.Context) error
 	Close(ctx context.Context) error
-	SendMessage(chatID int64, text string) error
-	SendKeyboard(chatID int64, text string, buttons KeyBoard) error
+	SendKeyboard(ctx context.Context, chatID int64, text string, buttons KeyBoard) error
+	// AnswerCallback подтверждает нажатие inline кнопки, text (может быть пустым) показывается уведомлением
+	AnswerCallback(ctx context.Context, callbackID, text string) error
 	SetMessageHandler(handler func(update tgbotapi.Update))
+	// SetRouter направляет все обновления, включая callback query, в router вместо обработчика сообщений
+	SetRouter(router *Router)
+	// WebhookHandler принимает обновления в режиме webhook. Монтируется на http.Server по WebhookConfig.RoutePath.
+	WebhookHandler() http.Handler
 }
 
 type bot struct {
+	*messenger
 	sem     *semaphore.Weighted
 	client  *tgbotapi.BotAPI
 	handler func(update tgbotapi.Update)
+	router  *Router
+	webhook WebhookConfig
+
+	// ctx - контекст Start, в нем обрабатываются обновления из webhook. started закрывается после его установки.
+	ctx     context.Context
+	started chan struct{}
 }
 
 func NewBot(token string, maxUserConcurrency int) (Bot, error) {
-	client, err := tgbotapi.NewBotAPI(token)
+	return newBot(Config{Token: token, MaxConcurrency: maxUserConcurrency})
+}
+
+// NewBotWithConfig создает бота, который получает обновления через webhook, если задан cfg.Webhook.URL,
+// иначе через long polling
+func NewBotWithConfig(cfg Config, opts ...Option) (Bot, error) {
+	return newBot(cfg, opts...)
+}
+
+func newBot(cfg Config, opts ...Option) (*bot, error) {
+	client, err := tgbotapi.NewBotAPI(cfg.Token)
 	if err != nil {
 		return nil, err
 	}
 	ctx := context.Background()
+	maxUserConcurrency := cfg.MaxConcurrency
 
 	var sem *semaphore.Weighted
 	if maxUserConcurrency > 0 {
@@ -36,9 +62,13 @@ func NewBot(token string, maxUserConcurrency int) (Bot, error) {
 		log.Warnf(ctx, "Telegram bot initialized with unlimited user concurrency")
 	}
 
+	o := newOptions(opts, cfg.Sender)
 	return &bot{
-		sem:    sem,
-		client: client,
+		messenger: &messenger{api: client, sender: o.sender},
+		sem:       sem,
+		client:    client,
+		webhook:   cfg.Webhook,
+		started:   make(chan struct{}),
 	}, nil
 }
 
@@ -46,29 +76,32 @@ func (b *bot) SetMessageHandler(handler func(update tgbotapi.Update)) {
 	b.handler = handler
 }
 
-func (b *bot) SendMessage(chatID int64, text string) error {
-	msg := tgbotapi.NewMessage(chatID, text)
-	_, err := b.client.Send(msg)
+func (b *bot) SetRouter(router *Router) {
+	router.username = b.client.Self.UserName
+	b.router = router
+}
+
+func (b *bot) SendKeyboard(ctx context.Context, chatID int64, text string, buttons KeyBoard) error {
+	_, err := b.SendMessage(ctx, chatID, text, WithReplyKeyboard(buttons))
 	return err
 }
 
-func (b *bot) SendKeyboard(chatID int64, text string, buttons KeyBoard) error {
-	keyboard := make([][]tgbotapi.KeyboardButton, len(buttons))
-	for i, row := range buttons {
-		btnRow := make([]tgbotapi.KeyboardButton, len(row))
-		for j, label := range row {
-			btnRow[j] = tgbotapi.NewKeyboardButton(label)
-		}
-		keyboard[i] = btnRow
-	}
-	msg := tgbotapi.NewMessage(chatID, text)
-	msg.ReplyMarkup = tgbotapi.NewReplyKeyboard(keyboard...)
-	_, err := b.client.Send(msg)
+func (b *bot) AnswerCallback(ctx context.Context, callbackID, text string) error {
+	// Ответ на нажатие не считается сообщением и не ограничивается Sender
+	_, err := call(ctx, func() (*tgbotapi.APIResponse, error) {
+		return b.client.Request(tgbotapi.NewCallback(callbackID, text))
+	})
 	return err
 }
 
-// nolint: gocognit
 func (b *bot) Start(ctx context.Context) error {
+	b.ctx = ctx
+	close(b.started)
+
+	if b.webhook.Enabled() {
+		return b.startWebhook(ctx)
+	}
+
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = 60
 	updates := b.client.GetUpdatesChan(u)
@@ -81,38 +114,79 @@ func (b *bot) Start(ctx context.Context) error {
 			if !ok {
 				return nil
 			}
-			if update.Message != nil {
-				if b.handler == nil {
-					log.Warn(ctx,
-						"No handler for message",
-						log.Any("chat_id", update.Message.Chat.ID),
-						log.String("bot_name", b.client.Self.UserName),
-					)
-					continue
+			b.dispatch(ctx, update)
+		}
+	}
+}
+
+// startWebhook регистрирует webhook и ждет остановки: обновления приходят в WebhookHandler
+func (b *bot) startWebhook(ctx context.Context) error {
+	if err := b.setWebhook(); err != nil {
+		return err
+	}
+	log.Info(ctx, "Telegram webhook set", log.String("bot_name", b.client.Self.UserName), log.String("path", b.webhook.RoutePath()))
+	<-ctx.Done()
+	return nil
+}
+
+// dispatch обрабатывает обновление в отдельной горутине. Семафор ограничивает число одновременных обработчиков.
+func (b *bot) dispatch(ctx context.Context, update Update) {
+	handle := b.handlerFor(ctx, update)
+	if handle == nil {
+		return
+	}
+
+	go func() {
+		if b.sem != nil {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+				err := b.sem.Acquire(ctx, 1)
+				if err != nil {
+					log.Error(ctx, "Failed to acquire semaphore", log.Err(err))
+					return
 				}
-				go func() {
-					if b.sem != nil {
-						select {
-						case <-ctx.Done():
-							return
-						default:
-							err := b.sem.Acquire(ctx, 1)
-							if err != nil {
-								log.Error(ctx, "Failed to acquire semaphore", log.Err(err))
-								return
-							}
-							defer b.sem.Release(1)
-						}
-					}
-					b.handler(update)
-				}()
+				defer b.sem.Release(1)
+			}
+		}
+		handle(updateContext(ctx, update))
+	}()
+}
+
+func (b *bot) handlerFor(ctx context.Context, update Update) func(ctx context.Context) {
+	if b.router != nil {
+		return func(ctx context.Context) {
+			if err := b.router.Handle(ctx, update); err != nil {
+				log.Error(ctx, "handle telegram update error", log.Int("update_id", update.UpdateID), log.Err(err))
 			}
 		}
 	}
+	if update.Message == nil {
+		return nil
+	}
+	if b.handler == nil {
+		log.Warn(ctx,
+			"No handler for message",
+			log.Any("chat_id", update.Message.Chat.ID),
+			log.String("bot_name", b.client.Self.UserName),
+		)
+		return nil
+	}
+	return func(context.Context) {
+		b.handler(update)
+	}
 }
 
 func (b *bot) Close(ctx context.Context) error {
-	b.client.StopReceivingUpdates()
+	switch {
+	case !b.webhook.Enabled():
+		b.client.StopReceivingUpdates()
+	case b.webhook.DeleteOnClose:
+		if err := b.deleteWebhook(); err != nil {
+			return err
+		}
+	}
 	log.Info(ctx, "Telegram bot closed", log.String("bot_name", b.client.Self.UserName))
 	return nil
 }
@@ -7,34 +7,38 @@ import (
 )
 
 type Client interface {
-	SendText(ctx context.Context, chatID int64, text string) error
+	Messenger
+	SendText(ctx context.Context, chatID int64, text string, opts ...SendOption) error
 	SendFile(ctx context.Context, chatID int64, file io.Reader, fileName, caption string) error
 }
 
 type client struct {
-	bot *tgbotapi.BotAPI
+	*messenger
 }
 
-func NewTelegramClient(token string) (Client, error) {
+// NewTelegramClient создает клиент только для отправки сообщений. Если тем же токеном пользуется Bot,
+// передайте общий Sender через WithSender, иначе лимиты будут считаться раздельно.
+func NewTelegramClient(token string, opts ...Option) (Client, error) {
 	bot, err := tgbotapi.NewBotAPI(token)
 	if err != nil {
 		return nil, err
 	}
-	return &client{bot: bot}, nil
+	o := newOptions(opts, SenderConfig{})
+	return &client{messenger: &messenger{api: bot, sender: o.sender}}, nil
 }
 
-func (c *client) SendText(_ context.Context, chatID int64, text string) error {
-	msg := tgbotapi.NewMessage(chatID, text)
-	_, err := c.bot.Send(msg)
+func (c *client) SendText(ctx context.Context, chatID int64, text string, opts ...SendOption) error {
+	_, err := c.SendMessage(ctx, chatID, text, opts...)
 	return err
 }
 
-func (c *client) SendFile(_ context.Context, chatID int64, file io.Reader, fileName string, caption string) error {
-	doc := tgbotapi.NewDocument(chatID, tgbotapi.FileReader{
-		Name:   fileName,
-		Reader: file,
-	})
+func (c *client) SendFile(ctx context.Context, chatID int64, file io.Reader, fileName string, caption string) error {
+	data, err := InputFile{Name: fileName, Reader: file}.requestData()
+	if err != nil {
+		return err
+	}
+	doc := tgbotapi.NewDocument(chatID, data)
 	doc.Caption = caption
-	_, err := c.bot.Send(doc)
+	_, err = c.send(ctx, chatID, doc)
 	return err
 }
@@ -0,0 +1,100 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+var (
+	errConfigRequired = errors.New("telegram config error")
+
+	// secretTokenPattern - допустимые символы X-Telegram-Bot-Api-Secret-Token по документации Bot API
+	secretTokenPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+)
+
+// Config содержит параметры Telegram бота. Токены задаются только через переменные окружения.
+type Config struct {
+	Required bool   `yaml:"required"`
+	Token    string `yaml:"-" env:"TELEGRAM_BOT_TOKEN"`
+	// MaxConcurrency ограничивает число одновременно обрабатываемых обновлений, 0 - без ограничения
+	MaxConcurrency int           `yaml:"max_concurrency"`
+	Webhook        WebhookConfig `yaml:"webhook"`
+	Sender         SenderConfig  `yaml:"sender"`
+	// Alerts отправляет ошибки из логов в чат. Работает и без Required: боту не нужно получать обновления.
+	Alerts AlertConfig `yaml:"alerts"`
+}
+
+// WebhookConfig включает получение обновлений через webhook вместо long polling.
+// Webhook позволяет нескольким подам обслуживать один токен: long polling в таком случае конфликтует.
+type WebhookConfig struct {
+	// URL - публичный https адрес, на который Telegram отправляет обновления. Пустое значение - long polling.
+	URL string `yaml:"url"`
+	// Path - маршрут на http.Server, по умолчанию путь из URL. Нужен, если прокси переписывает путь.
+	Path        string `yaml:"path"`
+	SecretToken string `yaml:"-" env:"TELEGRAM_WEBHOOK_SECRET"`
+	// MaxConnections - число одновременных соединений от Telegram (1-100), по умолчанию 40
+	MaxConnections     int      `yaml:"max_connections"`
+	AllowedUpdates     []string `yaml:"allowed_updates"`
+	DropPendingUpdates bool     `yaml:"drop_pending_updates"`
+	// DeleteOnClose снимает webhook при остановке. При нескольких подах оставьте false,
+	// иначе остановка одного пода отключит бота для всех.
+	DeleteOnClose bool `yaml:"delete_on_close"`
+}
+
+func (c Config) Validate() error {
+	if !c.Required && !c.Alerts.Required {
+		return nil
+	}
+	if c.Token == "" {
+		return fmt.Errorf("token is empty: %w", errConfigRequired)
+	}
+	if c.MaxConcurrency < 0 {
+		return fmt.Errorf("max_concurrency is negative: %w", errConfigRequired)
+	}
+	if err := c.Sender.validate(); err != nil {
+		return err
+	}
+	if err := c.Alerts.validate(); err != nil {
+		return err
+	}
+	return c.Webhook.validate()
+}
+
+// Enabled сообщает, что бот работает через webhook
+func (c WebhookConfig) Enabled() bool {
+	return c.URL != ""
+}
+
+func (c WebhookConfig) validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	u, err := url.Parse(c.URL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("webhook.url must be an absolute https url: %w", errConfigRequired)
+	}
+	if !secretTokenPattern.MatchString(c.SecretToken) {
+		return fmt.Errorf("webhook secret token must be 1-256 characters A-Z, a-z, 0-9, _ or -: %w", errConfigRequired)
+	}
+	if c.RoutePath() == "/" {
+		return fmt.Errorf("webhook path must not be the server root: %w", errConfigRequired)
+	}
+	if c.MaxConnections < 0 || c.MaxConnections > 100 {
+		return fmt.Errorf("webhook.max_connections must be between 1 and 100: %w", errConfigRequired)
+	}
+	return nil
+}
+
+// RoutePath возвращает маршрут, на котором http.Server принимает обновления
+func (c WebhookConfig) RoutePath() string {
+	if c.Path != "" {
+		return c.Path
+	}
+	u, err := url.Parse(c.URL)
+	if err != nil || u.Path == "" {
+		return "/"
+	}
+	return u.Path
+}
@@ -0,0 +1,55 @@
+package telegram
+
+import (
+	"context"
+	"strconv"
+
+	coreCtx "github.com/Rasikrr/core/context"
+)
+
+type ctxKey string
+
+const (
+	ctxKeyChatID  ctxKey = "telegram_chat_id"
+	ctxKeyRoute   ctxKey = "telegram_route"
+	ctxKeyMatches ctxKey = "telegram_matches"
+)
+
+// updateContext добавляет в ctx chat ID и user ID отправителя обновления
+func updateContext(ctx context.Context, update Update) context.Context {
+	if chat := update.FromChat(); chat != nil {
+		ctx = context.WithValue(ctx, ctxKeyChatID, chat.ID)
+	}
+	if user := update.SentFrom(); user != nil {
+		ctx = coreCtx.WithUserID(ctx, strconv.FormatInt(user.ID, 10))
+	}
+	return ctx
+}
+
+// ChatID возвращает ID чата, из которого пришло обновление
+func ChatID(ctx context.Context) (int64, bool) {
+	v, ok := ctx.Value(ctxKeyChatID).(int64)
+	return v, ok
+}
+
+// UserID возвращает Telegram ID отправителя обновления
+func UserID(ctx context.Context) (int64, bool) {
+	v, ok := coreCtx.UserID(ctx)
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(v, 10, 64)
+	return id, err == nil
+}
+
+// Route возвращает маршрут, выбранный роутером: "/start", "callback:buy", "regex:^\d+$" или "fallback"
+func Route(ctx context.Context) string {
+	v, _ := ctx.Value(ctxKeyRoute).(string)
+	return v
+}
+
+// Matches возвращает группы регулярного выражения для обработчика, зарегистрированного через Router.Regex
+func Matches(ctx context.Context) []string {
+	v, _ := ctx.Value(ctxKeyMatches).([]string)
+	return v
+}
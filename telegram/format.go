@@ -0,0 +1,82 @@
+package telegram
+
+import (
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParseMode - режим разметки текста и подписей
+type ParseMode string
+
+const (
+	ParseModeMarkdownV2 ParseMode = "MarkdownV2"
+	ParseModeHTML       ParseMode = "HTML"
+
+	// MaxMessageLength - лимит Telegram на длину текста сообщения
+	MaxMessageLength = 4096
+	// MaxCaptionLength - лимит Telegram на длину подписи к медиа
+	MaxCaptionLength = 1024
+)
+
+// markdownV2Special - символы, которые в MarkdownV2 нужно экранировать вне разметки
+const markdownV2Special = "_*[]()~`>#+-=|{}.!\\"
+
+// EscapeMarkdownV2 экранирует пользовательский текст для вставки в сообщение с ParseModeMarkdownV2
+func EscapeMarkdownV2(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if strings.ContainsRune(markdownV2Special, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// EscapeHTML экранирует пользовательский текст для вставки в сообщение с ParseModeHTML
+func EscapeHTML(s string) string {
+	return html.EscapeString(s)
+}
+
+// SplitText делит текст на части не длиннее limit символов. Разрез делается по последнему переводу строки,
+// иначе по пробелу, иначе по границе символа. Разрез не попадает сразу после обратного слеша,
+// чтобы не разорвать экранирование MarkdownV2. Разметку, которая переходит через разрез, делит вызывающий.
+func SplitText(text string, limit int) []string {
+	if utf8.RuneCountInString(text) <= limit {
+		return []string{text}
+	}
+
+	var parts []string
+	for utf8.RuneCountInString(text) > limit {
+		cut := cutIndex(text, limit)
+		parts = append(parts, strings.TrimRight(text[:cut], "\n "))
+		text = strings.TrimLeft(text[cut:], "\n ")
+	}
+	if text = strings.TrimRight(text, "\n "); text != "" {
+		parts = append(parts, text)
+	}
+	return parts
+}
+
+// cutIndex возвращает байтовый индекс разреза в пределах первых limit символов text
+func cutIndex(text string, limit int) int {
+	end := 0
+	for i := 0; i < limit; i++ {
+		_, size := utf8.DecodeRuneInString(text[end:])
+		end += size
+	}
+	window := text[:end]
+
+	if i := strings.LastIndexByte(window, '\n'); i > 0 {
+		return i + 1
+	}
+	if i := strings.LastIndexByte(window, ' '); i > 0 {
+		return i + 1
+	}
+	for end > 1 && window[end-1] == '\\' {
+		end--
+	}
+	return end
+}
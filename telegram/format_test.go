@@ -0,0 +1,50 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscape(t *testing.T) {
+	require.Equal(t, `price 1\.5 \* 2 \(\~3\)\!`, EscapeMarkdownV2("price 1.5 * 2 (~3)!"))
+	require.Equal(t, `C:\\path`, EscapeMarkdownV2(`C:\path`))
+	require.Equal(t, "&lt;b&gt;Tom &amp; Jerry&lt;/b&gt;", EscapeHTML("<b>Tom & Jerry</b>"))
+}
+
+func TestSplitText(t *testing.T) {
+	require.Equal(t, []string{"short"}, SplitText("short", MaxMessageLength))
+
+	line := strings.Repeat("я", 3000)
+	parts := SplitText(line+"\n"+line, MaxMessageLength)
+	require.Equal(t, []string{line, line}, parts, "split prefers line breaks")
+
+	parts = SplitText(strings.Repeat("ab ", 10), 7)
+	require.Equal(t, []string{"ab ab", "ab ab", "ab ab", "ab ab", "ab ab"}, parts)
+
+	parts = SplitText(strings.Repeat(`x\.`, 5), 5)
+	for _, part := range parts {
+		require.LessOrEqual(t, utf8.RuneCountInString(part), 5)
+		require.False(t, strings.HasSuffix(part, `\`), "escape sequence must not be cut: %q", part)
+	}
+	require.Equal(t, strings.Repeat(`x\.`, 5), strings.Join(parts, ""))
+}
+
+func TestCallbackData(t *testing.T) {
+	data, err := EncodeCallbackData("buy", CallbackInt(1234567890), "eur")
+	require.NoError(t, err)
+	require.Equal(t, "buy:kf12oi:eur", data)
+
+	action, args := DecodeCallbackData(data)
+	require.Equal(t, "buy", action)
+	id, err := ParseCallbackInt(args[0])
+	require.NoError(t, err)
+	require.Equal(t, int64(1234567890), id)
+
+	_, err = EncodeCallbackData("buy", strings.Repeat("x", 64))
+	require.ErrorIs(t, err, ErrCallbackDataTooLong)
+	_, err = EncodeCallbackData("buy", "a:b")
+	require.Error(t, err)
+}
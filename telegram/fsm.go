@@ -0,0 +1,216 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/Rasikrr/core/log"
+)
+
+const (
+	defaultCancelCommand = "cancel"
+
+	ctxKeyConversation ctxKey = "telegram_conversation"
+)
+
+// State - шаг диалога. Пустое значение - диалога нет.
+type State string
+
+// FSM ведет многошаговые диалоги: анкеты, подтверждения и т.п. Обработчики регистрируются на состояния,
+// состояние и данные чата хранятся в SessionStore. Пока в чате идет диалог, обновления получает обработчик
+// текущего состояния, а не маршрут роутера. Если для состояния обработчика нет, обновление идет в роутер.
+//
+// Использование:
+//
+//	fsm := telegram.NewFSM(telegram.NewRedisSessionStore(redis, time.Hour), telegram.WithFSMTimeout(10*time.Minute, expired))
+//	fsm.On("ask_name", func(ctx context.Context, update telegram.Update) error {
+//		conv := telegram.ConversationFrom(ctx)
+//		conv.Set("name", update.Message.Text)
+//		conv.Transition("ask_age")
+//		return nil
+//	})
+//	router.Use(fsm.Middleware())
+//	router.Command("register", func(ctx context.Context, update telegram.Update) error {
+//		telegram.ConversationFrom(ctx).Transition("ask_name")
+//		return nil
+//	})
+type FSM struct {
+	store         SessionStore
+	handlers      map[State]HandlerFunc
+	timeout       time.Duration
+	onTimeout     HandlerFunc
+	cancelCommand string
+	onCancel      HandlerFunc
+	now           func() time.Time
+}
+
+type FSMOption func(*FSM)
+
+// WithFSMTimeout завершает диалог, если в чате не было обновлений дольше timeout.
+// onTimeout (может быть nil) вызывается с пустым диалогом и может перевести его в новое состояние,
+// после чего обновление обрабатывается как обычно.
+func WithFSMTimeout(timeout time.Duration, onTimeout HandlerFunc) FSMOption {
+	return func(f *FSM) {
+		f.timeout = timeout
+		f.onTimeout = onTimeout
+	}
+}
+
+// WithCancelCommand меняет команду отмены диалога (по умолчанию /cancel) и задает обработчик отмены
+func WithCancelCommand(command string, onCancel HandlerFunc) FSMOption {
+	return func(f *FSM) {
+		f.cancelCommand = strings.TrimPrefix(command, "/")
+		f.onCancel = onCancel
+	}
+}
+
+func NewFSM(store SessionStore, opts ...FSMOption) *FSM {
+	f := &FSM{
+		store:         store,
+		handlers:      make(map[State]HandlerFunc),
+		cancelCommand: defaultCancelCommand,
+		now:           time.Now,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// On регистрирует обработчик обновлений в состоянии state
+func (f *FSM) On(state State, handler HandlerFunc, middlewares ...Middleware) {
+	f.handlers[state] = chain(handler, middlewares)
+}
+
+// Middleware загружает диалог чата в ctx (ConversationFrom), направляет обновление в обработчик состояния
+// и сохраняет изменения диалога после успешной обработки.
+// Обновления одного чата лучше обрабатывать последовательно: параллельные обработчики перезапишут данные друг друга.
+func (f *FSM) Middleware() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, update Update) error {
+			chatID, ok := ChatID(ctx)
+			if !ok {
+				return next(ctx, update)
+			}
+
+			conv, err := f.load(ctx, chatID)
+			if err != nil {
+				return err
+			}
+			ctx = context.WithValue(ctx, ctxKeyConversation, conv)
+
+			if conv.expired(f.timeout, f.now()) {
+				log.Debug(ctx, "telegram conversation timed out", log.String("state", string(conv.session.State)))
+				conv.Finish()
+				if f.onTimeout != nil {
+					if err := f.onTimeout(ctx, update); err != nil {
+						return err
+					}
+				}
+			}
+
+			err = f.handle(ctx, conv, next, update)
+			if err != nil {
+				return err
+			}
+			return f.save(ctx, conv)
+		}
+	}
+}
+
+func (f *FSM) handle(ctx context.Context, conv *Conversation, next HandlerFunc, update Update) error {
+	if conv.State() == "" {
+		return next(ctx, update)
+	}
+	if isCommand(update, f.cancelCommand) {
+		conv.Finish()
+		if f.onCancel != nil {
+			return f.onCancel(ctx, update)
+		}
+		return nil
+	}
+	handler, ok := f.handlers[conv.State()]
+	if !ok {
+		return next(ctx, update)
+	}
+	return handler(ctx, update)
+}
+
+func (f *FSM) load(ctx context.Context, chatID int64) (*Conversation, error) {
+	session, err := f.store.Load(ctx, chatID)
+	if err != nil && !errors.Is(err, ErrSessionNotFound) {
+		return nil, err
+	}
+	return &Conversation{chatID: chatID, session: session}, nil
+}
+
+// save сохраняет диалог после каждого обновления, пока он активен: таймаут WithFSMTimeout и TTL хранилища
+// отсчитываются от последнего обновления в чате, а не от последней смены состояния
+func (f *FSM) save(ctx context.Context, conv *Conversation) error {
+	if conv.session.State == "" {
+		if !conv.changed {
+			return nil
+		}
+		return f.store.Delete(ctx, conv.chatID)
+	}
+	conv.session.UpdatedAt = f.now()
+	return f.store.Save(ctx, conv.chatID, conv.session)
+}
+
+// Conversation - диалог текущего чата. Изменения сохраняются после успешного выхода из обработчика.
+type Conversation struct {
+	chatID  int64
+	session Session
+	changed bool
+}
+
+// ConversationFrom возвращает диалог чата из ctx. nil, если FSM.Middleware не подключен или у обновления нет чата.
+func ConversationFrom(ctx context.Context) *Conversation {
+	v, _ := ctx.Value(ctxKeyConversation).(*Conversation)
+	return v
+}
+
+func (c *Conversation) State() State {
+	return c.session.State
+}
+
+// Transition переводит диалог в состояние state. Следующее обновление чата получит обработчик этого состояния.
+func (c *Conversation) Transition(state State) {
+	c.session.State = state
+	c.changed = true
+}
+
+// Get возвращает значение, сохраненное на предыдущих шагах диалога
+func (c *Conversation) Get(key string) string {
+	return c.session.Data[key]
+}
+
+func (c *Conversation) Set(key, value string) {
+	if c.session.Data == nil {
+		c.session.Data = make(map[string]string)
+	}
+	c.session.Data[key] = value
+	c.changed = true
+}
+
+// Data возвращает копию всех данных диалога
+func (c *Conversation) Data() map[string]string {
+	return maps.Clone(c.session.Data)
+}
+
+// Finish завершает диалог и удаляет его данные
+func (c *Conversation) Finish() {
+	c.session = Session{}
+	c.changed = true
+}
+
+func (c *Conversation) expired(timeout time.Duration, now time.Time) bool {
+	return timeout > 0 && c.session.State != "" && now.Sub(c.session.UpdatedAt) > timeout
+}
+
+func isCommand(update Update, command string) bool {
+	return update.Message != nil && update.Message.IsCommand() && update.Message.Command() == command
+}
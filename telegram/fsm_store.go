@@ -0,0 +1,134 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+	"sync"
+	"time"
+
+	coreRedis "github.com/Rasikrr/core/cache/redis"
+)
+
+// ErrSessionNotFound возвращается SessionStore, если у чата нет активного диалога
+var ErrSessionNotFound = errors.New("telegram: session not found")
+
+// Session - состояние диалога в чате
+type Session struct {
+	State     State             `json:"state"`
+	Data      map[string]string `json:"data,omitempty"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// SessionStore хранит состояния диалогов по chat ID
+type SessionStore interface {
+	// Load возвращает ErrSessionNotFound, если диалога нет или он истек
+	Load(ctx context.Context, chatID int64) (Session, error)
+	Save(ctx context.Context, chatID int64, session Session) error
+	Delete(ctx context.Context, chatID int64) error
+}
+
+// MemorySessionStore хранит диалоги в памяти процесса. Подходит для тестов и одного инстанса бота.
+type MemorySessionStore struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	sessions map[int64]memorySession
+}
+
+type memorySession struct {
+	session   Session
+	expiresAt time.Time
+}
+
+// NewMemorySessionStore создает хранилище, в котором диалог живет ttl после последнего сохранения. 0 - бессрочно.
+func NewMemorySessionStore(ttl time.Duration) *MemorySessionStore {
+	return &MemorySessionStore{ttl: ttl, sessions: make(map[int64]memorySession)}
+}
+
+func (s *MemorySessionStore) Load(_ context.Context, chatID int64) (Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.sessions[chatID]
+	if !ok {
+		return Session{}, ErrSessionNotFound
+	}
+	if !stored.expiresAt.IsZero() && time.Now().After(stored.expiresAt) {
+		delete(s.sessions, chatID)
+		return Session{}, ErrSessionNotFound
+	}
+	session := stored.session
+	session.Data = maps.Clone(session.Data)
+	return session, nil
+}
+
+func (s *MemorySessionStore) Save(_ context.Context, chatID int64, session Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session.Data = maps.Clone(session.Data)
+	stored := memorySession{session: session}
+	if s.ttl > 0 {
+		stored.expiresAt = time.Now().Add(s.ttl)
+	}
+	s.sessions[chatID] = stored
+	return nil
+}
+
+func (s *MemorySessionStore) Delete(_ context.Context, chatID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, chatID)
+	return nil
+}
+
+const redisSessionKeyPrefix = "telegram:fsm:"
+
+// RedisSessionStore хранит диалоги в Redis в JSON, поэтому они переживают рестарт и доступны всем подам.
+// TTL продлевается при каждом сохранении.
+type RedisSessionStore struct {
+	client *coreRedis.Client
+	ttl    time.Duration
+}
+
+func NewRedisSessionStore(client *coreRedis.Client, ttl time.Duration) *RedisSessionStore {
+	return &RedisSessionStore{client: client, ttl: ttl}
+}
+
+func (s *RedisSessionStore) Load(ctx context.Context, chatID int64) (Session, error) {
+	data, err := s.client.GetBytes(ctx, redisSessionKey(chatID))
+	if err != nil {
+		if errors.Is(err, coreRedis.Nil) {
+			return Session{}, ErrSessionNotFound
+		}
+		return Session{}, fmt.Errorf("telegram: load session: %w", err)
+	}
+	var session Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return Session{}, fmt.Errorf("telegram: decode session: %w", err)
+	}
+	return session, nil
+}
+
+func (s *RedisSessionStore) Save(ctx context.Context, chatID int64, session Session) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("telegram: encode session: %w", err)
+	}
+	if err := s.client.SetWithExpiration(ctx, redisSessionKey(chatID), data, s.ttl); err != nil {
+		return fmt.Errorf("telegram: save session: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisSessionStore) Delete(ctx context.Context, chatID int64) error {
+	if err := s.client.Delete(ctx, redisSessionKey(chatID)); err != nil {
+		return fmt.Errorf("telegram: delete session: %w", err)
+	}
+	return nil
+}
+
+func redisSessionKey(chatID int64) string {
+	return redisSessionKeyPrefix + strconv.FormatInt(chatID, 10)
+}
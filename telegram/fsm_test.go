@@ -0,0 +1,149 @@
+package telegram
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFSM(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemorySessionStore(time.Hour)
+	now := time.Unix(1_700_000_000, 0)
+
+	var replies []string
+	reply := func(text string) HandlerFunc {
+		return func(context.Context, Update) error {
+			replies = append(replies, text)
+			return nil
+		}
+	}
+
+	fsm := NewFSM(store,
+		WithFSMTimeout(10*time.Minute, reply("expired")),
+		WithCancelCommand("/cancel", reply("cancelled")),
+	)
+	fsm.now = func() time.Time { return now }
+	fsm.On("ask_name", func(ctx context.Context, update Update) error {
+		conv := ConversationFrom(ctx)
+		conv.Set("name", update.Message.Text)
+		conv.Transition("ask_age")
+		return nil
+	})
+	fsm.On("ask_age", func(ctx context.Context, update Update) error {
+		conv := ConversationFrom(ctx)
+		replies = append(replies, conv.Get("name")+" "+update.Message.Text)
+		conv.Finish()
+		return nil
+	})
+
+	router := NewRouter()
+	router.Use(fsm.Middleware())
+	router.Command("register", func(ctx context.Context, _ Update) error {
+		ConversationFrom(ctx).Transition("ask_name")
+		return nil
+	})
+	router.Fallback(reply("fallback"))
+
+	handle := func(update Update) {
+		require.NoError(t, router.Handle(updateContext(ctx, update), update))
+	}
+	state := func() State {
+		session, err := store.Load(ctx, 100)
+		if err != nil {
+			require.ErrorIs(t, err, ErrSessionNotFound)
+		}
+		return session.State
+	}
+
+	handle(commandUpdate("/register", 1))
+	require.Equal(t, State("ask_name"), state())
+	handle(textUpdate("Alice"))
+	require.Equal(t, State("ask_age"), state())
+	handle(textUpdate("30"))
+	require.Equal(t, []string{"Alice 30"}, replies)
+	require.Empty(t, state(), "finished conversation is deleted")
+
+	handle(commandUpdate("/register", 1))
+	handle(commandUpdate("/cancel", 1))
+	require.Empty(t, state())
+
+	handle(commandUpdate("/register", 1))
+	now = now.Add(11 * time.Minute)
+	handle(textUpdate("Bob"))
+	require.Empty(t, state())
+	require.Equal(t, []string{"Alice 30", "cancelled", "expired", "fallback"}, replies)
+}
+
+func TestFSMWithoutFallback(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemorySessionStore(time.Hour)
+
+	var replies []string
+	fsm := NewFSM(store, WithCancelCommand("/cancel", func(context.Context, Update) error {
+		replies = append(replies, "cancelled")
+		return nil
+	}))
+	fsm.On("ask_name", func(ctx context.Context, update Update) error {
+		replies = append(replies, "name "+update.Message.Text)
+		ConversationFrom(ctx).Finish()
+		return nil
+	})
+
+	router := NewRouter()
+	router.Use(fsm.Middleware())
+	router.Command("register", func(ctx context.Context, _ Update) error {
+		ConversationFrom(ctx).Transition("ask_name")
+		return nil
+	})
+
+	handle := func(update Update) {
+		require.NoError(t, router.Handle(updateContext(ctx, update), update))
+	}
+
+	handle(commandUpdate("/register", 1))
+	handle(textUpdate("Alice"))
+	handle(commandUpdate("/register", 1))
+	handle(commandUpdate("/cancel", 1))
+	handle(textUpdate("Bob"))
+	require.Equal(t, []string{"name Alice", "cancelled"}, replies, "text without a route is ignored outside a conversation")
+}
+
+func TestFSMTimeoutCountsFromLastUpdate(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemorySessionStore(time.Hour)
+	now := time.Unix(1_700_000_000, 0)
+
+	expired := 0
+	fsm := NewFSM(store, WithFSMTimeout(10*time.Minute, func(context.Context, Update) error {
+		expired++
+		return nil
+	}))
+	fsm.now = func() time.Time { return now }
+	// Невалидный ответ оставляет диалог в том же состоянии
+	fsm.On("ask_age", func(context.Context, Update) error { return nil })
+
+	router := NewRouter()
+	router.Use(fsm.Middleware())
+	router.Command("register", func(ctx context.Context, _ Update) error {
+		ConversationFrom(ctx).Transition("ask_age")
+		return nil
+	})
+
+	handle := func(update Update) {
+		require.NoError(t, router.Handle(updateContext(ctx, update), update))
+	}
+
+	handle(commandUpdate("/register", 1))
+	for range 3 {
+		now = now.Add(6 * time.Minute)
+		handle(textUpdate("thirty"))
+	}
+	require.Zero(t, expired)
+	session, err := store.Load(ctx, 100)
+	require.NoError(t, err)
+	require.Equal(t, State("ask_age"), session.State)
+	require.Equal(t, now, session.UpdatedAt)
+}
@@ -0,0 +1,117 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// MaxCallbackDataLength - лимит Telegram на callback data кнопки в байтах
+const MaxCallbackDataLength = 64
+
+var (
+	ErrCallbackDataTooLong = fmt.Errorf("telegram: callback data exceeds %d bytes", MaxCallbackDataLength)
+	errCallbackSeparator   = errors.New("telegram: callback action and args must not contain ':'")
+)
+
+// InlineKeyboard - кнопки под сообщением
+type InlineKeyboard [][]InlineButton
+
+// InlineButton - кнопка с callback data или ссылкой
+type InlineButton struct {
+	Text string
+	Data string
+	URL  string
+}
+
+// CallbackButton создает кнопку, нажатие которой придет в Router.Callback(action).
+// Аргументы доступны в обработчике через CallbackArgs.
+func CallbackButton(text, action string, args ...string) (InlineButton, error) {
+	data, err := EncodeCallbackData(action, args...)
+	if err != nil {
+		return InlineButton{}, err
+	}
+	return InlineButton{Text: text, Data: data}, nil
+}
+
+func URLButton(text, url string) InlineButton {
+	return InlineButton{Text: text, URL: url}
+}
+
+// InlineRow собирает кнопки в ряд
+func InlineRow(buttons ...InlineButton) []InlineButton {
+	return buttons
+}
+
+func (k InlineKeyboard) markup() tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, len(k))
+	for i, row := range k {
+		rows[i] = make([]tgbotapi.InlineKeyboardButton, len(row))
+		for j, button := range row {
+			if button.URL != "" {
+				rows[i][j] = tgbotapi.NewInlineKeyboardButtonURL(button.Text, button.URL)
+			} else {
+				rows[i][j] = tgbotapi.NewInlineKeyboardButtonData(button.Text, button.Data)
+			}
+		}
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+func (k KeyBoard) markup() tgbotapi.ReplyKeyboardMarkup {
+	keyboard := make([][]tgbotapi.KeyboardButton, len(k))
+	for i, row := range k {
+		btnRow := make([]tgbotapi.KeyboardButton, len(row))
+		for j, label := range row {
+			btnRow[j] = tgbotapi.NewKeyboardButton(label)
+		}
+		keyboard[i] = btnRow
+	}
+	return tgbotapi.NewReplyKeyboard(keyboard...)
+}
+
+// EncodeCallbackData кодирует действие и аргументы в "action:arg1:arg2".
+// Telegram ограничивает callback data 64 байтами: передавайте идентификаторы, а не данные,
+// числа - через CallbackInt.
+func EncodeCallbackData(action string, args ...string) (string, error) {
+	if strings.Contains(action, callbackDataSep) {
+		return "", errCallbackSeparator
+	}
+	for _, arg := range args {
+		if strings.Contains(arg, callbackDataSep) {
+			return "", errCallbackSeparator
+		}
+	}
+	data := strings.Join(append([]string{action}, args...), callbackDataSep)
+	if len(data) > MaxCallbackDataLength {
+		return "", fmt.Errorf("%w: %q", ErrCallbackDataTooLong, data)
+	}
+	return data, nil
+}
+
+// DecodeCallbackData - обратная операция к EncodeCallbackData
+func DecodeCallbackData(data string) (action string, args []string) {
+	parts := strings.Split(data, callbackDataSep)
+	return parts[0], parts[1:]
+}
+
+// CallbackArgs возвращает аргументы callback query, закодированные EncodeCallbackData
+func CallbackArgs(update Update) []string {
+	if update.CallbackQuery == nil {
+		return nil
+	}
+	_, args := DecodeCallbackData(update.CallbackQuery.Data)
+	return args
+}
+
+// CallbackInt кодирует число в base36: ID 1234567890 занимает 6 байт вместо 10
+func CallbackInt(v int64) string {
+	return strconv.FormatInt(v, 36)
+}
+
+func ParseCallbackInt(s string) (int64, error) {
+	return strconv.ParseInt(s, 36, 64)
+}
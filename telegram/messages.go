@@ -0,0 +1,343 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	minMediaGroupSize = 2
+	maxMediaGroupSize = 10
+)
+
+var (
+	ErrEmptyText          = errors.New("telegram: empty message text")
+	ErrCaptionTooLong     = fmt.Errorf("telegram: caption exceeds %d characters", MaxCaptionLength)
+	ErrMessageTooLong     = fmt.Errorf("telegram: edited text exceeds %d characters", MaxMessageLength)
+	ErrInvalidMediaGroup  = fmt.Errorf("telegram: media group must contain %d-%d items", minMediaGroupSize, maxMediaGroupSize)
+	errEmptyInputFile     = errors.New("telegram: input file has no id, url or reader")
+	errUnsupportedMedia   = errors.New("telegram: unsupported media type")
+	errKeyboardsExclusive = errors.New("telegram: inline and reply keyboards are mutually exclusive")
+)
+
+// Messenger - отправка и изменение сообщений, общее для Bot и Client.
+// Все методы соблюдают лимиты Sender, проверяют ctx перед запросом и перестают ждать ответа при его отмене.
+type Messenger interface {
+	// SendMessage отправляет текст, длинный текст делится на несколько сообщений по MaxMessageLength.
+	// Reply-to применяется к первому сообщению, клавиатура - к последнему, оно же возвращается.
+	SendMessage(ctx context.Context, chatID int64, text string, opts ...SendOption) (Message, error)
+	// EditMessage меняет текст сообщения и, если передан WithInlineKeyboard, его кнопки
+	EditMessage(ctx context.Context, chatID int64, messageID int, text string, opts ...SendOption) error
+	// EditKeyboard меняет только кнопки сообщения. Пустая клавиатура убирает их.
+	EditKeyboard(ctx context.Context, chatID int64, messageID int, keyboard InlineKeyboard) error
+	DeleteMessage(ctx context.Context, chatID int64, messageID int) error
+	SendPhoto(ctx context.Context, chatID int64, photo InputFile, caption string, opts ...SendOption) (Message, error)
+	// SendMediaGroup отправляет альбом из 2-10 фото, видео или документов. Клавиатуры альбомы не поддерживают.
+	SendMediaGroup(ctx context.Context, chatID int64, media []Media, opts ...SendOption) ([]Message, error)
+}
+
+type sendOptions struct {
+	parseMode ParseMode
+	replyTo   int
+	inline    InlineKeyboard
+	reply     KeyBoard
+	silent    bool
+	noPreview bool
+}
+
+type SendOption func(*sendOptions)
+
+// WithParseMode включает разметку текста или подписи. Пользовательский ввод экранируйте через
+// EscapeMarkdownV2 или EscapeHTML.
+func WithParseMode(mode ParseMode) SendOption {
+	return func(o *sendOptions) {
+		o.parseMode = mode
+	}
+}
+
+// WithReplyTo отправляет сообщение ответом на messageID
+func WithReplyTo(messageID int) SendOption {
+	return func(o *sendOptions) {
+		o.replyTo = messageID
+	}
+}
+
+func WithInlineKeyboard(keyboard InlineKeyboard) SendOption {
+	return func(o *sendOptions) {
+		o.inline = keyboard
+	}
+}
+
+func WithReplyKeyboard(keyboard KeyBoard) SendOption {
+	return func(o *sendOptions) {
+		o.reply = keyboard
+	}
+}
+
+// WithoutNotification доставляет сообщение без звука
+func WithoutNotification() SendOption {
+	return func(o *sendOptions) {
+		o.silent = true
+	}
+}
+
+// WithoutPreview отключает превью ссылок
+func WithoutPreview() SendOption {
+	return func(o *sendOptions) {
+		o.noPreview = true
+	}
+}
+
+func newSendOptions(opts []SendOption) (sendOptions, error) {
+	var o sendOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.inline != nil && o.reply != nil {
+		return o, errKeyboardsExclusive
+	}
+	return o, nil
+}
+
+func (o sendOptions) markup() any {
+	switch {
+	case o.inline != nil:
+		return o.inline.markup()
+	case o.reply != nil:
+		return o.reply.markup()
+	default:
+		return nil
+	}
+}
+
+// InputFile - файл для отправки. Заполняется одно из полей: ID уже загруженного в Telegram файла,
+// URL, который Telegram скачает сам, или Reader с именем файла. Reader читается в память целиком,
+// чтобы запрос можно было повторить после 429.
+type InputFile struct {
+	ID     string
+	URL    string
+	Name   string
+	Reader io.Reader
+}
+
+func (f InputFile) requestData() (tgbotapi.RequestFileData, error) {
+	switch {
+	case f.ID != "":
+		return tgbotapi.FileID(f.ID), nil
+	case f.URL != "":
+		return tgbotapi.FileURL(f.URL), nil
+	case f.Reader != nil:
+		data, err := io.ReadAll(f.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("read file %q: %w", f.Name, err)
+		}
+		return tgbotapi.FileBytes{Name: f.Name, Bytes: data}, nil
+	default:
+		return nil, errEmptyInputFile
+	}
+}
+
+type MediaType string
+
+const (
+	MediaPhoto    MediaType = "photo"
+	MediaVideo    MediaType = "video"
+	MediaDocument MediaType = "document"
+)
+
+// Media - элемент альбома
+type Media struct {
+	Type    MediaType
+	File    InputFile
+	Caption string
+}
+
+func (m Media) input(parseMode ParseMode) (any, error) {
+	if utf8.RuneCountInString(m.Caption) > MaxCaptionLength {
+		return nil, ErrCaptionTooLong
+	}
+	file, err := m.File.requestData()
+	if err != nil {
+		return nil, err
+	}
+	switch m.Type {
+	case MediaPhoto:
+		media := tgbotapi.NewInputMediaPhoto(file)
+		media.Caption, media.ParseMode = m.Caption, string(parseMode)
+		return media, nil
+	case MediaVideo:
+		media := tgbotapi.NewInputMediaVideo(file)
+		media.Caption, media.ParseMode = m.Caption, string(parseMode)
+		return media, nil
+	case MediaDocument:
+		media := tgbotapi.NewInputMediaDocument(file)
+		media.Caption, media.ParseMode = m.Caption, string(parseMode)
+		return media, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", errUnsupportedMedia, m.Type)
+	}
+}
+
+// messenger реализует Messenger поверх tgbotapi. Запросы в чаты проходят через sender.
+type messenger struct {
+	api    *tgbotapi.BotAPI
+	sender *Sender
+}
+
+func (m *messenger) SendMessage(ctx context.Context, chatID int64, text string, opts ...SendOption) (Message, error) {
+	if text == "" {
+		return Message{}, ErrEmptyText
+	}
+	o, err := newSendOptions(opts)
+	if err != nil {
+		return Message{}, err
+	}
+
+	chunks := SplitText(text, MaxMessageLength)
+	var last Message
+	for i, chunk := range chunks {
+		msg := tgbotapi.NewMessage(chatID, chunk)
+		msg.ParseMode = string(o.parseMode)
+		msg.DisableNotification = o.silent
+		msg.DisableWebPagePreview = o.noPreview
+		if i == 0 {
+			msg.ReplyToMessageID = o.replyTo
+		}
+		if i == len(chunks)-1 {
+			msg.ReplyMarkup = o.markup()
+		}
+		if last, err = m.send(ctx, chatID, msg); err != nil {
+			return last, err
+		}
+	}
+	return last, nil
+}
+
+func (m *messenger) EditMessage(ctx context.Context, chatID int64, messageID int, text string, opts ...SendOption) error {
+	if text == "" {
+		return ErrEmptyText
+	}
+	if utf8.RuneCountInString(text) > MaxMessageLength {
+		return ErrMessageTooLong
+	}
+	o, err := newSendOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	edit.ParseMode = string(o.parseMode)
+	edit.DisableWebPagePreview = o.noPreview
+	if o.inline != nil {
+		markup := o.inline.markup()
+		edit.ReplyMarkup = &markup
+	}
+	return m.request(ctx, chatID, edit)
+}
+
+func (m *messenger) EditKeyboard(ctx context.Context, chatID int64, messageID int, keyboard InlineKeyboard) error {
+	markup := keyboard.markup()
+	// Пустая клавиатура должна уйти как inline_keyboard: [], иначе Telegram не уберет кнопки
+	if markup.InlineKeyboard == nil {
+		markup.InlineKeyboard = [][]tgbotapi.InlineKeyboardButton{}
+	}
+	return m.request(ctx, chatID, tgbotapi.NewEditMessageReplyMarkup(chatID, messageID, markup))
+}
+
+func (m *messenger) DeleteMessage(ctx context.Context, chatID int64, messageID int) error {
+	return m.request(ctx, chatID, tgbotapi.NewDeleteMessage(chatID, messageID))
+}
+
+func (m *messenger) SendPhoto(ctx context.Context, chatID int64, photo InputFile, caption string, opts ...SendOption) (Message, error) {
+	if utf8.RuneCountInString(caption) > MaxCaptionLength {
+		return Message{}, ErrCaptionTooLong
+	}
+	o, err := newSendOptions(opts)
+	if err != nil {
+		return Message{}, err
+	}
+	file, err := photo.requestData()
+	if err != nil {
+		return Message{}, err
+	}
+
+	msg := tgbotapi.NewPhoto(chatID, file)
+	msg.Caption = caption
+	msg.ParseMode = string(o.parseMode)
+	msg.ReplyToMessageID = o.replyTo
+	msg.DisableNotification = o.silent
+	msg.ReplyMarkup = o.markup()
+	return m.send(ctx, chatID, msg)
+}
+
+func (m *messenger) SendMediaGroup(ctx context.Context, chatID int64, media []Media, opts ...SendOption) ([]Message, error) {
+	if len(media) < minMediaGroupSize || len(media) > maxMediaGroupSize {
+		return nil, ErrInvalidMediaGroup
+	}
+	o, err := newSendOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]any, 0, len(media))
+	for _, item := range media {
+		input, err := item.input(o.parseMode)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, input)
+	}
+
+	group := tgbotapi.NewMediaGroup(chatID, files)
+	group.ReplyToMessageID = o.replyTo
+	group.DisableNotification = o.silent
+	return throttle(ctx, m.sender, chatID, func() ([]Message, error) {
+		return m.api.SendMediaGroup(group)
+	})
+}
+
+// send отправляет сообщение, которое Telegram возвращает в ответе
+func (m *messenger) send(ctx context.Context, chatID int64, c tgbotapi.Chattable) (Message, error) {
+	return throttle(ctx, m.sender, chatID, func() (Message, error) {
+		return m.api.Send(c)
+	})
+}
+
+// request выполняет запрос, в ответ на который Telegram возвращает true, например правку или удаление
+func (m *messenger) request(ctx context.Context, chatID int64, c tgbotapi.Chattable) error {
+	_, err := throttle(ctx, m.sender, chatID, func() (*tgbotapi.APIResponse, error) {
+		return m.api.Request(c)
+	})
+	return err
+}
+
+// call выполняет запрос tgbotapi с учетом ctx. tgbotapi не принимает контекст, поэтому при отмене
+// call сразу возвращает ctx.Err(), а запрос завершается в фоне: сообщение при этом может быть доставлено.
+func call[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	var zero T
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	type result struct {
+		value T
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		value, err := fn()
+		done <- result{value: value, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case r := <-done:
+		return r.value, r.err
+	}
+}
@@ -0,0 +1,31 @@
+package telegram
+
+import (
+	"sync"
+
+	coreMetrics "github.com/Rasikrr/core/metrics"
+)
+
+var (
+	once    sync.Once
+	metrics *Metrics
+)
+
+type Metrics struct {
+	messagesTotal  coreMetrics.CounterVec // {status}
+	throttledTotal coreMetrics.CounterVec // {reason}
+	queued         coreMetrics.Gauge
+	waitSec        coreMetrics.Histogram
+}
+
+func initTelegramMetrics() {
+	once.Do(func() {
+		metrics = &Metrics{
+			messagesTotal:  coreMetrics.NewCounterVec("telegram", "messages_total", "Outbound Telegram requests by result", []string{"status"}, nil),
+			throttledTotal: coreMetrics.NewCounterVec("telegram", "throttled_total", "Outbound Telegram requests delayed by rate limits", []string{"reason"}, nil),
+			queued:         coreMetrics.NewGauge("telegram", "queued", "Outbound Telegram requests waiting for a rate limit slot", nil),
+			waitSec: coreMetrics.NewHistogram("telegram", "wait_seconds", "Time spent waiting for a rate limit slot",
+				[]float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}, nil),
+		}
+	})
+}
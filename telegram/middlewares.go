@@ -0,0 +1,133 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	coreCtx "github.com/Rasikrr/core/context"
+	"github.com/Rasikrr/core/log"
+	"github.com/Rasikrr/core/recovery"
+	"github.com/Rasikrr/core/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/Rasikrr/core/telegram"
+
+// LoggingMiddleware пишет каждое обработанное обновление с маршрутом и длительностью, как access log в http.
+// Ошибку обработчика дополнительно логирует бот.
+func LoggingMiddleware() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, update Update) error {
+			start := time.Now()
+			err := next(ctx, update)
+
+			attrs := []log.Attr{
+				log.String("route", Route(ctx)),
+				log.Int("update_id", update.UpdateID),
+				log.Duration("duration", time.Since(start)),
+			}
+			if chatID, ok := ChatID(ctx); ok {
+				attrs = append(attrs, log.Int64("chat_id", chatID))
+			}
+			if err != nil {
+				attrs = append(attrs, log.Err(err))
+			}
+			log.Info(ctx, "telegram update handled", attrs...)
+			return err
+		}
+	}
+}
+
+// RecoveryMiddleware превращает панику обработчика в ошибку: лог со стеком, событие в Sentry и метрика,
+// как для HTTP и gRPC
+func RecoveryMiddleware() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, update Update) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					tags := map[string]string{"telegram.route": Route(ctx)}
+					if chatID, ok := ChatID(ctx); ok {
+						tags["telegram.chat_id"] = strconv.FormatInt(chatID, 10)
+					}
+					err = recovery.Handle(ctx, recovery.ComponentTelegram, r, tags)
+				}
+			}()
+			return next(ctx, update)
+		}
+	}
+}
+
+// TracingMiddleware создает span на обработку обновления и кладет trace ID в ctx для логов.
+// Без включенного трейсинга trace ID генерируется по update ID.
+func TracingMiddleware() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, update Update) error {
+			if !tracing.Enabled() {
+				return next(coreCtx.WithTraceID(ctx, fmt.Sprintf("telegram-%d", update.UpdateID)), update)
+			}
+
+			attrs := []attribute.KeyValue{
+				attribute.String("telegram.route", Route(ctx)),
+				attribute.Int("telegram.update_id", update.UpdateID),
+			}
+			if chatID, ok := ChatID(ctx); ok {
+				attrs = append(attrs, attribute.Int64("telegram.chat_id", chatID))
+			}
+			ctx, span := tracing.GetTracer(tracerName).Start(ctx, "telegram.handle "+Route(ctx),
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(attrs...),
+			)
+			defer span.End()
+			ctx = coreCtx.WithTraceID(ctx, span.SpanContext().TraceID().String())
+
+			err := next(ctx, update)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			return err
+		}
+	}
+}
+
+// RateLimitMiddleware пропускает не больше rate обновлений за per из одного чата.
+// Лишние обновления отбрасываются, чтобы один чат не занимал все слоты семафора бота.
+// rate и per должны быть положительными, иначе это ошибка настройки и функция паникует при создании роутера.
+func RateLimitMiddleware(rate int, per time.Duration) Middleware {
+	if rate <= 0 || per <= 0 {
+		panic(fmt.Sprintf("telegram: RateLimitMiddleware requires rate > 0 and per > 0, got rate=%d per=%s", rate, per))
+	}
+	limiter := newChatLimiter(rate, per)
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, update Update) error {
+			chatID, ok := ChatID(ctx)
+			if ok && !limiter.Allow(chatID) {
+				log.Debug(ctx, "telegram update dropped by rate limit", log.Int64("chat_id", chatID))
+				return nil
+			}
+			return next(ctx, update)
+		}
+	}
+}
+
+// AdminOnly пропускает обновления только от пользователей из списка. Остальные игнорируются.
+func AdminOnly(userIDs ...int64) Middleware {
+	admins := make(map[int64]struct{}, len(userIDs))
+	for _, id := range userIDs {
+		admins[id] = struct{}{}
+	}
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, update Update) error {
+			userID, _ := UserID(ctx)
+			if _, ok := admins[userID]; !ok {
+				log.Warn(ctx, "telegram update from non-admin rejected", log.String("route", Route(ctx)))
+				return nil
+			}
+			return next(ctx, update)
+		}
+	}
+}
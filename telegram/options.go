@@ -0,0 +1,25 @@
+package telegram
+
+type options struct {
+	sender *Sender
+}
+
+type Option func(*options)
+
+// WithSender задает общий Sender, например для Bot и Client с одним токеном
+func WithSender(sender *Sender) Option {
+	return func(o *options) {
+		o.sender = sender
+	}
+}
+
+func newOptions(opts []Option, senderCfg SenderConfig) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.sender == nil {
+		o.sender = NewSender(senderCfg)
+	}
+	return o
+}
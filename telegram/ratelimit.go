@@ -0,0 +1,66 @@
+package telegram
+
+import (
+	"sync"
+	"time"
+)
+
+// chatLimiter - token bucket на каждый чат: burst сообщений сразу, дальше не чаще rate за per
+type chatLimiter struct {
+	mu       sync.Mutex
+	burst    float64
+	perToken time.Duration
+	buckets  map[int64]*bucket
+	swept    time.Time
+	now      func() time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newChatLimiter(rate int, per time.Duration) *chatLimiter {
+	return &chatLimiter{
+		burst:    float64(rate),
+		perToken: per / time.Duration(rate),
+		buckets:  make(map[int64]*bucket),
+		now:      time.Now,
+	}
+}
+
+// Allow забирает токен чата, если он есть
+func (l *chatLimiter) Allow(chatID int64) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[chatID]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[chatID] = b
+	}
+	b.tokens = min(l.burst, b.tokens+float64(now.Sub(b.last))/float64(l.perToken))
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// sweep удаляет полностью восстановившиеся бакеты, чтобы map не росла с числом чатов
+func (l *chatLimiter) sweep(now time.Time) {
+	full := l.perToken * time.Duration(l.burst)
+	if now.Sub(l.swept) < full {
+		return
+	}
+	l.swept = now
+	for id, b := range l.buckets {
+		if now.Sub(b.last) >= full {
+			delete(l.buckets, id)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package telegram
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+const (
+	routeFallback       = "fallback"
+	routeUnmatched      = "unmatched"
+	callbackDataSep     = ":"
+	callbackRoutePrefix = "callback:"
+	regexRoutePrefix    = "regex:"
+)
+
+// HandlerFunc обрабатывает обновление. ctx содержит chat ID (ChatID) и user ID (UserID, context.UserID).
+type HandlerFunc func(ctx context.Context, update Update) error
+
+// Middleware оборачивает обработчик, как middleware в http
+type Middleware func(next HandlerFunc) HandlerFunc
+
+type route struct {
+	name    string
+	handler HandlerFunc
+}
+
+type regexRoute struct {
+	route
+	pattern *regexp.Regexp
+}
+
+// Router выбирает обработчик обновления: команды, затем регулярные выражения по тексту сообщения,
+// callback query по действию, иначе fallback. Middleware из Use применяются ко всем маршрутам,
+// middleware маршрута - только к нему, после общих.
+//
+// Использование:
+//
+//	router := telegram.NewRouter()
+//	router.Use(telegram.RecoveryMiddleware(), telegram.LoggingMiddleware(), telegram.RateLimitMiddleware(1, time.Second))
+//	router.Command("start", start)
+//	router.Command("ban", ban, telegram.AdminOnly(adminIDs...))
+//	router.Callback("buy", buy) // data "buy" или "buy:42"
+//	router.Fallback(help)
+//	bot.SetRouter(router)
+type Router struct {
+	username    string
+	middlewares []Middleware
+	commands    map[string]route
+	regexps     []regexRoute
+	callbacks   map[string]route
+	fallback    *route
+}
+
+func NewRouter() *Router {
+	return &Router{
+		commands:  make(map[string]route),
+		callbacks: make(map[string]route),
+	}
+}
+
+// Use добавляет middleware ко всем маршрутам. Первый добавленный выполняется первым.
+func (r *Router) Use(middlewares ...Middleware) {
+	r.middlewares = append(r.middlewares, middlewares...)
+}
+
+// Command регистрирует обработчик команды. name можно передавать как "start", так и "/start".
+// Аргументы команды доступны через update.Message.CommandArguments().
+func (r *Router) Command(name string, handler HandlerFunc, middlewares ...Middleware) {
+	name = strings.TrimPrefix(name, "/")
+	r.commands[name] = route{name: "/" + name, handler: chain(handler, middlewares)}
+}
+
+// Regex регистрирует обработчик текстовых сообщений, подходящих под pattern.
+// Маршруты проверяются в порядке регистрации, группы доступны через Matches(ctx).
+func (r *Router) Regex(pattern *regexp.Regexp, handler HandlerFunc, middlewares ...Middleware) {
+	r.regexps = append(r.regexps, regexRoute{
+		route:   route{name: regexRoutePrefix + pattern.String(), handler: chain(handler, middlewares)},
+		pattern: pattern,
+	})
+}
+
+// Callback регистрирует обработчик callback query с данными "action" или "action:args", см. EncodeCallbackData
+func (r *Router) Callback(action string, handler HandlerFunc, middlewares ...Middleware) {
+	r.callbacks[action] = route{name: callbackRoutePrefix + action, handler: chain(handler, middlewares)}
+}
+
+// Fallback регистрирует обработчик обновлений, для которых не нашлось маршрута
+func (r *Router) Fallback(handler HandlerFunc, middlewares ...Middleware) {
+	r.fallback = &route{name: routeFallback, handler: chain(handler, middlewares)}
+}
+
+// Handle выбирает маршрут и вызывает обработчик через цепочку middleware.
+// Обновление без маршрута и без fallback проходит только через общие middleware (например FSM.Middleware)
+// и дальше игнорируется, Route для него "unmatched". Команды другим ботам игнорируются целиком.
+func (r *Router) Handle(ctx context.Context, update Update) error {
+	// В группах команда может быть адресована другому боту: /start@other_bot
+	if msg := update.Message; msg != nil && msg.IsCommand() && !r.addressedToUs(msg) {
+		return nil
+	}
+	rt, matches, ok := r.match(update)
+	if !ok {
+		rt = route{name: routeUnmatched, handler: ignore}
+	}
+	ctx = context.WithValue(ctx, ctxKeyRoute, rt.name)
+	if matches != nil {
+		ctx = context.WithValue(ctx, ctxKeyMatches, matches)
+	}
+	return chain(rt.handler, r.middlewares)(ctx, update)
+}
+
+func (r *Router) match(update Update) (route, []string, bool) {
+	switch {
+	case update.Message != nil:
+		if update.Message.IsCommand() {
+			if rt, ok := r.commands[update.Message.Command()]; ok {
+				return rt, nil, true
+			}
+		}
+		text := update.Message.Text
+		if text == "" {
+			text = update.Message.Caption
+		}
+		for _, rr := range r.regexps {
+			if matches := rr.pattern.FindStringSubmatch(text); matches != nil {
+				return rr.route, matches, true
+			}
+		}
+	case update.CallbackQuery != nil:
+		action, _, _ := strings.Cut(update.CallbackQuery.Data, callbackDataSep)
+		if rt, ok := r.callbacks[action]; ok {
+			return rt, nil, true
+		}
+	}
+	if r.fallback != nil {
+		return *r.fallback, nil, true
+	}
+	return route{}, nil, false
+}
+
+func ignore(context.Context, Update) error {
+	return nil
+}
+
+func (r *Router) addressedToUs(msg *Message) bool {
+	_, to, found := strings.Cut(msg.CommandWithAt(), "@")
+	return !found || r.username == "" || strings.EqualFold(to, r.username)
+}
+
+// chain оборачивает handler в middlewares так, что первый middleware выполняется первым
+func chain(handler HandlerFunc, middlewares []Middleware) HandlerFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
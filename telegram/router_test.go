@@ -0,0 +1,130 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/Rasikrr/core/recovery"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func commandUpdate(text string, userID int64) Update {
+	return Update{Message: &tgbotapi.Message{
+		Text:     text,
+		Chat:     &tgbotapi.Chat{ID: 100},
+		From:     &tgbotapi.User{ID: userID},
+		Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(text)}},
+	}}
+}
+
+func textUpdate(text string) Update {
+	return Update{Message: &tgbotapi.Message{Text: text, Chat: &tgbotapi.Chat{ID: 100}, From: &tgbotapi.User{ID: 1}}}
+}
+
+func callbackUpdate(data string) Update {
+	return Update{CallbackQuery: &tgbotapi.CallbackQuery{
+		Data:    data,
+		From:    &tgbotapi.User{ID: 1},
+		Message: &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: 100}},
+	}}
+}
+
+func TestRouter(t *testing.T) {
+	var got []string
+	record := func(ctx context.Context, _ Update) error {
+		got = append(got, Route(ctx))
+		return nil
+	}
+
+	router := NewRouter()
+	router.username = "core_bot"
+	router.Command("/start", func(ctx context.Context, update Update) error {
+		chatID, _ := ChatID(ctx)
+		userID, _ := UserID(ctx)
+		require.Equal(t, int64(100), chatID)
+		require.Equal(t, int64(7), userID)
+		return record(ctx, update)
+	})
+	router.Regex(regexp.MustCompile(`^order (\d+)$`), func(ctx context.Context, update Update) error {
+		require.Equal(t, []string{"order 42", "42"}, Matches(ctx))
+		return record(ctx, update)
+	})
+	router.Callback("buy", record)
+	router.Fallback(record)
+
+	ctx := context.Background()
+	for _, update := range []Update{
+		commandUpdate("/start", 7),
+		commandUpdate("/start@core_bot", 7),
+		commandUpdate("/start@other_bot", 7),
+		textUpdate("order 42"),
+		callbackUpdate("buy:42"),
+		callbackUpdate("sell"),
+		textUpdate("hello"),
+	} {
+		require.NoError(t, router.Handle(updateContext(ctx, update), update))
+	}
+	require.Equal(t, []string{"/start", "/start", "regex:^order (\\d+)$", "callback:buy", "fallback", "fallback"}, got)
+}
+
+func TestRouterMiddlewares(t *testing.T) {
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(ctx context.Context, update Update) error {
+				order = append(order, name)
+				return next(ctx, update)
+			}
+		}
+	}
+
+	router := NewRouter()
+	router.Use(RecoveryMiddleware(), trace("global"))
+	router.Command("ban", func(context.Context, Update) error {
+		order = append(order, "handler")
+		return nil
+	}, trace("route"), AdminOnly(1))
+	router.Command("panic", func(context.Context, Update) error {
+		panic("boom")
+	})
+
+	ctx := context.Background()
+	handle := func(update Update) error {
+		return router.Handle(updateContext(ctx, update), update)
+	}
+
+	require.NoError(t, handle(commandUpdate("/ban", 1)))
+	require.Equal(t, []string{"global", "route", "handler"}, order)
+
+	order = nil
+	require.NoError(t, handle(commandUpdate("/ban", 2)))
+	require.Equal(t, []string{"global", "route"}, order, "non-admin must not reach the handler")
+
+	var perr *recovery.PanicError
+	require.True(t, errors.As(handle(commandUpdate("/panic", 1)), &perr))
+}
+
+func TestChatLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	limiter := newChatLimiter(2, time.Second)
+	limiter.now = func() time.Time { return now }
+
+	require.True(t, limiter.Allow(1))
+	require.True(t, limiter.Allow(1))
+	require.False(t, limiter.Allow(1))
+	require.True(t, limiter.Allow(2), "chats are limited independently")
+
+	now = now.Add(500 * time.Millisecond)
+	require.True(t, limiter.Allow(1))
+	require.False(t, limiter.Allow(1))
+}
+
+func TestRateLimitMiddlewareRejectsInvalidLimits(t *testing.T) {
+	require.Panics(t, func() { RateLimitMiddleware(0, time.Second) })
+	require.Panics(t, func() { RateLimitMiddleware(-1, time.Second) })
+	require.Panics(t, func() { RateLimitMiddleware(1, 0) })
+}
@@ -0,0 +1,290 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Rasikrr/core/log"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	defaultGlobalRate     = 30
+	defaultChatRate       = 1
+	defaultGroupRate      = 20
+	defaultSenderQueue    = 1000
+	defaultSenderRetries  = 3
+	statusTooManyRequests = 429
+)
+
+// ErrRateLimited возвращается, если Telegram продолжает отвечать 429 после всех повторов
+var ErrRateLimited = errors.New("telegram: rate limited")
+
+// SenderConfig - лимиты исходящих сообщений. Значения по умолчанию соответствуют лимитам Bot API:
+// около 30 сообщений в секунду всего, 1 в секунду в личный чат и 20 в минуту в группу.
+type SenderConfig struct {
+	// GlobalRate - сообщений в секунду на весь бот
+	GlobalRate int `yaml:"global_rate"`
+	// ChatRate - сообщений в секунду в один личный чат
+	ChatRate int `yaml:"chat_rate"`
+	// GroupRate - сообщений в минуту в одну группу или канал
+	GroupRate int `yaml:"group_rate"`
+	// QueueSize - сколько сообщений может ждать отправки. Когда очередь заполнена, отправка блокируется до отмены ctx.
+	QueueSize int `yaml:"queue_size"`
+	// MaxRetries - сколько раз повторять запрос после 429 с retry_after
+	MaxRetries int `yaml:"max_retries"`
+}
+
+func (c SenderConfig) validate() error {
+	if c.GlobalRate < 0 || c.ChatRate < 0 || c.GroupRate < 0 || c.QueueSize < 0 || c.MaxRetries < 0 {
+		return fmt.Errorf("sender limits must not be negative: %w", errConfigRequired)
+	}
+	return nil
+}
+
+func (c SenderConfig) withDefaults() SenderConfig {
+	if c.GlobalRate == 0 {
+		c.GlobalRate = defaultGlobalRate
+	}
+	if c.ChatRate == 0 {
+		c.ChatRate = defaultChatRate
+	}
+	if c.GroupRate == 0 {
+		c.GroupRate = defaultGroupRate
+	}
+	if c.QueueSize == 0 {
+		c.QueueSize = defaultSenderQueue
+	}
+	if c.MaxRetries == 0 {
+		c.MaxRetries = defaultSenderRetries
+	}
+	return c
+}
+
+// Sender распределяет исходящие запросы к Bot API во времени, чтобы не получать 429 Too Many Requests,
+// и повторяет запрос через retry_after, если Telegram все же ответил 429.
+// Лимиты Telegram действуют на токен, поэтому Bot и Client одного токена должны использовать один Sender (WithSender).
+type Sender struct {
+	cfg      SenderConfig
+	schedule *schedule
+	slots    chan struct{}
+}
+
+func NewSender(cfg SenderConfig) *Sender {
+	initTelegramMetrics()
+	cfg = cfg.withDefaults()
+	return &Sender{
+		cfg:      cfg,
+		schedule: newSchedule(cfg),
+		slots:    make(chan struct{}, cfg.QueueSize),
+	}
+}
+
+// do выполняет запрос в чат chatID с учетом лимитов. chatID 0 - запрос вне чата, учитывается только общий лимит.
+func (s *Sender) do(ctx context.Context, chatID int64, fn func() error) error {
+	if err := s.enqueue(ctx); err != nil {
+		metrics.messagesTotal.WithLabelValues("failed").Inc()
+		return err
+	}
+	defer s.dequeue()
+
+	for attempt := 0; ; attempt++ {
+		if err := s.wait(ctx, chatID); err != nil {
+			metrics.messagesTotal.WithLabelValues("failed").Inc()
+			return err
+		}
+
+		err := fn()
+		retryAfter, limited := retryAfter(err)
+		switch {
+		case !limited && err != nil:
+			metrics.messagesTotal.WithLabelValues("failed").Inc()
+			return err
+		case !limited:
+			metrics.messagesTotal.WithLabelValues("sent").Inc()
+			return nil
+		}
+
+		metrics.throttledTotal.WithLabelValues("retry_after").Inc()
+		s.schedule.block(chatID, retryAfter)
+		if attempt >= s.cfg.MaxRetries {
+			metrics.messagesTotal.WithLabelValues("failed").Inc()
+			return fmt.Errorf("%w: %w", ErrRateLimited, err)
+		}
+		log.Warn(ctx, "telegram: too many requests, retrying",
+			log.Int64("chat_id", chatID),
+			log.Duration("retry_after", retryAfter),
+			log.Int("attempt", attempt+1),
+		)
+	}
+}
+
+// enqueue занимает место в очереди. Заполненная очередь блокирует отправителя: так всплеск уведомлений
+// замедляет вызывающий код вместо неограниченного роста памяти.
+func (s *Sender) enqueue(ctx context.Context) error {
+	select {
+	case s.slots <- struct{}{}:
+		metrics.queued.Inc()
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Sender) dequeue() {
+	<-s.slots
+	metrics.queued.Dec()
+}
+
+// wait ждет слот чата, затем общий слот. Общий слот занимается только когда подошла очередь чата,
+// поэтому медленный чат (например группа с лимитом 20 в минуту) не задерживает остальные.
+func (s *Sender) wait(ctx context.Context, chatID int64) error {
+	chatDelay := s.schedule.reserveChat(chatID)
+	if err := sleep(ctx, chatDelay); err != nil {
+		return err
+	}
+	delay := s.schedule.reserve()
+	if err := sleep(ctx, delay); err != nil {
+		return err
+	}
+	if total := chatDelay + delay; total > 0 {
+		metrics.throttledTotal.WithLabelValues("rate_limit").Inc()
+		metrics.waitSec.Observe(total.Seconds())
+	}
+	return nil
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// retryAfter достает retry_after из ответа 429
+func retryAfter(err error) (time.Duration, bool) {
+	var apiErr *tgbotapi.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != statusTooManyRequests {
+		return 0, false
+	}
+	return time.Duration(max(apiErr.RetryAfter, 1)) * time.Second, true
+}
+
+// throttle выполняет запрос tgbotapi через Sender. Без Sender запрос выполняется сразу.
+func throttle[T any](ctx context.Context, s *Sender, chatID int64, fn func() (T, error)) (T, error) {
+	if s == nil {
+		return call(ctx, fn)
+	}
+	var value T
+	err := s.do(ctx, chatID, func() error {
+		var err error
+		value, err = call(ctx, fn)
+		return err
+	})
+	return value, err
+}
+
+// schedule раздает слоты отправки: запросы одного чата и все запросы бота идут не чаще заданных интервалов.
+// Слоты чата и общие слоты резервируются независимо и заранее, поэтому ожидающие отправители
+// обслуживаются в порядке резервирования.
+type schedule struct {
+	mu            sync.Mutex
+	interval      time.Duration
+	chatInterval  time.Duration
+	groupInterval time.Duration
+	next          time.Time
+	chats         map[int64]time.Time
+	swept         time.Time
+	now           func() time.Time
+}
+
+func newSchedule(cfg SenderConfig) *schedule {
+	return &schedule{
+		interval:      time.Second / time.Duration(cfg.GlobalRate),
+		chatInterval:  time.Second / time.Duration(cfg.ChatRate),
+		groupInterval: time.Minute / time.Duration(cfg.GroupRate),
+		chats:         make(map[int64]time.Time),
+		now:           time.Now,
+	}
+}
+
+// reserve занимает ближайший общий слот и возвращает, сколько до него ждать
+func (s *schedule) reserve() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	at := now
+	if s.next.After(at) {
+		at = s.next
+	}
+	s.next = at.Add(s.interval)
+	return at.Sub(now)
+}
+
+// reserveChat занимает ближайший слот чата и возвращает, сколько до него ждать. Для chatID 0 ждать не нужно.
+func (s *schedule) reserveChat(chatID int64) time.Duration {
+	if chatID == 0 {
+		return 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	at := now
+	if next := s.chats[chatID]; next.After(at) {
+		at = next
+	}
+	s.chats[chatID] = at.Add(s.chatSpacing(chatID))
+	return at.Sub(now)
+}
+
+// block откладывает следующие запросы в чат на d. Для chatID 0 пауза действует на весь бот.
+func (s *schedule) block(chatID int64, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	until := s.now().Add(d)
+	if chatID == 0 {
+		if until.After(s.next) {
+			s.next = until
+		}
+		return
+	}
+	if until.After(s.chats[chatID]) {
+		s.chats[chatID] = until
+	}
+}
+
+// chatSpacing - интервал между сообщениями в чат. ID групп и каналов в Bot API отрицательные.
+func (s *schedule) chatSpacing(chatID int64) time.Duration {
+	if chatID < 0 {
+		return s.groupInterval
+	}
+	return s.chatInterval
+}
+
+// sweep удаляет чаты, слот которых уже прошел, чтобы map не росла с числом чатов
+func (s *schedule) sweep(now time.Time) {
+	if now.Sub(s.swept) < time.Minute {
+		return
+	}
+	s.swept = now
+	for id, next := range s.chats {
+		if !next.After(now) {
+			delete(s.chats, id)
+		}
+	}
+}
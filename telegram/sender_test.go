@@ -0,0 +1,111 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule(t *testing.T) {
+	now := time.Unix(0, 0)
+	s := newSchedule(SenderConfig{GlobalRate: 10, ChatRate: 1, GroupRate: 20})
+	s.now = func() time.Time { return now }
+
+	require.Zero(t, s.reserveChat(1))
+	require.Zero(t, s.reserve())
+	require.Zero(t, s.reserveChat(2))
+	require.Equal(t, 100*time.Millisecond, s.reserve(), "global rate spaces all chats")
+	require.Equal(t, time.Second, s.reserveChat(1), "private chat gets one message per second")
+
+	require.Zero(t, s.reserveChat(-100))
+	require.Equal(t, 3*time.Second, s.reserveChat(-100), "group gets 20 messages per minute")
+	require.Zero(t, s.reserveChat(0), "requests outside a chat wait only for the global slot")
+
+	s.block(2, 10*time.Second)
+	require.Equal(t, 10*time.Second, s.reserveChat(2))
+	s.block(0, time.Second)
+	require.Equal(t, time.Second, s.reserve())
+}
+
+func TestScheduleSlowChatDoesNotDelayOthers(t *testing.T) {
+	now := time.Unix(0, 0)
+	s := newSchedule(SenderConfig{GlobalRate: 10, ChatRate: 1, GroupRate: 20})
+	s.now = func() time.Time { return now }
+
+	for range 5 {
+		s.reserveChat(-100)
+	}
+	require.Equal(t, 15*time.Second, s.reserveChat(-100), "group is throttled")
+
+	require.Zero(t, s.reserveChat(7))
+	require.Zero(t, s.reserve(), "queued group messages do not hold global slots")
+	require.Zero(t, s.reserveChat(8))
+	require.Equal(t, 100*time.Millisecond, s.reserve())
+}
+
+func TestSenderRetryAfter(t *testing.T) {
+	sender := NewSender(SenderConfig{MaxRetries: 1})
+	ctx := context.Background()
+
+	attempts := 0
+	start := time.Now()
+	msg, err := throttle(ctx, sender, 42, func() (Message, error) {
+		attempts++
+		if attempts == 1 {
+			return Message{}, &tgbotapi.Error{Code: 429, Message: "Too Many Requests", ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 1}}
+		}
+		return Message{MessageID: 7}, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 7, msg.MessageID)
+	require.Equal(t, 2, attempts)
+	require.GreaterOrEqual(t, time.Since(start), time.Second, "retry waits for retry_after")
+
+	_, err = throttle(ctx, sender, 43, func() (Message, error) {
+		return Message{}, &tgbotapi.Error{Code: 429, ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 0}}
+	})
+	require.ErrorIs(t, err, ErrRateLimited)
+
+	boom := errors.New("boom")
+	_, err = throttle(ctx, sender, 44, func() (Message, error) { return Message{}, boom })
+	require.ErrorIs(t, err, boom)
+}
+
+func TestSenderBackpressure(t *testing.T) {
+	sender := NewSender(SenderConfig{QueueSize: 1})
+	require.NoError(t, sender.enqueue(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, sender.enqueue(ctx), context.DeadlineExceeded, "full queue blocks the caller")
+
+	sender.dequeue()
+	require.NoError(t, sender.enqueue(context.Background()))
+}
+
+func TestSenderRetryReplaysUpload(t *testing.T) {
+	file, err := InputFile{Name: "report.csv", Reader: strings.NewReader("a,b\n1,2\n")}.requestData()
+	require.NoError(t, err)
+
+	sender := NewSender(SenderConfig{MaxRetries: 1})
+	var bodies []string
+	_, err = throttle(context.Background(), sender, 42, func() (Message, error) {
+		_, reader, err := file.UploadData()
+		require.NoError(t, err)
+		body, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		bodies = append(bodies, string(body))
+		if len(bodies) == 1 {
+			return Message{}, &tgbotapi.Error{Code: 429, ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 1}}
+		}
+		return Message{}, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a,b\n1,2\n", "a,b\n1,2\n"}, bodies, "retry uploads the whole file again")
+}
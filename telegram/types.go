@@ -0,0 +1,7 @@
+package telegram
+
+import tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+type Update = tgbotapi.Update
+type Message = tgbotapi.Message
+type CallbackQuery = tgbotapi.CallbackQuery
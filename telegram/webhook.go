@@ -0,0 +1,77 @@
+package telegram
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Rasikrr/core/log"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	secretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+	maxUpdateBytes    = 1 << 20
+)
+
+// WebhookHandler принимает обновления от Telegram и передает их тому же обработчику, что и long polling,
+// с тем же ограничением параллельности. Запросы без верного X-Telegram-Bot-Api-Secret-Token отклоняются.
+// До вызова Start обработчик отвечает 503, и Telegram повторит доставку.
+func (b *bot) WebhookHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		token := r.Header.Get(secretTokenHeader)
+		if subtle.ConstantTimeCompare([]byte(token), []byte(b.webhook.SecretToken)) != 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var update Update
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxUpdateBytes)).Decode(&update); err != nil {
+			log.Warn(r.Context(), "telegram: invalid webhook update", log.Err(err))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		select {
+		case <-b.started:
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		// Обработка идет в контексте бота: контекст запроса отменится сразу после ответа
+		b.dispatch(b.ctx, update)
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// setWebhook регистрирует webhook. tgbotapi не поддерживает secret_token, поэтому запрос собирается вручную.
+func (b *bot) setWebhook() error {
+	params := tgbotapi.Params{}
+	params.AddNonEmpty("url", b.webhook.URL)
+	params.AddNonEmpty("secret_token", b.webhook.SecretToken)
+	params.AddNonZero("max_connections", b.webhook.MaxConnections)
+	params.AddBool("drop_pending_updates", b.webhook.DropPendingUpdates)
+	if len(b.webhook.AllowedUpdates) > 0 {
+		if err := params.AddInterface("allowed_updates", b.webhook.AllowedUpdates); err != nil {
+			return err
+		}
+	}
+	if _, err := b.client.MakeRequest("setWebhook", params); err != nil {
+		return fmt.Errorf("telegram: set webhook: %w", err)
+	}
+	return nil
+}
+
+func (b *bot) deleteWebhook() error {
+	params := tgbotapi.Params{}
+	params.AddBool("drop_pending_updates", false)
+	if _, err := b.client.MakeRequest("deleteWebhook", params); err != nil {
+		return fmt.Errorf("telegram: delete webhook: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package telegram
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookHandler(t *testing.T) {
+	handled := make(chan int64, 1)
+	router := NewRouter()
+	router.Command("start", func(ctx context.Context, _ Update) error {
+		chatID, _ := ChatID(ctx)
+		handled <- chatID
+		return nil
+	})
+
+	b := &bot{
+		client:  &tgbotapi.BotAPI{Self: tgbotapi.User{UserName: "core_bot"}},
+		webhook: WebhookConfig{URL: "https://bot.example.com/tg", SecretToken: "s3cret"},
+		started: make(chan struct{}),
+	}
+	b.SetRouter(router)
+	handler := b.WebhookHandler()
+
+	body := `{"update_id":1,"message":{"message_id":1,"text":"/start","chat":{"id":42},"from":{"id":7},` +
+		`"entities":[{"type":"bot_command","offset":0,"length":6}]}}`
+	send := func(secret string) int {
+		req := httptest.NewRequest(http.MethodPost, "/tg", strings.NewReader(body))
+		req.Header.Set(secretTokenHeader, secret)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	require.Equal(t, http.StatusUnauthorized, send("wrong"))
+	require.Equal(t, http.StatusServiceUnavailable, send("s3cret"), "updates before Start are rejected so Telegram retries")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	b.ctx = ctx
+	close(b.started)
+
+	require.Equal(t, http.StatusOK, send("s3cret"))
+	select {
+	case chatID := <-handled:
+		require.Equal(t, int64(42), chatID)
+	case <-time.After(time.Second):
+		t.Fatal("update was not dispatched")
+	}
+}
+
+func TestWebhookConfigValidate(t *testing.T) {
+	cfg := Config{Required: true, Token: "token", Webhook: WebhookConfig{URL: "https://bot.example.com/tg", SecretToken: "s3cret"}}
+	require.NoError(t, cfg.Validate())
+	require.Equal(t, "/tg", cfg.Webhook.RoutePath())
+
+	cfg.Webhook.SecretToken = "not allowed!"
+	require.ErrorIs(t, cfg.Validate(), errConfigRequired)
+
+	cfg.Webhook = WebhookConfig{URL: "http://bot.example.com/tg", SecretToken: "s3cret"}
+	require.ErrorIs(t, cfg.Validate(), errConfigRequired)
+}
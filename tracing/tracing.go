@@ -47,14 +47,7 @@ func Init(ctx context.Context, cfg Config, appName, env string) error {
 
 		// 2. Создаём ресурс (service name + метаданные)
 		var res *resource.Resource
-		res, err = resource.Merge(
-			resource.Default(),
-			resource.NewWithAttributes(
-				semconv.SchemaURL,
-				semconv.ServiceNameKey.String(appName),
-				semconv.DeploymentEnvironmentNameKey.String(env),
-			),
-		)
+		res, err = NewResource(appName, env)
 		if err != nil {
 			return
 		}
@@ -72,6 +65,19 @@ func Init(ctx context.Context, cfg Config, appName, env string) error {
 	return err
 }
 
+// NewResource описывает сервис для OTel. Экспорт логов использует тот же ресурс, что и трейсы,
+// чтобы их можно было связать в бэкенде.
+func NewResource(appName, env string) (*resource.Resource, error) {
+	return resource.Merge(
+		resource.Default(),
+		resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceNameKey.String(appName),
+			semconv.DeploymentEnvironmentNameKey.String(env),
+		),
+	)
+}
+
 func Enabled() bool {
 	return enabled.Load()
 }